@@ -0,0 +1,210 @@
+package llmhub
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StreamAccumulator 合并流式响应块，重建完整的 ChatCompletionResponse
+// 使用示例：
+//
+//	acc := llmhub.NewStreamAccumulator()
+//	for {
+//	    chunk, err := stream.Recv()
+//	    if err == io.EOF {
+//	        break
+//	    }
+//	    if err != nil {
+//	        return err
+//	    }
+//	    acc.Add(chunk)
+//	}
+//	resp := acc.Response()
+type StreamAccumulator struct {
+	id                string
+	model             string
+	created           int64
+	systemFingerprint string
+	usage             Usage
+	choices           map[int]*accumulatedChoice
+}
+
+type accumulatedChoice struct {
+	role         string
+	content      strings.Builder
+	functionCall *FunctionCall
+	toolCalls    []*ToolCall
+	toolIndexes  map[int]int
+	citations    []Citation
+	reasoning    strings.Builder
+	thinking     []ThinkingBlock
+	finishReason string
+}
+
+// NewStreamAccumulator 创建流式响应合并器
+func NewStreamAccumulator() *StreamAccumulator {
+	return &StreamAccumulator{
+		choices: make(map[int]*accumulatedChoice),
+	}
+}
+
+// Add 合并一个流式响应块
+func (a *StreamAccumulator) Add(chunk *ChatCompletionResponse) {
+	if chunk == nil {
+		return
+	}
+
+	if a.id == "" {
+		a.id = chunk.ID
+	}
+	if a.model == "" {
+		a.model = chunk.Model
+	}
+	if a.created == 0 {
+		a.created = chunk.Created
+	}
+	if chunk.SystemFingerprint != "" {
+		a.systemFingerprint = chunk.SystemFingerprint
+	}
+	// 用量通常只出现在最后一个块中，且为累计值
+	if chunk.Usage.TotalTokens > 0 || chunk.Usage.PromptTokens > 0 || chunk.Usage.CompletionTokens > 0 {
+		a.usage = chunk.Usage
+	}
+
+	for _, choice := range chunk.Choices {
+		acc := a.choice(choice.Index)
+		if choice.FinishReason != "" {
+			acc.finishReason = choice.FinishReason
+		}
+		if choice.Delta != nil {
+			acc.add(choice.Delta)
+		}
+	}
+}
+
+func (a *StreamAccumulator) choice(index int) *accumulatedChoice {
+	acc, ok := a.choices[index]
+	if !ok {
+		acc = &accumulatedChoice{toolIndexes: make(map[int]int)}
+		a.choices[index] = acc
+	}
+	return acc
+}
+
+func (c *accumulatedChoice) add(delta *ChatMessage) {
+	if delta.Role != "" {
+		c.role = delta.Role
+	}
+
+	switch v := delta.Content.(type) {
+	case string:
+		c.content.WriteString(v)
+	case nil:
+	default:
+		c.content.WriteString(fmt.Sprintf("%v", v))
+	}
+
+	if delta.FunctionCall != nil {
+		if c.functionCall == nil {
+			c.functionCall = &FunctionCall{}
+		}
+		c.functionCall.Name += delta.FunctionCall.Name
+		c.functionCall.Arguments += delta.FunctionCall.Arguments
+	}
+
+	c.citations = append(c.citations, delta.Citations...)
+	c.reasoning.WriteString(delta.ReasoningContent)
+	c.thinking = append(c.thinking, delta.ThinkingBlocks...)
+
+	for _, tc := range delta.ToolCalls {
+		call := c.toolCall(tc)
+		if tc.ID != "" {
+			call.ID = tc.ID
+		}
+		if tc.Type != "" {
+			call.Type = tc.Type
+		}
+		call.Function.Name += tc.Function.Name
+		call.Function.Arguments += tc.Function.Arguments
+	}
+}
+
+// toolCall 按 index 找到要合并的工具调用，没有 index 时按 ID 匹配或沿用最后一个
+func (c *accumulatedChoice) toolCall(tc ToolCall) *ToolCall {
+	if tc.Index != nil {
+		if pos, ok := c.toolIndexes[*tc.Index]; ok {
+			return c.toolCalls[pos]
+		}
+		c.toolIndexes[*tc.Index] = len(c.toolCalls)
+		c.toolCalls = append(c.toolCalls, &ToolCall{Type: "function"})
+		return c.toolCalls[len(c.toolCalls)-1]
+	}
+
+	for _, call := range c.toolCalls {
+		if tc.ID != "" && call.ID == tc.ID {
+			return call
+		}
+	}
+	if tc.ID == "" && len(c.toolCalls) > 0 {
+		return c.toolCalls[len(c.toolCalls)-1]
+	}
+	c.toolCalls = append(c.toolCalls, &ToolCall{Type: "function"})
+	return c.toolCalls[len(c.toolCalls)-1]
+}
+
+// Response 返回合并后的完整响应
+func (a *StreamAccumulator) Response() *ChatCompletionResponse {
+	indexes := make([]int, 0, len(a.choices))
+	for index := range a.choices {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	choices := make([]ChatCompletionChoice, 0, len(indexes))
+	for _, index := range indexes {
+		acc := a.choices[index]
+
+		role := acc.role
+		if role == "" {
+			role = "assistant"
+		}
+		message := ChatMessage{
+			Role:         role,
+			Content:      acc.content.String(),
+			FunctionCall: acc.functionCall,
+			Citations:    acc.citations,
+
+			ReasoningContent: acc.reasoning.String(),
+			ThinkingBlocks:   acc.thinking,
+		}
+		for _, call := range acc.toolCalls {
+			message.ToolCalls = append(message.ToolCalls, ToolCall{
+				ID:       call.ID,
+				Type:     call.Type,
+				Function: call.Function,
+			})
+		}
+
+		choices = append(choices, ChatCompletionChoice{
+			Index:        index,
+			Message:      message,
+			FinishReason: acc.finishReason,
+		})
+	}
+
+	usage := a.usage
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+
+	return &ChatCompletionResponse{
+		ID:                a.id,
+		Object:            "chat.completion",
+		Created:           a.created,
+		Model:             a.model,
+		Choices:           choices,
+		Usage:             usage,
+		SystemFingerprint: a.systemFingerprint,
+	}
+}
@@ -0,0 +1,133 @@
+package llmhub
+
+import (
+	"testing"
+)
+
+func TestStreamAccumulator(t *testing.T) {
+	idx := func(i int) *int { return &i }
+
+	chunks := []*ChatCompletionResponse{
+		{ID: "c1", Model: "m", Created: 1, Choices: []ChatCompletionChoice{
+			{Index: 0, Delta: &ChatMessage{Role: "assistant", Content: ""}},
+		}},
+		{ID: "c1", Choices: []ChatCompletionChoice{
+			{Index: 0, Delta: &ChatMessage{Content: "Checking "}},
+		}},
+		{ID: "c1", Choices: []ChatCompletionChoice{
+			{Index: 0, Delta: &ChatMessage{Content: "weather"}},
+		}},
+		{ID: "c1", Choices: []ChatCompletionChoice{
+			{Index: 0, Delta: &ChatMessage{ToolCalls: []ToolCall{
+				{Index: idx(0), ID: "call_1", Type: "function", Function: FunctionCall{Name: "get_weather"}},
+			}}},
+		}},
+		{ID: "c1", Choices: []ChatCompletionChoice{
+			{Index: 0, Delta: &ChatMessage{ToolCalls: []ToolCall{
+				{Index: idx(0), Function: FunctionCall{Arguments: `{"city":`}},
+				{Index: idx(1), ID: "call_2", Type: "function", Function: FunctionCall{Name: "get_time", Arguments: `{}`}},
+			}}},
+		}},
+		{ID: "c1", Choices: []ChatCompletionChoice{
+			{Index: 0, Delta: &ChatMessage{ToolCalls: []ToolCall{
+				{Index: idx(0), Function: FunctionCall{Arguments: `"Paris"}`}},
+			}}},
+		}},
+		{ID: "c1", Choices: []ChatCompletionChoice{
+			{Index: 0, Delta: &ChatMessage{}, FinishReason: "tool_calls"},
+		}},
+		{ID: "c1", Usage: Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}},
+	}
+
+	acc := NewStreamAccumulator()
+	for _, chunk := range chunks {
+		acc.Add(chunk)
+	}
+	resp := acc.Response()
+
+	if resp.ID != "c1" || resp.Model != "m" || resp.Object != "chat.completion" {
+		t.Errorf("unexpected envelope: %+v", resp)
+	}
+	if len(resp.Choices) != 1 {
+		t.Fatalf("expected 1 choice, got %d", len(resp.Choices))
+	}
+
+	choice := resp.Choices[0]
+	if choice.Message.Role != "assistant" || choice.Message.Content != "Checking weather" {
+		t.Errorf("unexpected message: %+v", choice.Message)
+	}
+	if choice.FinishReason != "tool_calls" {
+		t.Errorf("expected finish_reason 'tool_calls', got %q", choice.FinishReason)
+	}
+	if resp.Usage.TotalTokens != 15 {
+		t.Errorf("expected total tokens 15, got %d", resp.Usage.TotalTokens)
+	}
+
+	calls := choice.Message.ToolCalls
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %d", len(calls))
+	}
+	if calls[0].ID != "call_1" || calls[0].Function.Name != "get_weather" || calls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected first tool call: %+v", calls[0])
+	}
+	if calls[1].ID != "call_2" || calls[1].Function.Name != "get_time" || calls[1].Function.Arguments != `{}` {
+		t.Errorf("unexpected second tool call: %+v", calls[1])
+	}
+	if calls[0].Index != nil {
+		t.Error("expected stream index to be cleared in the final message")
+	}
+}
+
+func TestStreamAccumulator_ToolCallsWithoutIndex(t *testing.T) {
+	acc := NewStreamAccumulator()
+	acc.Add(&ChatCompletionResponse{Choices: []ChatCompletionChoice{
+		{Delta: &ChatMessage{ToolCalls: []ToolCall{{ID: "call_1", Function: FunctionCall{Name: "f", Arguments: `{"a":`}}}}},
+	}})
+	acc.Add(&ChatCompletionResponse{Choices: []ChatCompletionChoice{
+		{Delta: &ChatMessage{ToolCalls: []ToolCall{{Function: FunctionCall{Arguments: `1}`}}}}},
+	}})
+
+	calls := acc.Response().Choices[0].Message.ToolCalls
+	if len(calls) != 1 || calls[0].Function.Arguments != `{"a":1}` || calls[0].Type != "function" {
+		t.Errorf("unexpected tool calls: %+v", calls)
+	}
+}
+
+func TestStreamAccumulator_Citations(t *testing.T) {
+	acc := NewStreamAccumulator()
+	acc.Add(&ChatCompletionResponse{Choices: []ChatCompletionChoice{
+		{Delta: &ChatMessage{Role: "assistant", Content: "Sunny"}},
+	}})
+	acc.Add(&ChatCompletionResponse{Choices: []ChatCompletionChoice{
+		{Delta: &ChatMessage{Citations: []Citation{{Start: 0, End: 5, Text: "Sunny"}}}},
+	}})
+
+	citations := acc.Response().Choices[0].Message.Citations
+	if len(citations) != 1 || citations[0].Text != "Sunny" {
+		t.Errorf("expected citation to be kept, got %+v", citations)
+	}
+}
+
+func TestStreamAccumulator_Reasoning(t *testing.T) {
+	acc := NewStreamAccumulator()
+	acc.Add(&ChatCompletionResponse{Choices: []ChatCompletionChoice{
+		{Delta: &ChatMessage{Role: "assistant", ReasoningContent: "Let me "}},
+	}})
+	acc.Add(&ChatCompletionResponse{Choices: []ChatCompletionChoice{
+		{Delta: &ChatMessage{ReasoningContent: "think."}},
+	}})
+	acc.Add(&ChatCompletionResponse{Choices: []ChatCompletionChoice{
+		{Delta: &ChatMessage{ThinkingBlocks: []ThinkingBlock{{Type: "thinking", Thinking: "Let me think.", Signature: "sig"}}}},
+	}})
+	acc.Add(&ChatCompletionResponse{Choices: []ChatCompletionChoice{
+		{Delta: &ChatMessage{Content: "42"}, FinishReason: "stop"},
+	}})
+
+	msg := acc.Response().Choices[0].Message
+	if msg.ReasoningContent != "Let me think." || msg.Content != "42" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+	if len(msg.ThinkingBlocks) != 1 || msg.ThinkingBlocks[0].Signature != "sig" {
+		t.Errorf("unexpected thinking blocks: %+v", msg.ThinkingBlocks)
+	}
+}
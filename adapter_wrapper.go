@@ -16,25 +16,29 @@ type adapterWrapper struct {
 func (w *adapterWrapper) ChatCompletion(ctx context.Context, req *internalChatCompletionRequest) (*internalChatCompletionResponse, error) {
 	// 转换为适配器需要的类型
 	adapterReq := &models.ChatCompletionRequest{
-		Model:            req.Model,
-		Messages:         w.toAdapterMessages(req.Messages),
-		Temperature:      req.Temperature,
-		TopP:             req.TopP,
-		MaxTokens:        req.MaxTokens,
-		Stream:           req.Stream,
-		PresencePenalty:  req.PresencePenalty,
-		FrequencyPenalty: req.FrequencyPenalty,
-		Stop:             req.Stop,
-		User:             req.User,
-		Functions:        w.toAdapterFunctions(req.Functions),
-		FunctionCall:     req.FunctionCall,
-		LogitBias:        req.LogitBias,
-		LogProbs:         req.LogProbs,
-		TopLogProbs:      req.TopLogProbs,
-		ResponseFormat:   w.toAdapterResponseFormat(req.ResponseFormat),
-		Seed:             req.Seed,
-		Tools:            w.toAdapterTools(req.Tools),
-		ToolChoice:       req.ToolChoice,
+		Model:               req.Model,
+		Messages:            w.toAdapterMessages(req.Messages),
+		Temperature:         req.Temperature,
+		TopP:                req.TopP,
+		MaxTokens:           req.MaxTokens,
+		MaxCompletionTokens: req.MaxCompletionTokens,
+		ReasoningEffort:     req.ReasoningEffort,
+		Stream:              req.Stream,
+		PresencePenalty:     req.PresencePenalty,
+		FrequencyPenalty:    req.FrequencyPenalty,
+		Stop:                req.Stop,
+		User:                req.User,
+		Functions:           w.toAdapterFunctions(req.Functions),
+		FunctionCall:        req.FunctionCall,
+		LogitBias:           req.LogitBias,
+		LogProbs:            req.LogProbs,
+		TopLogProbs:         req.TopLogProbs,
+		ResponseFormat:      w.toAdapterResponseFormat(req.ResponseFormat),
+		Seed:                req.Seed,
+		Tools:               w.toAdapterTools(req.Tools),
+		ToolChoice:          req.ToolChoice,
+		KeepAlive:           req.KeepAlive,
+		Thinking:            w.toAdapterThinkingConfig(req.Thinking),
 	}
 
 	resp, err := w.adapter.ChatCompletion(ctx, adapterReq)
@@ -47,25 +51,30 @@ func (w *adapterWrapper) ChatCompletion(ctx context.Context, req *internalChatCo
 
 func (w *adapterWrapper) ChatCompletionStream(ctx context.Context, req *internalChatCompletionRequest) (io.ReadCloser, error) {
 	adapterReq := &models.ChatCompletionRequest{
-		Model:            req.Model,
-		Messages:         w.toAdapterMessages(req.Messages),
-		Temperature:      req.Temperature,
-		TopP:             req.TopP,
-		MaxTokens:        req.MaxTokens,
-		Stream:           true,
-		PresencePenalty:  req.PresencePenalty,
-		FrequencyPenalty: req.FrequencyPenalty,
-		Stop:             req.Stop,
-		User:             req.User,
-		Functions:        w.toAdapterFunctions(req.Functions),
-		FunctionCall:     req.FunctionCall,
-		LogitBias:        req.LogitBias,
-		LogProbs:         req.LogProbs,
-		TopLogProbs:      req.TopLogProbs,
-		ResponseFormat:   w.toAdapterResponseFormat(req.ResponseFormat),
-		Seed:             req.Seed,
-		Tools:            w.toAdapterTools(req.Tools),
-		ToolChoice:       req.ToolChoice,
+		Model:               req.Model,
+		Messages:            w.toAdapterMessages(req.Messages),
+		Temperature:         req.Temperature,
+		TopP:                req.TopP,
+		MaxTokens:           req.MaxTokens,
+		MaxCompletionTokens: req.MaxCompletionTokens,
+		ReasoningEffort:     req.ReasoningEffort,
+		Stream:              true,
+		StreamOptions:       w.toAdapterStreamOptions(req.StreamOptions),
+		PresencePenalty:     req.PresencePenalty,
+		FrequencyPenalty:    req.FrequencyPenalty,
+		Stop:                req.Stop,
+		User:                req.User,
+		Functions:           w.toAdapterFunctions(req.Functions),
+		FunctionCall:        req.FunctionCall,
+		LogitBias:           req.LogitBias,
+		LogProbs:            req.LogProbs,
+		TopLogProbs:         req.TopLogProbs,
+		ResponseFormat:      w.toAdapterResponseFormat(req.ResponseFormat),
+		Seed:                req.Seed,
+		Tools:               w.toAdapterTools(req.Tools),
+		ToolChoice:          req.ToolChoice,
+		KeepAlive:           req.KeepAlive,
+		Thinking:            w.toAdapterThinkingConfig(req.Thinking),
 	}
 
 	return w.adapter.ChatCompletionStream(ctx, adapterReq)
@@ -82,11 +91,15 @@ func (w *adapterWrapper) toAdapterMessages(msgs []internalChatMessage) []models.
 	for _, msg := range msgs {
 		result = append(result, models.ChatMessage{
 			Role:         msg.Role,
-			Content:      msg.Content,
+			Content:      w.toAdapterContent(msg.Content),
 			Name:         msg.Name,
 			FunctionCall: w.toAdapterFunctionCall(msg.FunctionCall),
 			ToolCalls:    w.toAdapterToolCalls(msg.ToolCalls),
 			ToolCallID:   msg.ToolCallID,
+			Citations:    w.toAdapterCitations(msg.Citations),
+
+			ReasoningContent: msg.ReasoningContent,
+			ThinkingBlocks:   w.toAdapterThinkingBlocks(msg.ThinkingBlocks),
 		})
 	}
 	return result
@@ -106,6 +119,7 @@ func (w *adapterWrapper) toAdapterToolCalls(tcs []internalToolCall) []models.Too
 	result := make([]models.ToolCall, 0, len(tcs))
 	for _, tc := range tcs {
 		result = append(result, models.ToolCall{
+			Index:    tc.Index,
 			ID:       tc.ID,
 			Type:     tc.Type,
 			Function: *w.toAdapterFunctionCall(&tc.Function),
@@ -126,13 +140,31 @@ func (w *adapterWrapper) toAdapterFunctions(funcs []internalFunctionDefinition)
 	return result
 }
 
+func (w *adapterWrapper) toAdapterStreamOptions(so *internalStreamOptions) *models.StreamOptions {
+	if so == nil {
+		return nil
+	}
+	return &models.StreamOptions{
+		IncludeUsage: so.IncludeUsage,
+	}
+}
+
 func (w *adapterWrapper) toAdapterResponseFormat(rf *internalResponseFormat) *models.ResponseFormat {
 	if rf == nil {
 		return nil
 	}
-	return &models.ResponseFormat{
+	result := &models.ResponseFormat{
 		Type: rf.Type,
 	}
+	if rf.JSONSchema != nil {
+		result.JSONSchema = &models.JSONSchema{
+			Name:        rf.JSONSchema.Name,
+			Description: rf.JSONSchema.Description,
+			Schema:      rf.JSONSchema.Schema,
+			Strict:      rf.JSONSchema.Strict,
+		}
+	}
+	return result
 }
 
 func (w *adapterWrapper) toAdapterTools(tools []internalTool) []models.Tool {
@@ -154,6 +186,8 @@ func (w *adapterWrapper) toInternalResponse(resp *models.ChatCompletionResponse)
 			Message:      w.toInternalMessage(choice.Message),
 			FinishReason: choice.FinishReason,
 			Delta:        w.toInternalMessagePtr(choice.Delta),
+
+			ContentFilterResults: w.toInternalContentFilterResults(choice.ContentFilterResults),
 		})
 	}
 
@@ -171,11 +205,15 @@ func (w *adapterWrapper) toInternalResponse(resp *models.ChatCompletionResponse)
 func (w *adapterWrapper) toInternalMessage(msg models.ChatMessage) internalChatMessage {
 	return internalChatMessage{
 		Role:         msg.Role,
-		Content:      msg.Content,
+		Content:      w.toInternalContent(msg.Content),
 		Name:         msg.Name,
 		FunctionCall: w.toInternalFunctionCall(msg.FunctionCall),
 		ToolCalls:    w.toInternalToolCalls(msg.ToolCalls),
 		ToolCallID:   msg.ToolCallID,
+		Citations:    w.toInternalCitations(msg.Citations),
+
+		ReasoningContent: msg.ReasoningContent,
+		ThinkingBlocks:   w.toInternalThinkingBlocks(msg.ThinkingBlocks),
 	}
 }
 
@@ -201,6 +239,7 @@ func (w *adapterWrapper) toInternalToolCalls(tcs []models.ToolCall) []internalTo
 	result := make([]internalToolCall, 0, len(tcs))
 	for _, tc := range tcs {
 		result = append(result, internalToolCall{
+			Index:    tc.Index,
 			ID:       tc.ID,
 			Type:     tc.Type,
 			Function: *w.toInternalFunctionCall(&tc.Function),
@@ -209,6 +248,130 @@ func (w *adapterWrapper) toInternalToolCalls(tcs []models.ToolCall) []internalTo
 	return result
 }
 
+func (w *adapterWrapper) toAdapterCitations(citations []internalCitation) []models.Citation {
+	if len(citations) == 0 {
+		return nil
+	}
+	result := make([]models.Citation, 0, len(citations))
+	for _, citation := range citations {
+		sources := make([]models.CitationSource, 0, len(citation.Sources))
+		for _, source := range citation.Sources {
+			sources = append(sources, models.CitationSource(source))
+		}
+		result = append(result, models.Citation{
+			Start:   citation.Start,
+			End:     citation.End,
+			Text:    citation.Text,
+			Sources: sources,
+		})
+	}
+	return result
+}
+
+func (w *adapterWrapper) toInternalCitations(citations []models.Citation) []internalCitation {
+	if len(citations) == 0 {
+		return nil
+	}
+	result := make([]internalCitation, 0, len(citations))
+	for _, citation := range citations {
+		sources := make([]internalCitationSource, 0, len(citation.Sources))
+		for _, source := range citation.Sources {
+			sources = append(sources, internalCitationSource(source))
+		}
+		result = append(result, internalCitation{
+			Start:   citation.Start,
+			End:     citation.End,
+			Text:    citation.Text,
+			Sources: sources,
+		})
+	}
+	return result
+}
+
+func (w *adapterWrapper) toInternalContentFilterResults(results map[string]models.ContentFilterResult) map[string]internalContentFilterResult {
+	if len(results) == 0 {
+		return nil
+	}
+	result := make(map[string]internalContentFilterResult, len(results))
+	for category, r := range results {
+		result[category] = internalContentFilterResult(r)
+	}
+	return result
+}
+
+// toAdapterContent 转换 []internalContentPart 形式的多模态内容，其他形式原样传递
+func (w *adapterWrapper) toAdapterContent(content interface{}) interface{} {
+	parts, ok := content.([]internalContentPart)
+	if !ok {
+		return content
+	}
+	result := make([]models.ContentPart, 0, len(parts))
+	for _, part := range parts {
+		p := models.ContentPart{Type: part.Type, Text: part.Text}
+		if part.ImageURL != nil {
+			p.ImageURL = &models.ImageURL{URL: part.ImageURL.URL, Detail: part.ImageURL.Detail}
+		}
+		if part.Document != nil {
+			doc := models.Document(*part.Document)
+			p.Document = &doc
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+func (w *adapterWrapper) toInternalContent(content interface{}) interface{} {
+	parts, ok := content.([]models.ContentPart)
+	if !ok {
+		return content
+	}
+	result := make([]internalContentPart, 0, len(parts))
+	for _, part := range parts {
+		p := internalContentPart{Type: part.Type, Text: part.Text}
+		if part.ImageURL != nil {
+			p.ImageURL = &internalImageURL{URL: part.ImageURL.URL, Detail: part.ImageURL.Detail}
+		}
+		if part.Document != nil {
+			doc := internalDocument(*part.Document)
+			p.Document = &doc
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+func (w *adapterWrapper) toAdapterThinkingBlocks(blocks []internalThinkingBlock) []models.ThinkingBlock {
+	if len(blocks) == 0 {
+		return nil
+	}
+	result := make([]models.ThinkingBlock, 0, len(blocks))
+	for _, block := range blocks {
+		result = append(result, models.ThinkingBlock(block))
+	}
+	return result
+}
+
+func (w *adapterWrapper) toInternalThinkingBlocks(blocks []models.ThinkingBlock) []internalThinkingBlock {
+	if len(blocks) == 0 {
+		return nil
+	}
+	result := make([]internalThinkingBlock, 0, len(blocks))
+	for _, block := range blocks {
+		result = append(result, internalThinkingBlock(block))
+	}
+	return result
+}
+
+func (w *adapterWrapper) toAdapterThinkingConfig(tc *internalThinkingConfig) *models.ThinkingConfig {
+	if tc == nil {
+		return nil
+	}
+	return &models.ThinkingConfig{
+		Type:         tc.Type,
+		BudgetTokens: tc.BudgetTokens,
+	}
+}
+
 func (w *adapterWrapper) toInternalUsage(usage models.Usage) internalUsage {
 	return internalUsage{
 		PromptTokens:     usage.PromptTokens,
@@ -216,3 +379,39 @@ func (w *adapterWrapper) toInternalUsage(usage models.Usage) internalUsage {
 		TotalTokens:      usage.TotalTokens,
 	}
 }
+
+func (w *adapterWrapper) toInternalStreamResponse(chunk *models.ChatCompletionStreamResponse) *internalChatCompletionResponse {
+	choices := make([]internalChatCompletionChoice, 0, len(chunk.Choices))
+	for _, choice := range chunk.Choices {
+		delta := internalChatMessage{
+			Role:         choice.Delta.Role,
+			Content:      choice.Delta.Content,
+			FunctionCall: w.toInternalFunctionCall(choice.Delta.FunctionCall),
+			ToolCalls:    w.toInternalToolCalls(choice.Delta.ToolCalls),
+			Citations:    w.toInternalCitations(choice.Delta.Citations),
+
+			ReasoningContent: choice.Delta.ReasoningContent,
+			ThinkingBlocks:   w.toInternalThinkingBlocks(choice.Delta.ThinkingBlocks),
+		}
+		choices = append(choices, internalChatCompletionChoice{
+			Index:        choice.Index,
+			FinishReason: choice.FinishReason,
+			Delta:        &delta,
+
+			ContentFilterResults: w.toInternalContentFilterResults(choice.ContentFilterResults),
+		})
+	}
+
+	resp := &internalChatCompletionResponse{
+		ID:                chunk.ID,
+		Object:            chunk.Object,
+		Created:           chunk.Created,
+		Model:             chunk.Model,
+		Choices:           choices,
+		SystemFingerprint: chunk.SystemFingerprint,
+	}
+	if chunk.Usage != nil {
+		resp.Usage = w.toInternalUsage(*chunk.Usage)
+	}
+	return resp
+}
@@ -3,7 +3,6 @@ package llmhub
 import (
 	"context"
 	"fmt"
-	"io"
 
 	"github.com/gotoailab/llmhub/internal/adapters"
 )
@@ -29,6 +28,18 @@ type ClientConfig struct {
 
 	// Model 模型名称（可选，可以在调用时指定）
 	Model string
+
+	// Deployment Azure OpenAI 部署名称（可选，默认使用请求中的模型名）
+	Deployment string
+
+	// APIVersion Azure OpenAI 的 api-version（可选）
+	APIVersion string
+
+	// Region AWS Bedrock（默认 us-east-1）或 Vertex AI（默认 us-central1）的地域（可选）
+	Region string
+
+	// CustomProvider 自定义 OpenAI 兼容提供商（可选），设置后无需注册，Provider 可省略
+	CustomProvider *CustomProvider
 }
 
 // NewClient 创建新的客户端
@@ -44,12 +55,27 @@ func NewClient(config ClientConfig) (*Client, error) {
 		return nil, fmt.Errorf("api key is required")
 	}
 
+	if config.Provider == "" && config.CustomProvider != nil {
+		config.Provider = Provider(config.CustomProvider.Name)
+	}
 	if config.Provider == "" {
 		return nil, fmt.Errorf("provider is required")
 	}
 
-	// 创建适配器（将 llmhub.Provider 转换为 adapters.Provider）
-	adapter, err := adapters.CreateAdapter(adapters.Provider(config.Provider), config.APIKey, config.BaseURL)
+	var adapter adapters.Adapter
+	var err error
+	if config.CustomProvider != nil {
+		customConfig := config.CustomProvider.toAdapterConfig()
+		customConfig.Name = string(config.Provider)
+		adapter, err = adapters.CreateCustomAdapter(customConfig, config.APIKey, config.BaseURL)
+	} else {
+		// 创建适配器（将 llmhub.Provider 转换为 adapters.Provider）
+		adapter, err = adapters.CreateAdapterWithOptions(adapters.Provider(config.Provider), config.APIKey, config.BaseURL, adapters.AdapterOptions{
+			Deployment: config.Deployment,
+			APIVersion: config.APIVersion,
+			Region:     config.Region,
+		})
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create adapter: %w", err)
 	}
@@ -84,7 +110,8 @@ func (c *Client) ChatCompletions(ctx context.Context, req ChatCompletionRequest)
 }
 
 // ChatCompletionsStream 创建流式聊天完成请求
-func (c *Client) ChatCompletionsStream(ctx context.Context, req ChatCompletionRequest) (io.ReadCloser, error) {
+// 返回的 ChatCompletionStream 使用完毕后需要调用 Close
+func (c *Client) ChatCompletionsStream(ctx context.Context, req ChatCompletionRequest) (*ChatCompletionStream, error) {
 	// 如果请求中没有指定模型，使用客户端默认模型
 	if req.Model == "" {
 		if c.model == "" {
@@ -100,7 +127,12 @@ func (c *Client) ChatCompletionsStream(ctx context.Context, req ChatCompletionRe
 	internalReq := c.toInternalRequest(req)
 
 	// 调用适配器包装器
-	return c.adapter.ChatCompletionStream(ctx, internalReq)
+	body, err := c.adapter.ChatCompletionStream(ctx, internalReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return newChatCompletionStream(c, body), nil
 }
 
 // GetProvider 获取当前客户端使用的提供商
@@ -111,25 +143,30 @@ func (c *Client) GetProvider() Provider {
 // toInternalRequest 转换为内部请求格式
 func (c *Client) toInternalRequest(req ChatCompletionRequest) *internalChatCompletionRequest {
 	return &internalChatCompletionRequest{
-		Model:            req.Model,
-		Messages:         c.toInternalMessages(req.Messages),
-		Temperature:      req.Temperature,
-		TopP:             req.TopP,
-		MaxTokens:        req.MaxTokens,
-		Stream:           req.Stream,
-		PresencePenalty:  req.PresencePenalty,
-		FrequencyPenalty: req.FrequencyPenalty,
-		Stop:             req.Stop,
-		User:             req.User,
-		Functions:        c.toInternalFunctions(req.Functions),
-		FunctionCall:     req.FunctionCall,
-		LogitBias:        req.LogitBias,
-		LogProbs:         req.LogProbs,
-		TopLogProbs:      req.TopLogProbs,
-		ResponseFormat:   c.toInternalResponseFormat(req.ResponseFormat),
-		Seed:             req.Seed,
-		Tools:            c.toInternalTools(req.Tools),
-		ToolChoice:       req.ToolChoice,
+		Model:               req.Model,
+		Messages:            c.toInternalMessages(req.Messages),
+		Temperature:         req.Temperature,
+		TopP:                req.TopP,
+		MaxTokens:           req.MaxTokens,
+		MaxCompletionTokens: req.MaxCompletionTokens,
+		ReasoningEffort:     req.ReasoningEffort,
+		Stream:              req.Stream,
+		StreamOptions:       c.toInternalStreamOptions(req.StreamOptions),
+		PresencePenalty:     req.PresencePenalty,
+		FrequencyPenalty:    req.FrequencyPenalty,
+		Stop:                req.Stop,
+		User:                req.User,
+		Functions:           c.toInternalFunctions(req.Functions),
+		FunctionCall:        req.FunctionCall,
+		LogitBias:           req.LogitBias,
+		LogProbs:            req.LogProbs,
+		TopLogProbs:         req.TopLogProbs,
+		ResponseFormat:      c.toInternalResponseFormat(req.ResponseFormat),
+		Seed:                req.Seed,
+		Tools:               c.toInternalTools(req.Tools),
+		ToolChoice:          req.ToolChoice,
+		KeepAlive:           req.KeepAlive,
+		Thinking:            c.toInternalThinkingConfig(req.Thinking),
 	}
 }
 
@@ -142,6 +179,8 @@ func (c *Client) toPublicResponse(resp *internalChatCompletionResponse) *ChatCom
 			Message:      c.toPublicMessage(choice.Message),
 			FinishReason: choice.FinishReason,
 			Delta:        c.toPublicMessagePtr(choice.Delta),
+
+			ContentFilterResults: c.toPublicContentFilterResults(choice.ContentFilterResults),
 		})
 	}
 
@@ -162,11 +201,15 @@ func (c *Client) toInternalMessages(msgs []ChatMessage) []internalChatMessage {
 	for _, msg := range msgs {
 		result = append(result, internalChatMessage{
 			Role:         msg.Role,
-			Content:      msg.Content,
+			Content:      c.toInternalContent(msg.Content),
 			Name:         msg.Name,
 			FunctionCall: c.toInternalFunctionCall(msg.FunctionCall),
 			ToolCalls:    c.toInternalToolCalls(msg.ToolCalls),
 			ToolCallID:   msg.ToolCallID,
+			Citations:    c.toInternalCitations(msg.Citations),
+
+			ReasoningContent: msg.ReasoningContent,
+			ThinkingBlocks:   c.toInternalThinkingBlocks(msg.ThinkingBlocks),
 		})
 	}
 	return result
@@ -175,11 +218,15 @@ func (c *Client) toInternalMessages(msgs []ChatMessage) []internalChatMessage {
 func (c *Client) toPublicMessage(msg internalChatMessage) ChatMessage {
 	return ChatMessage{
 		Role:         msg.Role,
-		Content:      msg.Content,
+		Content:      c.toPublicContent(msg.Content),
 		Name:         msg.Name,
 		FunctionCall: c.toPublicFunctionCall(msg.FunctionCall),
 		ToolCalls:    c.toPublicToolCalls(msg.ToolCalls),
 		ToolCallID:   msg.ToolCallID,
+		Citations:    c.toPublicCitations(msg.Citations),
+
+		ReasoningContent: msg.ReasoningContent,
+		ThinkingBlocks:   c.toPublicThinkingBlocks(msg.ThinkingBlocks),
 	}
 }
 
@@ -227,6 +274,7 @@ func (c *Client) toInternalToolCalls(tcs []ToolCall) []internalToolCall {
 	result := make([]internalToolCall, 0, len(tcs))
 	for _, tc := range tcs {
 		result = append(result, internalToolCall{
+			Index:    tc.Index,
 			ID:       tc.ID,
 			Type:     tc.Type,
 			Function: *c.toInternalFunctionCall(&tc.Function),
@@ -239,6 +287,7 @@ func (c *Client) toPublicToolCalls(tcs []internalToolCall) []ToolCall {
 	result := make([]ToolCall, 0, len(tcs))
 	for _, tc := range tcs {
 		result = append(result, ToolCall{
+			Index:    tc.Index,
 			ID:       tc.ID,
 			Type:     tc.Type,
 			Function: *c.toPublicFunctionCall(&tc.Function),
@@ -247,6 +296,130 @@ func (c *Client) toPublicToolCalls(tcs []internalToolCall) []ToolCall {
 	return result
 }
 
+func (c *Client) toInternalCitations(citations []Citation) []internalCitation {
+	if len(citations) == 0 {
+		return nil
+	}
+	result := make([]internalCitation, 0, len(citations))
+	for _, citation := range citations {
+		sources := make([]internalCitationSource, 0, len(citation.Sources))
+		for _, source := range citation.Sources {
+			sources = append(sources, internalCitationSource(source))
+		}
+		result = append(result, internalCitation{
+			Start:   citation.Start,
+			End:     citation.End,
+			Text:    citation.Text,
+			Sources: sources,
+		})
+	}
+	return result
+}
+
+func (c *Client) toPublicCitations(citations []internalCitation) []Citation {
+	if len(citations) == 0 {
+		return nil
+	}
+	result := make([]Citation, 0, len(citations))
+	for _, citation := range citations {
+		sources := make([]CitationSource, 0, len(citation.Sources))
+		for _, source := range citation.Sources {
+			sources = append(sources, CitationSource(source))
+		}
+		result = append(result, Citation{
+			Start:   citation.Start,
+			End:     citation.End,
+			Text:    citation.Text,
+			Sources: sources,
+		})
+	}
+	return result
+}
+
+func (c *Client) toPublicContentFilterResults(results map[string]internalContentFilterResult) map[string]ContentFilterResult {
+	if len(results) == 0 {
+		return nil
+	}
+	result := make(map[string]ContentFilterResult, len(results))
+	for category, r := range results {
+		result[category] = ContentFilterResult(r)
+	}
+	return result
+}
+
+// toInternalContent 转换 []ContentPart 形式的多模态内容，其他形式原样传递
+func (c *Client) toInternalContent(content interface{}) interface{} {
+	parts, ok := content.([]ContentPart)
+	if !ok {
+		return content
+	}
+	result := make([]internalContentPart, 0, len(parts))
+	for _, part := range parts {
+		p := internalContentPart{Type: part.Type, Text: part.Text}
+		if part.ImageURL != nil {
+			p.ImageURL = &internalImageURL{URL: part.ImageURL.URL, Detail: part.ImageURL.Detail}
+		}
+		if part.Document != nil {
+			doc := internalDocument(*part.Document)
+			p.Document = &doc
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+func (c *Client) toPublicContent(content interface{}) interface{} {
+	parts, ok := content.([]internalContentPart)
+	if !ok {
+		return content
+	}
+	result := make([]ContentPart, 0, len(parts))
+	for _, part := range parts {
+		p := ContentPart{Type: part.Type, Text: part.Text}
+		if part.ImageURL != nil {
+			p.ImageURL = &ImageURL{URL: part.ImageURL.URL, Detail: part.ImageURL.Detail}
+		}
+		if part.Document != nil {
+			doc := Document(*part.Document)
+			p.Document = &doc
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+func (c *Client) toInternalThinkingBlocks(blocks []ThinkingBlock) []internalThinkingBlock {
+	if len(blocks) == 0 {
+		return nil
+	}
+	result := make([]internalThinkingBlock, 0, len(blocks))
+	for _, block := range blocks {
+		result = append(result, internalThinkingBlock(block))
+	}
+	return result
+}
+
+func (c *Client) toPublicThinkingBlocks(blocks []internalThinkingBlock) []ThinkingBlock {
+	if len(blocks) == 0 {
+		return nil
+	}
+	result := make([]ThinkingBlock, 0, len(blocks))
+	for _, block := range blocks {
+		result = append(result, ThinkingBlock(block))
+	}
+	return result
+}
+
+func (c *Client) toInternalThinkingConfig(tc *ThinkingConfig) *internalThinkingConfig {
+	if tc == nil {
+		return nil
+	}
+	return &internalThinkingConfig{
+		Type:         tc.Type,
+		BudgetTokens: tc.BudgetTokens,
+	}
+}
+
 func (c *Client) toInternalTools(tools []Tool) []internalTool {
 	result := make([]internalTool, 0, len(tools))
 	for _, tool := range tools {
@@ -258,11 +431,29 @@ func (c *Client) toInternalTools(tools []Tool) []internalTool {
 	return result
 }
 
+func (c *Client) toInternalStreamOptions(so *StreamOptions) *internalStreamOptions {
+	if so == nil {
+		return nil
+	}
+	return &internalStreamOptions{
+		IncludeUsage: so.IncludeUsage,
+	}
+}
+
 func (c *Client) toInternalResponseFormat(rf *ResponseFormat) *internalResponseFormat {
 	if rf == nil {
 		return nil
 	}
-	return &internalResponseFormat{
+	result := &internalResponseFormat{
 		Type: rf.Type,
 	}
+	if rf.JSONSchema != nil {
+		result.JSONSchema = &internalJSONSchema{
+			Name:        rf.JSONSchema.Name,
+			Description: rf.JSONSchema.Description,
+			Schema:      rf.JSONSchema.Schema,
+			Strict:      rf.JSONSchema.Strict,
+		}
+	}
+	return result
 }
@@ -63,6 +63,45 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClient_CustomProvider(t *testing.T) {
+	client, err := NewClient(ClientConfig{
+		APIKey: "test-key",
+		CustomProvider: &CustomProvider{
+			Name:       "lmstudio",
+			BaseURL:    "http://localhost:1234/v1",
+			AuthHeader: "X-Api-Key",
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client.GetProvider() != "lmstudio" {
+		t.Errorf("GetProvider() = %v, want lmstudio", client.GetProvider())
+	}
+
+	if _, err := NewClient(ClientConfig{APIKey: "test-key", CustomProvider: &CustomProvider{Name: "lmstudio"}}); err == nil {
+		t.Error("expected error for custom provider without base url")
+	}
+}
+
+func TestRegisterProvider(t *testing.T) {
+	if err := RegisterProvider(CustomProvider{Name: string(ProviderOpenAI), BaseURL: "https://example.com/v1"}); err == nil {
+		t.Error("expected error when overriding a built-in provider")
+	}
+
+	err := RegisterProvider(CustomProvider{Name: "cerebras", BaseURL: "https://api.cerebras.ai/v1", SupportsTools: true})
+	if err != nil {
+		t.Fatalf("RegisterProvider() error = %v", err)
+	}
+	client, err := NewClient(ClientConfig{APIKey: "test-key", Provider: "cerebras"})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if client.GetProvider() != "cerebras" {
+		t.Errorf("GetProvider() = %v, want cerebras", client.GetProvider())
+	}
+}
+
 func TestClient_GetProvider(t *testing.T) {
 	providers := []Provider{
 		ProviderOpenAI,
@@ -39,6 +39,9 @@ func init() {
 	adapters.Register(adapters.Provider(llmhub.ProviderTogether), adapters.NewTogetherAdapter)
 	adapters.Register(adapters.Provider(llmhub.ProviderNovita), adapters.NewNovitaAdapter)
 	adapters.Register(adapters.Provider(llmhub.ProviderXAI), adapters.NewXaiAdapter)
+	adapters.RegisterWithOptions(adapters.Provider(llmhub.ProviderAzureOpenAI), adapters.NewAzureOpenAIAdapterWithOptions)
+	adapters.RegisterWithOptions(adapters.Provider(llmhub.ProviderBedrock), adapters.NewBedrockAdapterWithOptions)
+	adapters.RegisterWithOptions(adapters.Provider(llmhub.ProviderVertex), adapters.NewVertexAdapterWithOptions)
 }
 
 func main() {
@@ -51,6 +54,24 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// 注册配置中声明的自定义提供商
+	for _, p := range cfg.Providers {
+		if err := adapters.RegisterCustomProvider(adapters.CustomProviderConfig{
+			Name:                    p.Name,
+			BaseURL:                 p.BaseURL,
+			Endpoint:                p.Endpoint,
+			AuthHeader:              p.AuthHeader,
+			AuthScheme:              p.AuthScheme,
+			Headers:                 p.Headers,
+			SupportsTools:           p.SupportsTools,
+			SupportsVision:          p.SupportsVision,
+			SupportsStreamOptions:   p.SupportsStreamOptions,
+			SupportsReasoningEffort: p.SupportsReasoningEffort,
+		}); err != nil {
+			log.Fatalf("Failed to register provider: %v", err)
+		}
+	}
+
 	// 创建处理器和路由
 	handler := api.NewHandler()
 	router := api.SetupRouter(handler)
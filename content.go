@@ -0,0 +1,40 @@
+package llmhub
+
+import "encoding/base64"
+
+// 图片细节级别，用于 ImageURLPart 和 ImageDataPart 的 detail 参数
+const (
+	ImageDetailAuto = "auto"
+	ImageDetailLow  = "low"
+	ImageDetailHigh = "high"
+)
+
+// TextPart 创建文本内容片段
+func TextPart(text string) ContentPart {
+	return ContentPart{Type: "text", Text: text}
+}
+
+// ImageURLPart 创建图片内容片段，url 为 http(s) 地址或 data URI，detail 可为空
+func ImageURLPart(url, detail string) ContentPart {
+	return ContentPart{Type: "image_url", ImageURL: &ImageURL{URL: url, Detail: detail}}
+}
+
+// ImageDataPart 将图片数据编码为 base64 data URI 并创建图片内容片段
+// mimeType 如 "image/png"、"image/jpeg"，detail 可为空
+func ImageDataPart(mimeType string, data []byte, detail string) ContentPart {
+	return ImageURLPart("data:"+mimeType+";base64,"+base64.StdEncoding.EncodeToString(data), detail)
+}
+
+// PDFPart 将 PDF 文件内容编码为 base64 并创建文档内容片段，title 可为空
+func PDFPart(data []byte, title string) ContentPart {
+	return ContentPart{Type: "document", Document: &Document{
+		MediaType: "application/pdf",
+		Data:      base64.StdEncoding.EncodeToString(data),
+		Title:     title,
+	}}
+}
+
+// TextDocumentPart 创建纯文本文档内容片段，title 可为空
+func TextDocumentPart(text, title string) ContentPart {
+	return ContentPart{Type: "document", Document: &Document{MediaType: "text/plain", Data: text, Title: title}}
+}
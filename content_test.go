@@ -0,0 +1,49 @@
+package llmhub
+
+import (
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestContentPartHelpers(t *testing.T) {
+	part := ImageDataPart("image/png", []byte("png"), ImageDetailHigh)
+	if part.Type != "image_url" || part.ImageURL.URL != "data:image/png;base64,cG5n" || part.ImageURL.Detail != "high" {
+		t.Errorf("unexpected image part: %+v", part)
+	}
+	if part := TextPart("hi"); part.Type != "text" || part.Text != "hi" {
+		t.Errorf("unexpected text part: %+v", part)
+	}
+	if part := PDFPart([]byte("%PDF"), "Report"); part.Type != "document" || part.Document.MediaType != "application/pdf" ||
+		part.Document.Data != "JVBERg==" || part.Document.Title != "Report" {
+		t.Errorf("unexpected pdf part: %+v", part.Document)
+	}
+}
+
+func TestContentParts_Conversion(t *testing.T) {
+	c := &Client{}
+	w := &adapterWrapper{}
+	doc := TextDocumentPart("Q3 revenue grew 12%.", "Report")
+	doc.Document.Citations = true
+	content := []ContentPart{
+		TextPart("What is in this image?"),
+		ImageURLPart("https://example.com/cat.jpg", ImageDetailLow),
+		doc,
+	}
+
+	parts, ok := w.toAdapterContent(c.toInternalContent(content)).([]models.ContentPart)
+	if !ok || len(parts) != 3 {
+		t.Fatalf("expected []models.ContentPart, got %#v", parts)
+	}
+	if parts[0].Text != "What is in this image?" || parts[1].ImageURL.URL != "https://example.com/cat.jpg" || parts[1].ImageURL.Detail != "low" {
+		t.Errorf("unexpected parts: %+v", parts)
+	}
+	if d := parts[2].Document; d == nil || d.MediaType != "text/plain" || d.Title != "Report" || !d.Citations {
+		t.Errorf("unexpected document part: %+v", d)
+	}
+
+	// 字符串内容原样传递
+	if got := w.toAdapterContent(c.toInternalContent("hello")); got != "hello" {
+		t.Errorf("expected string content unchanged, got %v", got)
+	}
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"io"
@@ -39,23 +38,24 @@ func main() {
 	fmt.Println("流式响应:")
 	fmt.Println("---")
 
-	// 读取流式数据
-	reader := bufio.NewReader(stream)
-	buffer := make([]byte, 4096)
-
+	// 逐块读取流式数据，Recv 在流结束时返回 io.EOF
 	for {
-		n, err := reader.Read(buffer)
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			if err == io.EOF {
-				break
-			}
 			log.Printf("读取错误: %v", err)
 			break
 		}
 
-		if n > 0 {
-			// 打印接收到的数据（实际使用时需要解析 SSE 格式）
-			fmt.Print(string(buffer[:n]))
+		for _, choice := range chunk.Choices {
+			if choice.Delta == nil {
+				continue
+			}
+			if content, ok := choice.Delta.Content.(string); ok {
+				fmt.Print(content)
+			}
 		}
 	}
 
@@ -4,7 +4,8 @@ go 1.21
 
 require (
 	github.com/gin-gonic/gin v1.9.1
-	github.com/sashabaranov/go-openai v1.20.4
+	github.com/gorilla/websocket v1.5.3
+	github.com/sashabaranov/go-openai v1.41.2
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -34,4 +34,9 @@ func init() {
 	adapters.Register(adapters.Provider(ProviderNovita), adapters.NewNovitaAdapter)
 	adapters.Register(adapters.Provider(ProviderXAI), adapters.NewXaiAdapter)
 	adapters.Register(adapters.Provider(ProviderOpenRouter), adapters.NewOpenRouterAdapter)
+
+	// 需要额外配置（部署名称、api-version 等）的适配器
+	adapters.RegisterWithOptions(adapters.Provider(ProviderAzureOpenAI), adapters.NewAzureOpenAIAdapterWithOptions)
+	adapters.RegisterWithOptions(adapters.Provider(ProviderBedrock), adapters.NewBedrockAdapterWithOptions)
+	adapters.RegisterWithOptions(adapters.Provider(ProviderVertex), adapters.NewVertexAdapterWithOptions)
 }
@@ -2,8 +2,14 @@ package adapters
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gotoailab/llmhub/internal/models"
 )
@@ -27,27 +33,112 @@ type Adapter interface {
 type AdapterFactory func(apiKey, baseURL string) (Adapter, error)
 
 // Registry 适配器注册表，使用 Provider 作为 key
+// 运行期间可能注册自定义提供商，请通过 Register 和 CreateAdapter 访问
 var Registry = make(map[Provider]AdapterFactory)
 
+// registryMu 保护 Registry 和 optionsRegistry 的并发读写
+var registryMu sync.RWMutex
+
 // Register 注册适配器工厂
 func Register(provider Provider, factory AdapterFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	Registry[provider] = factory
 }
 
+// AdapterOptions 部分提供商需要的额外配置
+type AdapterOptions struct {
+	Deployment string // Azure OpenAI 部署名称，为空时使用请求中的模型名
+	APIVersion string // Azure OpenAI api-version
+	Region     string // AWS Bedrock 或 Vertex AI 的地域，如 us-east-1、us-central1
+}
+
+// OptionsAdapterFactory 接受额外配置的适配器工厂函数
+type OptionsAdapterFactory func(apiKey, baseURL string, opts AdapterOptions) (Adapter, error)
+
+var optionsRegistry = make(map[Provider]OptionsAdapterFactory)
+
+// RegisterWithOptions 注册需要额外配置的适配器工厂，同时以空配置注册到 Registry
+func RegisterWithOptions(provider Provider, factory OptionsAdapterFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	optionsRegistry[provider] = factory
+	Registry[provider] = func(apiKey, baseURL string) (Adapter, error) {
+		return factory(apiKey, baseURL, AdapterOptions{})
+	}
+}
+
 // CreateAdapter 根据提供商创建适配器
 func CreateAdapter(provider Provider, apiKey, baseURL string) (Adapter, error) {
+	return CreateAdapterWithOptions(provider, apiKey, baseURL, AdapterOptions{})
+}
+
+// CreateAdapterWithOptions 根据提供商和额外配置创建适配器，不需要额外配置的提供商忽略 opts
+func CreateAdapterWithOptions(provider Provider, apiKey, baseURL string, opts AdapterOptions) (Adapter, error) {
+	registryMu.RLock()
+	optionsFactory, hasOptions := optionsRegistry[provider]
 	factory, exists := Registry[provider]
-	if !exists {
+	registryMu.RUnlock()
+
+	var adapter Adapter
+	var err error
+	if hasOptions {
+		adapter, err = optionsFactory(apiKey, baseURL, opts)
+	} else if exists {
+		adapter, err = factory(apiKey, baseURL)
+	} else {
 		return nil, fmt.Errorf("provider %s is not registered", provider)
 	}
-	return factory(apiKey, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	return withDocumentGuard(adapter), nil
+}
+
+// withDocumentGuard 不支持文档输入的适配器在请求前检查，避免文档被静默丢弃
+func withDocumentGuard(adapter Adapter) Adapter {
+	if _, ok := adapter.(documentSupporter); !ok {
+		return &documentGuard{Adapter: adapter}
+	}
+	return adapter
+}
+
+// documentSupporter 由支持文档输入（document 内容片段）的适配器实现
+type documentSupporter interface {
+	supportsDocuments()
+}
+
+// documentGuard 拒绝包含文档的请求
+type documentGuard struct {
+	Adapter
+}
+
+func (g *documentGuard) ChatCompletion(ctx context.Context, req *models.ChatCompletionRequest) (*models.ChatCompletionResponse, error) {
+	if err := g.check(req); err != nil {
+		return nil, err
+	}
+	return g.Adapter.ChatCompletion(ctx, req)
+}
+
+func (g *documentGuard) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
+	if err := g.check(req); err != nil {
+		return nil, err
+	}
+	return g.Adapter.ChatCompletionStream(ctx, req)
+}
+
+func (g *documentGuard) check(req *models.ChatCompletionRequest) error {
+	if hasContentPart(req.Messages, "document") {
+		return fmt.Errorf("provider %s does not support document content parts", g.GetProvider())
+	}
+	return nil
 }
 
 // convertToOpenAIFormatGeneric 通用的 OpenAI 格式转换函数
 func convertToOpenAIFormatGeneric(req *models.ChatCompletionRequest) map[string]interface{} {
 	result := map[string]interface{}{
 		"model":    req.Model,
-		"messages": req.Messages,
+		"messages": toOpenAICompatibleMessages(req.Messages),
 	}
 
 	if req.Temperature != nil {
@@ -56,8 +147,9 @@ func convertToOpenAIFormatGeneric(req *models.ChatCompletionRequest) map[string]
 	if req.TopP != nil {
 		result["top_p"] = *req.TopP
 	}
-	if req.MaxTokens != nil {
-		result["max_tokens"] = *req.MaxTokens
+	// 多数兼容接口只识别 max_tokens
+	if maxTokens := maxOutputTokens(req); maxTokens != nil {
+		result["max_tokens"] = *maxTokens
 	}
 	if req.PresencePenalty != nil {
 		result["presence_penalty"] = *req.PresencePenalty
@@ -107,3 +199,70 @@ func convertToOpenAIFormatGeneric(req *models.ChatCompletionRequest) map[string]
 
 	return result
 }
+
+// toOpenAICompatibleMessages 复制消息并去掉 reasoning_content 等响应专用字段，
+// developer 角色转换为 system，避免不识别这些字段的兼容接口报错
+func toOpenAICompatibleMessages(msgs []models.ChatMessage) []models.ChatMessage {
+	result := make([]models.ChatMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		msg.Role = nativeRole(msg.Role)
+		msg.Citations = nil
+		msg.ReasoningContent = ""
+		msg.ThinkingBlocks = nil
+		result = append(result, msg)
+	}
+	return result
+}
+
+// nativeRole 将 OpenAI 的 developer 角色映射为 system，供不识别 developer 的提供商使用
+func nativeRole(role string) string {
+	if role == "developer" {
+		return "system"
+	}
+	return role
+}
+
+// newResponseID 为不返回 ID 的提供商生成 OpenAI 风格的响应 ID
+func newResponseID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("chatcmpl-%d", time.Now().UnixNano())
+	}
+	return "chatcmpl-" + hex.EncodeToString(b)
+}
+
+// newToolCallID 为不返回调用 ID 的提供商生成工具调用 ID
+func newToolCallID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("call_%d", time.Now().UnixNano())
+	}
+	return "call_" + hex.EncodeToString(b)
+}
+
+// newStreamingHTTPClient 创建适合流式响应的 HTTP 客户端
+// http.Client.Timeout 会计入读取整个响应体的时间，长时间的流式输出会被中途截断，
+// 这里只限制等待响应头的时间，整体截止时间由调用方的 context 控制
+func newStreamingHTTPClient(headerTimeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = headerTimeout
+	return &http.Client{Transport: transport}
+}
+
+// contentToText 提取消息内容中的文本，多模态内容只保留 text 部分
+func contentToText(content interface{}) string {
+	if s, ok := content.(string); ok {
+		return s
+	}
+	parts, ok := contentParts(content)
+	if !ok {
+		return fmt.Sprintf("%v", content)
+	}
+	var b strings.Builder
+	for _, part := range parts {
+		if part.Type == "text" {
+			b.WriteString(part.Text)
+		}
+	}
+	return b.String()
+}
@@ -2,7 +2,12 @@ package adapters
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gotoailab/llmhub/internal/models"
 )
@@ -18,6 +23,15 @@ func init() {
 	Register(Provider("mistral"), NewMistralAdapter)
 }
 
+// unregisterProvider 在 registryMu 保护下移除测试中注册的提供商
+func unregisterProvider(provider Provider) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(Registry, provider)
+	delete(optionsRegistry, provider)
+	delete(customProviders, provider)
+}
+
 func TestCreateAdapter(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -116,3 +130,65 @@ func TestAdapterInterface(t *testing.T) {
 		t.Logf("ChatCompletion returned error (expected): %v", err)
 	}
 }
+
+func TestCreateAdapter_DocumentSupport(t *testing.T) {
+	req := &models.ChatCompletionRequest{
+		Model: "gpt-4o",
+		Messages: []models.ChatMessage{{Role: "user", Content: []models.ContentPart{
+			{Type: "document", Document: &models.Document{MediaType: "application/pdf", Data: "JVBERi0x"}},
+			{Type: "text", Text: "Summarize this contract."},
+		}}},
+	}
+
+	adapter, err := CreateAdapter(Provider("openai"), "test-key", "")
+	if err != nil {
+		t.Fatalf("CreateAdapter() error = %v", err)
+	}
+	_, err = adapter.ChatCompletion(context.Background(), req)
+	if err == nil || err.Error() != "provider openai does not support document content parts" {
+		t.Errorf("expected document error, got %v", err)
+	}
+	if _, err := adapter.ChatCompletionStream(context.Background(), req); err == nil {
+		t.Error("expected document error for stream")
+	}
+
+	for _, provider := range []Provider{"claude", "gemini"} {
+		adapter, err := CreateAdapter(provider, "test-key", "")
+		if err != nil {
+			t.Fatalf("CreateAdapter(%s) error = %v", provider, err)
+		}
+		if _, ok := adapter.(documentSupporter); !ok {
+			t.Errorf("expected %s adapter to accept documents", provider)
+		}
+	}
+}
+
+func TestNewStreamingHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-headers" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: 1\n\n")
+		w.(http.Flusher).Flush()
+		// 响应体的输出时间超过等待响应头的超时时间
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprint(w, "data: 2\n\n")
+	}))
+	defer server.Close()
+
+	client := newStreamingHTTPClient(100 * time.Millisecond)
+	resp, err := client.Get(server.URL + "/stream")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || string(body) != "data: 1\n\ndata: 2\n\n" {
+		t.Errorf("expected full stream body, got %q, %v", body, err)
+	}
+
+	if _, err := client.Get(server.URL + "/slow-headers"); err == nil {
+		t.Error("expected response header timeout")
+	}
+}
@@ -0,0 +1,47 @@
+package adapters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// azureOpenAIDefaultAPIVersion 未配置 api-version 时使用的 GA 版本
+const azureOpenAIDefaultAPIVersion = "2024-10-21"
+
+// NewAzureOpenAIAdapter 创建 Azure OpenAI 适配器（导出以供注册）
+// baseURL 为资源终结点，如 https://my-resource.openai.azure.com，部署名称默认使用请求中的模型名
+func NewAzureOpenAIAdapter(apiKey, baseURL string) (Adapter, error) {
+	return NewAzureOpenAIAdapterWithOptions(apiKey, baseURL, AdapterOptions{})
+}
+
+// NewAzureOpenAIAdapterWithOptions 创建指定部署名称和 api-version 的 Azure OpenAI 适配器
+// apiKey 默认通过 api-key 头发送；以 "Bearer " 开头时视为 Entra ID 访问令牌，通过 Authorization 头发送
+func NewAzureOpenAIAdapterWithOptions(apiKey, baseURL string, opts AdapterOptions) (Adapter, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("azure openai requires base url (resource endpoint)")
+	}
+
+	config := openai.DefaultAzureConfig(apiKey, baseURL)
+	if token, ok := strings.CutPrefix(apiKey, "Bearer "); ok {
+		config = openai.DefaultAzureConfig(token, baseURL)
+		config.APIType = openai.APITypeAzureAD
+	}
+	config.APIVersion = azureOpenAIDefaultAPIVersion
+	if opts.APIVersion != "" {
+		config.APIVersion = opts.APIVersion
+	}
+	// 部署名称可以包含 "."，不使用 go-openai 默认的映射规则
+	config.AzureModelMapperFunc = func(model string) string {
+		if opts.Deployment != "" {
+			return opts.Deployment
+		}
+		return model
+	}
+
+	return &OpenAIAdapter{
+		provider: Provider("azure"),
+		client:   openai.NewClientWithConfig(config),
+	}, nil
+}
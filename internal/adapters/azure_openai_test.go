@@ -0,0 +1,190 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestAzureOpenAIAdapter_ChatCompletion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/gpt-4o-prod/chat/completions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("api-version"); got != "2024-06-01" {
+			t.Errorf("expected api-version 2024-06-01, got %q", got)
+		}
+		if got := r.Header.Get("api-key"); got != "azure-key" {
+			t.Errorf("expected api-key header, got %q", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("unexpected Authorization header %q", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"chatcmpl-1","object":"chat.completion","created":1,"model":"gpt-4o","choices":[{"index":0,"finish_reason":"content_filter","message":{"role":"assistant","content":""},"content_filter_results":{"hate":{"filtered":false,"severity":"safe"},"violence":{"filtered":true,"severity":"high"}}}],"usage":{"prompt_tokens":5,"completion_tokens":0,"total_tokens":5}}`)
+	}))
+	defer server.Close()
+
+	adapter, err := NewAzureOpenAIAdapterWithOptions("azure-key", server.URL, AdapterOptions{Deployment: "gpt-4o-prod", APIVersion: "2024-06-01"})
+	if err != nil {
+		t.Fatalf("NewAzureOpenAIAdapterWithOptions() error = %v", err)
+	}
+	if adapter.GetProvider() != "azure" {
+		t.Errorf("expected provider azure, got %s", adapter.GetProvider())
+	}
+
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "gpt-4o",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	choice := resp.Choices[0]
+	if choice.FinishReason != "content_filter" {
+		t.Errorf("expected finish_reason content_filter, got %q", choice.FinishReason)
+	}
+	if got := choice.ContentFilterResults["violence"]; !got.Filtered || got.Severity != "high" {
+		t.Errorf("unexpected violence result: %+v", got)
+	}
+	if got := choice.ContentFilterResults["hate"]; got.Filtered || got.Severity != "safe" {
+		t.Errorf("unexpected hate result: %+v", got)
+	}
+	if _, ok := choice.ContentFilterResults["sexual"]; ok {
+		t.Error("expected categories missing from the response to be omitted")
+	}
+}
+
+func TestAzureOpenAIAdapter_EntraToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 未指定部署名称时使用模型名，且保留其中的 "."
+		if r.URL.Path != "/openai/deployments/gpt-4.1/chat/completions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("api-version"); got != azureOpenAIDefaultAPIVersion {
+			t.Errorf("expected default api-version, got %q", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer entra-token" {
+			t.Errorf("expected bearer token, got %q", got)
+		}
+		if got := r.Header.Get("api-key"); got != "" {
+			t.Errorf("unexpected api-key header %q", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"chatcmpl-1","object":"chat.completion","created":1,"model":"gpt-4.1","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"Hi"}}]}`)
+	}))
+	defer server.Close()
+
+	adapter, err := NewAzureOpenAIAdapter("Bearer entra-token", server.URL+"/")
+	if err != nil {
+		t.Fatalf("NewAzureOpenAIAdapter() error = %v", err)
+	}
+
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "gpt-4.1",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.Choices[0].Message.Content != "Hi" || resp.Choices[0].ContentFilterResults != nil {
+		t.Errorf("unexpected choice: %+v", resp.Choices[0])
+	}
+}
+
+func TestAzureOpenAIAdapter_PromptFiltered(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"message":"The response was filtered due to the prompt triggering Azure OpenAI's content management policy.","type":null,"param":"prompt","code":"content_filter","status":400,"innererror":{"code":"ResponsibleAIPolicyViolation","content_filter_result":{"hate":{"filtered":true,"severity":"high"},"jailbreak":{"filtered":true,"detected":true},"sexual":{"filtered":false,"severity":"safe"}}}}}`)
+	}))
+	defer server.Close()
+
+	adapter, err := NewAzureOpenAIAdapter("azure-key", server.URL)
+	if err != nil {
+		t.Fatalf("NewAzureOpenAIAdapter() error = %v", err)
+	}
+
+	_, err = adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "gpt-4o",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+	if err == nil {
+		t.Fatal("expected content filter error")
+	}
+	if !strings.Contains(err.Error(), "prompt blocked by content filter (hate, jailbreak)") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAzureOpenAIAdapter_ChatCompletionStream(t *testing.T) {
+	upstream := []string{
+		`{"choices":[],"created":0,"id":"","model":"","object":"","prompt_filter_results":[{"prompt_index":0,"content_filter_results":{"hate":{"filtered":false,"severity":"safe"}}}]}`,
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"Hi"},"finish_reason":null,"content_filter_results":{"hate":{"filtered":false,"severity":"safe"}}}]}`,
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{},"finish_reason":"stop","content_filter_results":{}}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range upstream {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	adapter, err := NewAzureOpenAIAdapter("azure-key", server.URL)
+	if err != nil {
+		t.Fatalf("NewAzureOpenAIAdapter() error = %v", err)
+	}
+
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "gpt-4o",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		t.Fatalf("read stream: %v", err)
+	}
+	out := string(data)
+	if strings.Contains(out, "prompt_filter_results") || strings.Count(out, "data: ") != 3 {
+		t.Errorf("expected prompt filter chunk to be dropped, got:\n%s", out)
+	}
+	if !strings.Contains(out, `"content_filter_results":{"hate":{"filtered":false,"severity":"safe"}}`) {
+		t.Errorf("expected content filter results in stream, got:\n%s", out)
+	}
+}
+
+func TestNewAzureOpenAIAdapter_RequiresBaseURL(t *testing.T) {
+	if _, err := NewAzureOpenAIAdapter("azure-key", ""); err == nil {
+		t.Error("expected error without resource endpoint")
+	}
+}
+
+func TestCreateAdapterWithOptions(t *testing.T) {
+	RegisterWithOptions("azure-test", NewAzureOpenAIAdapterWithOptions)
+	defer unregisterProvider("azure-test")
+
+	if _, err := CreateAdapter("azure-test", "key", "https://example.openai.azure.com"); err != nil {
+		t.Errorf("CreateAdapter() error = %v", err)
+	}
+	if _, err := CreateAdapterWithOptions("azure-test", "key", "", AdapterOptions{Deployment: "dep"}); err == nil {
+		t.Error("expected options factory to be used and reject missing base url")
+	}
+	if _, err := CreateAdapterWithOptions("missing", "key", "", AdapterOptions{}); err == nil {
+		t.Error("expected error for unregistered provider")
+	}
+}
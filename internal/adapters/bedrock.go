@@ -0,0 +1,628 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// bedrockDefaultRegion 未配置地域且无法从 baseURL 推断时使用的地域
+const bedrockDefaultRegion = "us-east-1"
+
+// BedrockAdapter AWS Bedrock 适配器，使用 Converse / ConverseStream 接口
+// apiKey 格式为 "AccessKeyID:SecretAccessKey"，使用临时凭证时追加会话令牌 "AccessKeyID:SecretAccessKey:SessionToken"
+// 模型名称为 Bedrock 的模型 ID 或推理配置文件 ID，如 "anthropic.claude-3-5-sonnet-20240620-v1:0"
+type BedrockAdapter struct {
+	baseURL string
+	signer  *awsSigner
+	client  *http.Client
+}
+
+// NewBedrockAdapter 创建 AWS Bedrock 适配器（导出以供注册）
+func NewBedrockAdapter(apiKey, baseURL string) (Adapter, error) {
+	return NewBedrockAdapterWithOptions(apiKey, baseURL, AdapterOptions{})
+}
+
+// NewBedrockAdapterWithOptions 创建指定地域的 AWS Bedrock 适配器
+// 地域优先使用 opts.Region，其次从 bedrock-runtime.{region}.amazonaws.com 形式的 baseURL 推断
+func NewBedrockAdapterWithOptions(apiKey, baseURL string, opts AdapterOptions) (Adapter, error) {
+	parts := strings.SplitN(apiKey, ":", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("bedrock api key must be in the format \"AccessKeyID:SecretAccessKey[:SessionToken]\"")
+	}
+	var sessionToken string
+	if len(parts) == 3 {
+		sessionToken = parts[2]
+	}
+
+	region := opts.Region
+	if baseURL != "" && region == "" {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bedrock base url: %w", err)
+		}
+		host := u.Hostname()
+		if rest, ok := strings.CutPrefix(host, "bedrock-runtime."); ok {
+			region, _, _ = strings.Cut(rest, ".")
+		}
+	}
+	if region == "" {
+		region = bedrockDefaultRegion
+	}
+	if baseURL == "" {
+		baseURL = "https://bedrock-runtime." + region + ".amazonaws.com"
+	}
+
+	return &BedrockAdapter{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		signer: &awsSigner{
+			accessKeyID:     parts[0],
+			secretAccessKey: parts[1],
+			sessionToken:    sessionToken,
+			region:          region,
+			service:         "bedrock",
+		},
+		client: newStreamingHTTPClient(60 * time.Second),
+	}, nil
+}
+
+func (a *BedrockAdapter) supportsDocuments() {}
+
+func (a *BedrockAdapter) GetProvider() Provider {
+	return Provider("bedrock")
+}
+
+func (a *BedrockAdapter) ChatCompletion(ctx context.Context, req *models.ChatCompletionRequest) (*models.ChatCompletionResponse, error) {
+	bedrockReq, err := convertToBedrockRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+
+	resp, err := a.do(ctx, req.Model, "converse", bedrockReq)
+	if err != nil {
+		return nil, fmt.Errorf("bedrock api error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var bedrockResp BedrockConverseResponse
+	if err := json.NewDecoder(resp.Body).Decode(&bedrockResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return convertFromBedrockResponse(&bedrockResp, req.Model), nil
+}
+
+func (a *BedrockAdapter) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
+	bedrockReq, err := convertToBedrockRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+
+	resp, err := a.do(ctx, req.Model, "converse-stream", bedrockReq)
+	if err != nil {
+		return nil, fmt.Errorf("bedrock stream error: %w", err)
+	}
+
+	return newBedrockStream(resp.Body, req), nil
+}
+
+// do 签名并发送请求，action 为 "converse" 或 "converse-stream"
+func (a *BedrockAdapter) do(ctx context.Context, modelID, action string, bedrockReq *BedrockConverseRequest) (*http.Response, error) {
+	reqBody, err := json.Marshal(bedrockReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// 模型 ID 中的 ":" 以及推理配置文件 ARN 中的 "/" 需要编码
+	endpoint := a.baseURL + "/model/" + awsURIEncode(modelID) + "/" + action
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	a.signer.sign(httpReq, reqBody, time.Now())
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		var bedrockErr BedrockError
+		if json.Unmarshal(body, &bedrockErr) == nil && bedrockErr.Message != "" {
+			// X-Amzn-ErrorType 形如 "ValidationException:http://internal.amazon.com/..."
+			errorType, _, _ := strings.Cut(resp.Header.Get("X-Amzn-ErrorType"), ":")
+			if errorType == "" {
+				errorType = bedrockErr.Type
+			}
+			return nil, fmt.Errorf("status %d, %s: %s", resp.StatusCode, errorType, bedrockErr.Message)
+		}
+		return nil, fmt.Errorf("status %d, body: %s", resp.StatusCode, string(body))
+	}
+	return resp, nil
+}
+
+// BedrockConverseRequest Converse 接口请求，模型 ID 位于 URL 中
+type BedrockConverseRequest struct {
+	Messages        []BedrockMessage        `json:"messages"`
+	System          []BedrockContentBlock   `json:"system,omitempty"`
+	InferenceConfig *BedrockInferenceConfig `json:"inferenceConfig,omitempty"`
+	ToolConfig      *BedrockToolConfig      `json:"toolConfig,omitempty"`
+
+	// 模型专有参数，如 Claude 的 thinking
+	AdditionalModelRequestFields map[string]interface{} `json:"additionalModelRequestFields,omitempty"`
+}
+
+type BedrockMessage struct {
+	Role    string                `json:"role"`
+	Content []BedrockContentBlock `json:"content"`
+}
+
+// BedrockContentBlock 内容块，每个块只设置其中一个字段
+type BedrockContentBlock struct {
+	Text             string                   `json:"text,omitempty"`
+	Image            *BedrockImage            `json:"image,omitempty"`
+	Document         *BedrockDocument         `json:"document,omitempty"`
+	ToolUse          *BedrockToolUse          `json:"toolUse,omitempty"`
+	ToolResult       *BedrockToolResult       `json:"toolResult,omitempty"`
+	ReasoningContent *BedrockReasoningContent `json:"reasoningContent,omitempty"`
+}
+
+type BedrockImage struct {
+	Format string        `json:"format"` // "png"、"jpeg"、"gif"、"webp"
+	Source BedrockSource `json:"source"`
+}
+
+type BedrockDocument struct {
+	Format string        `json:"format"` // "pdf"、"txt" 等
+	Name   string        `json:"name"`
+	Source BedrockSource `json:"source"`
+}
+
+type BedrockSource struct {
+	Bytes string `json:"bytes"` // base64 数据
+}
+
+type BedrockToolUse struct {
+	ToolUseID string      `json:"toolUseId"`
+	Name      string      `json:"name"`
+	Input     interface{} `json:"input"`
+}
+
+type BedrockToolResult struct {
+	ToolUseID string                     `json:"toolUseId"`
+	Content   []BedrockToolResultContent `json:"content"`
+}
+
+type BedrockToolResultContent struct {
+	Text string      `json:"text,omitempty"`
+	JSON interface{} `json:"json,omitempty"`
+}
+
+type BedrockReasoningContent struct {
+	ReasoningText   *BedrockReasoningText `json:"reasoningText,omitempty"`
+	RedactedContent string                `json:"redactedContent,omitempty"`
+}
+
+type BedrockReasoningText struct {
+	Text      string `json:"text"`
+	Signature string `json:"signature,omitempty"`
+}
+
+type BedrockInferenceConfig struct {
+	MaxTokens     *int     `json:"maxTokens,omitempty"`
+	Temperature   *float64 `json:"temperature,omitempty"`
+	TopP          *float64 `json:"topP,omitempty"`
+	StopSequences []string `json:"stopSequences,omitempty"`
+}
+
+type BedrockToolConfig struct {
+	Tools      []BedrockTool          `json:"tools"`
+	ToolChoice map[string]interface{} `json:"toolChoice,omitempty"`
+}
+
+type BedrockTool struct {
+	ToolSpec BedrockToolSpec `json:"toolSpec"`
+}
+
+type BedrockToolSpec struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	InputSchema BedrockInputSchema `json:"inputSchema"`
+}
+
+type BedrockInputSchema struct {
+	JSON interface{} `json:"json"`
+}
+
+type BedrockConverseResponse struct {
+	Output struct {
+		Message *BedrockMessage `json:"message,omitempty"`
+	} `json:"output"`
+	StopReason string       `json:"stopReason"`
+	Usage      BedrockUsage `json:"usage"`
+}
+
+type BedrockUsage struct {
+	InputTokens  int `json:"inputTokens"`
+	OutputTokens int `json:"outputTokens"`
+	TotalTokens  int `json:"totalTokens"`
+}
+
+type BedrockError struct {
+	Type    string `json:"__type,omitempty"`
+	Message string `json:"message"`
+}
+
+func convertToBedrockRequest(req *models.ChatCompletionRequest) (*BedrockConverseRequest, error) {
+	bedrockReq := &BedrockConverseRequest{
+		Messages: make([]BedrockMessage, 0, len(req.Messages)),
+	}
+
+	// 旧的 function_call 消息没有 ID，按函数名关联调用与结果
+	functionCallIDs := make(map[string]string)
+	documents := 0
+
+	for _, msg := range req.Messages {
+		switch msg.Role {
+		case "system", "developer":
+			if text := contentToText(msg.Content); text != "" {
+				bedrockReq.System = append(bedrockReq.System, BedrockContentBlock{Text: text})
+			}
+
+		case "assistant":
+			// 思考块必须位于 assistant 消息的最前面
+			var blocks []BedrockContentBlock
+			for _, tb := range msg.ThinkingBlocks {
+				reasoning := &BedrockReasoningContent{RedactedContent: tb.Data}
+				if tb.Type == "thinking" {
+					reasoning = &BedrockReasoningContent{ReasoningText: &BedrockReasoningText{Text: tb.Thinking, Signature: tb.Signature}}
+				}
+				blocks = append(blocks, BedrockContentBlock{ReasoningContent: reasoning})
+			}
+			if text := contentToText(msg.Content); text != "" {
+				blocks = append(blocks, BedrockContentBlock{Text: text})
+			}
+			for _, tc := range msg.ToolCalls {
+				blocks = append(blocks, BedrockContentBlock{ToolUse: &BedrockToolUse{
+					ToolUseID: tc.ID,
+					Name:      tc.Function.Name,
+					Input:     parseToolArguments(tc.Function.Arguments),
+				}})
+			}
+			if msg.FunctionCall != nil {
+				id := newToolCallID()
+				functionCallIDs[msg.FunctionCall.Name] = id
+				blocks = append(blocks, BedrockContentBlock{ToolUse: &BedrockToolUse{
+					ToolUseID: id,
+					Name:      msg.FunctionCall.Name,
+					Input:     parseToolArguments(msg.FunctionCall.Arguments),
+				}})
+			}
+			bedrockReq.Messages = appendBedrockBlocks(bedrockReq.Messages, "assistant", blocks)
+
+		case "tool", "function":
+			// 工具结果以 user 角色发送，连续的多个结果合并到同一轮
+			toolUseID := msg.ToolCallID
+			if toolUseID == "" {
+				toolUseID = functionCallIDs[msg.Name]
+			}
+			bedrockReq.Messages = appendBedrockBlocks(bedrockReq.Messages, "user", []BedrockContentBlock{{
+				ToolResult: &BedrockToolResult{
+					ToolUseID: toolUseID,
+					Content:   []BedrockToolResultContent{toBedrockToolResultContent(contentToText(msg.Content))},
+				},
+			}})
+
+		default:
+			blocks, err := toBedrockContentBlocks(msg.Content, &documents)
+			if err != nil {
+				return nil, err
+			}
+			bedrockReq.Messages = appendBedrockBlocks(bedrockReq.Messages, "user", blocks)
+		}
+	}
+	if len(bedrockReq.Messages) == 0 {
+		return nil, fmt.Errorf("at least one message is required")
+	}
+
+	inference := &BedrockInferenceConfig{
+		MaxTokens:     maxOutputTokens(req),
+		Temperature:   req.Temperature,
+		TopP:          req.TopP,
+		StopSequences: req.Stop,
+	}
+	if inference.MaxTokens != nil || inference.Temperature != nil || inference.TopP != nil || len(inference.StopSequences) > 0 {
+		bedrockReq.InferenceConfig = inference
+	}
+
+	// Bedrock 上的 Claude 通过 additionalModelRequestFields 开启扩展思考
+	var thinking *models.ThinkingConfig
+	if strings.Contains(req.Model, "anthropic.claude") {
+		var err error
+		thinking, err = claudeThinking(req)
+		if err != nil {
+			return nil, err
+		}
+		if thinking != nil {
+			bedrockReq.AdditionalModelRequestFields = map[string]interface{}{
+				"thinking": map[string]interface{}{"type": "enabled", "budget_tokens": thinking.BudgetTokens},
+			}
+			// maxTokens 需大于思考预算，未指定时在预算之外再留出回复空间
+			if bedrockReq.InferenceConfig == nil {
+				bedrockReq.InferenceConfig = &BedrockInferenceConfig{}
+			}
+			if bedrockReq.InferenceConfig.MaxTokens == nil {
+				maxTokens := thinking.BudgetTokens + 4096
+				bedrockReq.InferenceConfig.MaxTokens = &maxTokens
+			}
+			// 开启思考时不能调整 temperature 和 topP，忽略这两个采样参数
+			bedrockReq.InferenceConfig.Temperature = nil
+			bedrockReq.InferenceConfig.TopP = nil
+		}
+	}
+
+	// 转换工具定义，兼容旧的 Functions 格式
+	functions := make([]models.FunctionDefinition, 0, len(req.Tools)+len(req.Functions))
+	for _, tool := range req.Tools {
+		functions = append(functions, tool.Function)
+	}
+	functions = append(functions, req.Functions...)
+
+	toolChoice := req.ToolChoice
+	if toolChoice == nil {
+		toolChoice = req.FunctionCall
+	}
+	// Converse 没有 none 模式：没有工具调用历史时不发送 toolConfig，否则无法禁止调用工具
+	if toolChoice == "none" && len(functions) > 0 {
+		if bedrockHasToolHistory(bedrockReq.Messages) {
+			return nil, fmt.Errorf("tool_choice \"none\" is not supported by bedrock when the conversation contains tool calls")
+		}
+		functions = nil
+	}
+
+	if len(functions) > 0 {
+		config := &BedrockToolConfig{Tools: make([]BedrockTool, 0, len(functions))}
+		for _, fn := range functions {
+			schema := fn.Parameters
+			if schema == nil {
+				schema = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+			}
+			config.Tools = append(config.Tools, BedrockTool{ToolSpec: BedrockToolSpec{
+				Name:        fn.Name,
+				Description: fn.Description,
+				InputSchema: BedrockInputSchema{JSON: schema},
+			}})
+		}
+
+		choice, err := toBedrockToolChoice(toolChoice)
+		if err != nil {
+			return nil, err
+		}
+		// 开启思考时只支持 auto，不能强制调用工具
+		if thinking != nil && choice != nil {
+			return nil, fmt.Errorf("tool_choice %v is not supported when thinking is enabled", toolChoice)
+		}
+		config.ToolChoice = choice
+		bedrockReq.ToolConfig = config
+	}
+
+	return bedrockReq, nil
+}
+
+// toBedrockContentBlocks 将 user 消息内容转换为内容块
+// Converse 只接受内联的图片数据，图片需以 data URI 形式提供
+func toBedrockContentBlocks(content interface{}, documents *int) ([]BedrockContentBlock, error) {
+	parts, ok := contentParts(content)
+	if !ok {
+		return []BedrockContentBlock{{Text: contentToText(content)}}, nil
+	}
+	blocks := make([]BedrockContentBlock, 0, len(parts))
+	for _, part := range parts {
+		switch part.Type {
+		case "text":
+			if part.Text != "" {
+				blocks = append(blocks, BedrockContentBlock{Text: part.Text})
+			}
+		case "image_url":
+			if part.ImageURL == nil {
+				return nil, fmt.Errorf("image_url content part requires a url")
+			}
+			mimeType, data, ok := parseDataURI(part.ImageURL.URL)
+			if !ok {
+				return nil, fmt.Errorf("bedrock only supports images as base64 data URIs")
+			}
+			format, ok := strings.CutPrefix(mimeType, "image/")
+			if !ok {
+				return nil, fmt.Errorf("unsupported image media type %q", mimeType)
+			}
+			blocks = append(blocks, BedrockContentBlock{Image: &BedrockImage{Format: format, Source: BedrockSource{Bytes: data}}})
+		case "document":
+			if part.Document == nil {
+				return nil, fmt.Errorf("document content part requires a document")
+			}
+			*documents++
+			doc, err := toBedrockDocument(part.Document, *documents)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, BedrockContentBlock{Document: doc})
+		default:
+			return nil, fmt.Errorf("unsupported content part type %q", part.Type)
+		}
+	}
+	return blocks, nil
+}
+
+// toBedrockDocument 转换文档，纯文本以 base64 编码后作为 txt 文档发送
+// 文档名称在同一请求中必须唯一，且只能包含字母、数字、空白、"-"、"()" 和 "[]"
+func toBedrockDocument(doc *models.Document, n int) (*BedrockDocument, error) {
+	var format, data string
+	switch doc.MediaType {
+	case "application/pdf":
+		format, data = "pdf", doc.Data
+	case "text/plain":
+		format, data = "txt", base64.StdEncoding.EncodeToString([]byte(doc.Data))
+	default:
+		return nil, fmt.Errorf("unsupported document media type %q", doc.MediaType)
+	}
+
+	name := strings.Join(strings.FieldsFunc(doc.Title, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-()[]", r))
+	}), " ")
+	if name == "" {
+		name = "document"
+	}
+	return &BedrockDocument{
+		Format: format,
+		Name:   fmt.Sprintf("%s %d", name, n),
+		Source: BedrockSource{Bytes: data},
+	}, nil
+}
+
+// toBedrockToolResultContent 工具结果为 JSON 对象时以 json 发送，否则以 text 发送
+func toBedrockToolResultContent(content string) BedrockToolResultContent {
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &result); err == nil && result != nil {
+		return BedrockToolResultContent{JSON: result}
+	}
+	return BedrockToolResultContent{Text: content}
+}
+
+// appendBedrockBlocks 追加一轮消息，与上一轮角色相同时合并内容块（Converse 要求 user/assistant 交替）
+func appendBedrockBlocks(messages []BedrockMessage, role string, blocks []BedrockContentBlock) []BedrockMessage {
+	if len(blocks) == 0 {
+		return messages
+	}
+	if n := len(messages); n > 0 && messages[n-1].Role == role {
+		messages[n-1].Content = append(messages[n-1].Content, blocks...)
+		return messages
+	}
+	return append(messages, BedrockMessage{Role: role, Content: blocks})
+}
+
+// bedrockHasToolHistory 判断消息中是否包含工具调用或工具结果，此时 Converse 要求携带 toolConfig
+func bedrockHasToolHistory(messages []BedrockMessage) bool {
+	for _, msg := range messages {
+		for _, block := range msg.Content {
+			if block.ToolUse != nil || block.ToolResult != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// toBedrockToolChoice 将 OpenAI 的 tool_choice 转换为 {auto:{}}、{any:{}} 或 {tool:{name}}
+// Converse 没有 none 模式，"none" 由调用方在转换前处理
+func toBedrockToolChoice(toolChoice interface{}) (map[string]interface{}, error) {
+	switch v := toolChoice.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		switch v {
+		case "auto":
+			return nil, nil
+		case "required", "any":
+			return map[string]interface{}{"any": map[string]interface{}{}}, nil
+		}
+	case map[string]interface{}:
+		// {"type": "function", "function": {"name": ...}} 或旧的 {"name": ...}
+		if fn, ok := v["function"].(map[string]interface{}); ok {
+			if name, ok := fn["name"].(string); ok && name != "" {
+				return map[string]interface{}{"tool": map[string]interface{}{"name": name}}, nil
+			}
+		}
+		if name, ok := v["name"].(string); ok && name != "" {
+			return map[string]interface{}{"tool": map[string]interface{}{"name": name}}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported tool_choice %v", toolChoice)
+}
+
+func convertFromBedrockResponse(bedrockResp *BedrockConverseResponse, modelName string) *models.ChatCompletionResponse {
+	message := models.ChatMessage{Role: "assistant"}
+	var text strings.Builder
+	if bedrockResp.Output.Message != nil {
+		for _, block := range bedrockResp.Output.Message.Content {
+			switch {
+			case block.ToolUse != nil:
+				arguments, err := json.Marshal(block.ToolUse.Input)
+				if err != nil || block.ToolUse.Input == nil {
+					arguments = []byte("{}")
+				}
+				message.ToolCalls = append(message.ToolCalls, models.ToolCall{
+					ID:   block.ToolUse.ToolUseID,
+					Type: "function",
+					Function: models.FunctionCall{
+						Name:      block.ToolUse.Name,
+						Arguments: string(arguments),
+					},
+				})
+			case block.ReasoningContent != nil:
+				if rt := block.ReasoningContent.ReasoningText; rt != nil {
+					message.ReasoningContent += rt.Text
+					message.ThinkingBlocks = append(message.ThinkingBlocks, models.ThinkingBlock{
+						Type:      "thinking",
+						Thinking:  rt.Text,
+						Signature: rt.Signature,
+					})
+				} else if block.ReasoningContent.RedactedContent != "" {
+					message.ThinkingBlocks = append(message.ThinkingBlocks, models.ThinkingBlock{
+						Type: "redacted_thinking",
+						Data: block.ReasoningContent.RedactedContent,
+					})
+				}
+			default:
+				text.WriteString(block.Text)
+			}
+		}
+	}
+	message.Content = text.String()
+
+	return &models.ChatCompletionResponse{
+		ID:      newResponseID(),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   modelName,
+		Choices: []models.ChatCompletionChoice{{
+			Index:        0,
+			Message:      message,
+			FinishReason: mapBedrockStopReason(bedrockResp.StopReason),
+		}},
+		Usage: models.Usage{
+			PromptTokens:     bedrockResp.Usage.InputTokens,
+			CompletionTokens: bedrockResp.Usage.OutputTokens,
+			TotalTokens:      bedrockResp.Usage.TotalTokens,
+		},
+	}
+}
+
+// mapBedrockStopReason 将 Converse 的 stopReason 映射为 OpenAI 的 finish_reason
+func mapBedrockStopReason(reason string) string {
+	switch reason {
+	case "end_turn", "stop_sequence":
+		return "stop"
+	case "max_tokens":
+		return "length"
+	case "tool_use":
+		return "tool_calls"
+	case "guardrail_intervened", "content_filtered":
+		return "content_filter"
+	default:
+		return reason
+	}
+}
@@ -0,0 +1,194 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// BedrockStreamEvent ConverseStream 事件载荷，事件类型位于消息头 :event-type 中
+type BedrockStreamEvent struct {
+	Role              string              `json:"role,omitempty"`       // messageStart
+	ContentBlockIndex int                 `json:"contentBlockIndex"`    // contentBlockStart / Delta / Stop
+	Start             *BedrockBlockStart  `json:"start,omitempty"`      // contentBlockStart
+	Delta             *BedrockStreamDelta `json:"delta,omitempty"`      // contentBlockDelta
+	StopReason        string              `json:"stopReason,omitempty"` // messageStop
+	Usage             *BedrockUsage       `json:"usage,omitempty"`      // metadata
+	Message           string              `json:"message,omitempty"`    // 异常
+}
+
+type BedrockBlockStart struct {
+	ToolUse *BedrockToolUse `json:"toolUse,omitempty"`
+}
+
+type BedrockStreamDelta struct {
+	Text    string `json:"text,omitempty"`
+	ToolUse *struct {
+		Input string `json:"input"`
+	} `json:"toolUse,omitempty"`
+	ReasoningContent *struct {
+		Text            string `json:"text,omitempty"`
+		Signature       string `json:"signature,omitempty"`
+		RedactedContent string `json:"redactedContent,omitempty"`
+	} `json:"reasoningContent,omitempty"`
+}
+
+// bedrockStreamTranslator 将 ConverseStream 的二进制事件流转换为 OpenAI chat.completion.chunk
+type bedrockStreamTranslator struct {
+	reader  *eventStreamReader
+	id      string
+	model   string
+	created int64
+
+	// content block 下标到 OpenAI tool_calls 下标的映射
+	toolIndexes map[int]int
+	// 正在接收的思考块，块结束时连同签名一次性输出
+	thinking map[int]*models.ThinkingBlock
+}
+
+func newBedrockStream(body io.ReadCloser, req *models.ChatCompletionRequest) io.ReadCloser {
+	t := &bedrockStreamTranslator{
+		reader:      newEventStreamReader(body),
+		id:          newResponseID(),
+		model:       req.Model,
+		created:     time.Now().Unix(),
+		toolIndexes: make(map[int]int),
+		thinking:    make(map[int]*models.ThinkingBlock),
+	}
+	return newChunkStream(req, t.next, body)
+}
+
+func (t *bedrockStreamTranslator) next() ([]*models.ChatCompletionStreamResponse, error) {
+	msg, err := t.reader.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	var event BedrockStreamEvent
+	if len(msg.Payload) > 0 {
+		if err := json.Unmarshal(msg.Payload, &event); err != nil {
+			return nil, fmt.Errorf("failed to decode bedrock stream event: %w", err)
+		}
+	}
+
+	switch msg.Headers[":message-type"] {
+	case "exception":
+		return nil, fmt.Errorf("bedrock stream error: %s: %s", msg.Headers[":exception-type"], event.Message)
+	case "error":
+		return nil, fmt.Errorf("bedrock stream error: %s: %s", msg.Headers[":error-code"], msg.Headers[":error-message"])
+	}
+
+	switch msg.Headers[":event-type"] {
+	case "messageStart":
+		return t.chunk(models.ChatMessageDelta{Role: "assistant"}, ""), nil
+
+	case "contentBlockStart":
+		if event.Start == nil || event.Start.ToolUse == nil {
+			return nil, nil
+		}
+		index := len(t.toolIndexes)
+		t.toolIndexes[event.ContentBlockIndex] = index
+		return t.chunk(models.ChatMessageDelta{
+			ToolCalls: []models.ToolCall{{
+				Index: &index,
+				ID:    event.Start.ToolUse.ToolUseID,
+				Type:  "function",
+				Function: models.FunctionCall{
+					Name: event.Start.ToolUse.Name,
+				},
+			}},
+		}, ""), nil
+
+	case "contentBlockDelta":
+		if event.Delta == nil {
+			return nil, nil
+		}
+		switch {
+		case event.Delta.ToolUse != nil:
+			index, ok := t.toolIndexes[event.ContentBlockIndex]
+			if !ok || event.Delta.ToolUse.Input == "" {
+				return nil, nil
+			}
+			return t.chunk(models.ChatMessageDelta{
+				ToolCalls: []models.ToolCall{{
+					Index: &index,
+					Function: models.FunctionCall{
+						Arguments: event.Delta.ToolUse.Input,
+					},
+				}},
+			}, ""), nil
+		case event.Delta.ReasoningContent != nil:
+			rc := event.Delta.ReasoningContent
+			if rc.RedactedContent != "" {
+				return t.chunk(models.ChatMessageDelta{
+					ThinkingBlocks: []models.ThinkingBlock{{Type: "redacted_thinking", Data: rc.RedactedContent}},
+				}, ""), nil
+			}
+			block, ok := t.thinking[event.ContentBlockIndex]
+			if !ok {
+				block = &models.ThinkingBlock{Type: "thinking"}
+				t.thinking[event.ContentBlockIndex] = block
+			}
+			block.Thinking += rc.Text
+			block.Signature += rc.Signature
+			if rc.Text == "" {
+				return nil, nil
+			}
+			return t.chunk(models.ChatMessageDelta{ReasoningContent: rc.Text}, ""), nil
+		case event.Delta.Text != "":
+			return t.chunk(models.ChatMessageDelta{Content: event.Delta.Text}, ""), nil
+		}
+		return nil, nil
+
+	case "contentBlockStop":
+		block, ok := t.thinking[event.ContentBlockIndex]
+		if !ok {
+			return nil, nil
+		}
+		delete(t.thinking, event.ContentBlockIndex)
+		return t.chunk(models.ChatMessageDelta{ThinkingBlocks: []models.ThinkingBlock{*block}}, ""), nil
+
+	case "messageStop":
+		finishReason := "stop"
+		if event.StopReason != "" {
+			finishReason = mapBedrockStopReason(event.StopReason)
+		}
+		return t.chunk(models.ChatMessageDelta{}, finishReason), nil
+
+	case "metadata":
+		// 用量在结束块之后单独下发，由 chunkStream 按 stream_options 决定是否发送
+		if event.Usage == nil {
+			return nil, nil
+		}
+		return []*models.ChatCompletionStreamResponse{{
+			ID:      t.id,
+			Object:  "chat.completion.chunk",
+			Created: t.created,
+			Model:   t.model,
+			Usage: &models.Usage{
+				PromptTokens:     event.Usage.InputTokens,
+				CompletionTokens: event.Usage.OutputTokens,
+				TotalTokens:      event.Usage.TotalTokens,
+			},
+		}}, nil
+	}
+
+	return nil, nil
+}
+
+func (t *bedrockStreamTranslator) chunk(delta models.ChatMessageDelta, finishReason string) []*models.ChatCompletionStreamResponse {
+	return []*models.ChatCompletionStreamResponse{{
+		ID:      t.id,
+		Object:  "chat.completion.chunk",
+		Created: t.created,
+		Model:   t.model,
+		Choices: []models.ChatCompletionStreamChoice{{
+			Index:        0,
+			Delta:        delta,
+			FinishReason: finishReason,
+		}},
+	}}
+}
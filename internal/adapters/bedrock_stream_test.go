@@ -0,0 +1,201 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// encodeEventStreamMessage 按 application/vnd.amazon.eventstream 格式编码一条消息，头均为字符串类型
+func encodeEventStreamMessage(headers map[string]string, payload []byte) []byte {
+	var hdr bytes.Buffer
+	for name, value := range headers {
+		hdr.WriteByte(byte(len(name)))
+		hdr.WriteString(name)
+		hdr.WriteByte(7)
+		binary.Write(&hdr, binary.BigEndian, uint16(len(value)))
+		hdr.WriteString(value)
+	}
+
+	totalLen := 12 + hdr.Len() + len(payload) + 4
+	msg := make([]byte, 0, totalLen)
+	msg = binary.BigEndian.AppendUint32(msg, uint32(totalLen))
+	msg = binary.BigEndian.AppendUint32(msg, uint32(hdr.Len()))
+	msg = binary.BigEndian.AppendUint32(msg, crc32.ChecksumIEEE(msg[:8]))
+	msg = append(msg, hdr.Bytes()...)
+	msg = append(msg, payload...)
+	return binary.BigEndian.AppendUint32(msg, crc32.ChecksumIEEE(msg))
+}
+
+func bedrockEvent(eventType, payload string) []byte {
+	return encodeEventStreamMessage(map[string]string{
+		":event-type":   eventType,
+		":content-type": "application/json",
+		":message-type": "event",
+	}, []byte(payload))
+}
+
+func TestEventStreamReader(t *testing.T) {
+	data := append(bedrockEvent("messageStart", `{"role":"assistant"}`), bedrockEvent("messageStop", `{"stopReason":"end_turn"}`)...)
+	reader := newEventStreamReader(bytes.NewReader(data))
+
+	msg, err := reader.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if msg.Headers[":event-type"] != "messageStart" || string(msg.Payload) != `{"role":"assistant"}` {
+		t.Errorf("unexpected message: %v %s", msg.Headers, msg.Payload)
+	}
+	if msg, err = reader.Next(); err != nil || msg.Headers[":event-type"] != "messageStop" {
+		t.Errorf("unexpected second message: %v, %v", msg, err)
+	}
+	if _, err := reader.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+
+	// 载荷被篡改时校验失败
+	corrupt := bedrockEvent("messageStart", `{"role":"assistant"}`)
+	corrupt[len(corrupt)-6] ^= 0xff
+	if _, err := newEventStreamReader(bytes.NewReader(corrupt)).Next(); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("expected checksum error, got %v", err)
+	}
+}
+
+func TestBedrockAdapter_ChatCompletionStream(t *testing.T) {
+	events := [][]byte{
+		bedrockEvent("messageStart", `{"role":"assistant"}`),
+		bedrockEvent("contentBlockDelta", `{"contentBlockIndex":0,"delta":{"reasoningContent":{"text":"Need weather."}}}`),
+		bedrockEvent("contentBlockDelta", `{"contentBlockIndex":0,"delta":{"reasoningContent":{"signature":"sig"}}}`),
+		bedrockEvent("contentBlockStop", `{"contentBlockIndex":0}`),
+		bedrockEvent("contentBlockDelta", `{"contentBlockIndex":1,"delta":{"text":"Checking"}}`),
+		bedrockEvent("contentBlockStop", `{"contentBlockIndex":1}`),
+		bedrockEvent("contentBlockStart", `{"contentBlockIndex":2,"start":{"toolUse":{"toolUseId":"tooluse_1","name":"get_weather"}}}`),
+		bedrockEvent("contentBlockDelta", `{"contentBlockIndex":2,"delta":{"toolUse":{"input":"{\"city\":"}}}`),
+		bedrockEvent("contentBlockDelta", `{"contentBlockIndex":2,"delta":{"toolUse":{"input":"\"Paris\"}"}}}`),
+		bedrockEvent("contentBlockStop", `{"contentBlockIndex":2}`),
+		bedrockEvent("messageStop", `{"stopReason":"tool_use"}`),
+		bedrockEvent("metadata", `{"usage":{"inputTokens":12,"outputTokens":8,"totalTokens":20},"metrics":{"latencyMs":100}}`),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verifySigV4(t, r, body, "AKIDtest", "secret", "us-east-1", "bedrock")
+		if r.URL.Path != "/model/meta.llama3-70b-instruct-v1:0/converse-stream" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+		for _, event := range events {
+			w.Write(event)
+		}
+	}))
+	defer server.Close()
+
+	adapter, err := NewBedrockAdapter("AKIDtest:secret", server.URL)
+	if err != nil {
+		t.Fatalf("NewBedrockAdapter() error = %v", err)
+	}
+
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:         "meta.llama3-70b-instruct-v1:0",
+		Messages:      []models.ChatMessage{{Role: "user", Content: "Weather in Paris?"}},
+		StreamOptions: &models.StreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	var chunks []models.ChatCompletionStreamResponse
+	sse := NewSSEReader(stream)
+	for {
+		event, err := sse.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read stream: %v", err)
+		}
+		if string(event.Data) == SSEDone {
+			continue
+		}
+		var chunk models.ChatCompletionStreamResponse
+		if err := json.Unmarshal(event.Data, &chunk); err != nil {
+			t.Fatalf("invalid chunk %s: %v", event.Data, err)
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	var content, reasoning, arguments, finishReason string
+	var thinking []models.ThinkingBlock
+	var usage *models.Usage
+	for _, chunk := range chunks {
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			content += choice.Delta.Content
+			reasoning += choice.Delta.ReasoningContent
+			thinking = append(thinking, choice.Delta.ThinkingBlocks...)
+			for _, tc := range choice.Delta.ToolCalls {
+				if tc.Index == nil || *tc.Index != 0 {
+					t.Errorf("unexpected tool call index: %+v", tc)
+				}
+				if tc.ID != "" && (tc.ID != "tooluse_1" || tc.Function.Name != "get_weather") {
+					t.Errorf("unexpected tool call start: %+v", tc)
+				}
+				arguments += tc.Function.Arguments
+			}
+			if choice.FinishReason != "" {
+				finishReason = choice.FinishReason
+			}
+		}
+	}
+
+	if content != "Checking" || reasoning != "Need weather." || arguments != `{"city":"Paris"}` || finishReason != "tool_calls" {
+		t.Errorf("unexpected stream result: content=%q reasoning=%q arguments=%q finish=%q", content, reasoning, arguments, finishReason)
+	}
+	if len(thinking) != 1 || thinking[0].Thinking != "Need weather." || thinking[0].Signature != "sig" {
+		t.Errorf("unexpected thinking blocks: %+v", thinking)
+	}
+	if usage == nil || usage.PromptTokens != 12 || usage.CompletionTokens != 8 || usage.TotalTokens != 20 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+}
+
+func TestBedrockAdapter_ChatCompletionStreamException(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+		w.Write(bedrockEvent("messageStart", `{"role":"assistant"}`))
+		w.Write(encodeEventStreamMessage(map[string]string{
+			":message-type":   "exception",
+			":exception-type": "throttlingException",
+			":content-type":   "application/json",
+		}, []byte(`{"message":"Too many requests"}`)))
+	}))
+	defer server.Close()
+
+	adapter, _ := NewBedrockAdapter("AKID:secret", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "meta.llama3-70b-instruct-v1:0",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	_, err = io.ReadAll(stream)
+	if err == nil || !strings.Contains(err.Error(), "throttlingException: Too many requests") {
+		t.Errorf("expected throttling error, got %v", err)
+	}
+}
@@ -0,0 +1,349 @@
+package adapters
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// verifySigV4 按 AWS 文档的步骤独立计算签名并与请求中的签名比较
+func verifySigV4(t *testing.T, r *http.Request, body []byte, accessKeyID, secretAccessKey, region, service string) {
+	t.Helper()
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	if _, err := time.Parse("20060102T150405Z", amzDate); err != nil {
+		t.Fatalf("invalid X-Amz-Date %q", amzDate)
+	}
+	date := amzDate[:8]
+
+	hash := func(s []byte) string {
+		sum := sha256.Sum256(s)
+		return hex.EncodeToString(sum[:])
+	}
+	mac := func(key []byte, s string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(s))
+		return h.Sum(nil)
+	}
+
+	// 签名头：host、content-type 和所有 x-amz-* 头
+	names := []string{"host"}
+	for name := range r.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var canonicalHeaders string
+	for _, name := range names {
+		value := r.Host
+		if name != "host" {
+			value = r.Header.Get(name)
+		}
+		canonicalHeaders += name + ":" + value + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// 路径中已编码的 "%" 需要再编码一次
+	canonicalURI := strings.ReplaceAll(r.URL.EscapedPath(), "%", "%25")
+	canonicalRequest := r.Method + "\n" + canonicalURI + "\n\n" + canonicalHeaders + "\n" + signedHeaders + "\n" + hash(body)
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hash([]byte(canonicalRequest))
+	key := mac(mac(mac(mac([]byte("AWS4"+secretAccessKey), date), region), service), "aws4_request")
+	signature := hex.EncodeToString(mac(key, stringToSign))
+
+	want := fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKeyID, scope, signedHeaders, signature)
+	if got := r.Header.Get("Authorization"); got != want {
+		t.Errorf("unexpected Authorization\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestAWSSigner_KnownVector(t *testing.T) {
+	// AWS SigV4 测试套件中的 get-vanilla 用例
+	req, _ := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	signer := &awsSigner{
+		accessKeyID:     "AKIDEXAMPLE",
+		secretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		region:          "us-east-1",
+		service:         "service",
+	}
+	signer.sign(req, nil, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("unexpected Authorization\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestNewBedrockAdapter(t *testing.T) {
+	if _, err := NewBedrockAdapter("access-key-only", ""); err == nil {
+		t.Error("expected error for api key without secret key")
+	}
+
+	adapter, err := NewBedrockAdapter("AKID:secret", "https://bedrock-runtime.eu-west-1.amazonaws.com")
+	if err != nil {
+		t.Fatalf("NewBedrockAdapter() error = %v", err)
+	}
+	if region := adapter.(*BedrockAdapter).signer.region; region != "eu-west-1" {
+		t.Errorf("expected region inferred from base url, got %q", region)
+	}
+
+	adapter, err = NewBedrockAdapterWithOptions("AKID:secret:token", "", AdapterOptions{Region: "ap-northeast-1"})
+	if err != nil {
+		t.Fatalf("NewBedrockAdapterWithOptions() error = %v", err)
+	}
+	bedrock := adapter.(*BedrockAdapter)
+	if bedrock.baseURL != "https://bedrock-runtime.ap-northeast-1.amazonaws.com" || bedrock.signer.sessionToken != "token" {
+		t.Errorf("unexpected adapter: %s, %+v", bedrock.baseURL, bedrock.signer)
+	}
+}
+
+func TestBedrockAdapter_ChatCompletion(t *testing.T) {
+	var gotReq map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verifySigV4(t, r, body, "AKIDtest", "secret", "us-west-2", "bedrock")
+
+		if got := r.Header.Get("X-Amz-Security-Token"); got != "session-token" {
+			t.Errorf("expected session token header, got %q", got)
+		}
+		if r.URL.EscapedPath() != "/model/anthropic.claude-3-5-sonnet-20240620-v1%3A0/converse" {
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+		}
+		json.Unmarshal(body, &gotReq)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"output":{"message":{"role":"assistant","content":[{"text":"Let me check."},{"toolUse":{"toolUseId":"tooluse_1","name":"get_weather","input":{"city":"Paris"}}}]}},"stopReason":"tool_use","usage":{"inputTokens":20,"outputTokens":10,"totalTokens":30}}`)
+	}))
+	defer server.Close()
+
+	adapter, err := NewBedrockAdapterWithOptions("AKIDtest:secret:session-token", server.URL, AdapterOptions{Region: "us-west-2"})
+	if err != nil {
+		t.Fatalf("NewBedrockAdapterWithOptions() error = %v", err)
+	}
+
+	maxTokens := 512
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "anthropic.claude-3-5-sonnet-20240620-v1:0",
+		Messages: []models.ChatMessage{
+			{Role: "system", Content: "Be brief."},
+			{Role: "user", Content: "Weather in Paris and Rome?"},
+			{Role: "assistant", ToolCalls: []models.ToolCall{
+				{ID: "call_1", Type: "function", Function: models.FunctionCall{Name: "get_weather", Arguments: `{"city":"Rome"}`}},
+			}},
+			{Role: "tool", ToolCallID: "call_1", Content: `{"temp":20}`},
+		},
+		MaxTokens: &maxTokens,
+		Stop:      []string{"END"},
+		Tools: []models.Tool{{Type: "function", Function: models.FunctionDefinition{
+			Name:       "get_weather",
+			Parameters: map[string]interface{}{"type": "object"},
+		}}},
+		ToolChoice: "required",
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	// 请求转换
+	if system := gotReq["system"].([]interface{}); system[0].(map[string]interface{})["text"] != "Be brief." {
+		t.Errorf("unexpected system: %v", system)
+	}
+	inference := gotReq["inferenceConfig"].(map[string]interface{})
+	if inference["maxTokens"] != float64(512) || inference["stopSequences"].([]interface{})[0] != "END" {
+		t.Errorf("unexpected inferenceConfig: %v", inference)
+	}
+	messages := gotReq["messages"].([]interface{})
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d: %v", len(messages), messages)
+	}
+	toolUse := messages[1].(map[string]interface{})["content"].([]interface{})[0].(map[string]interface{})["toolUse"].(map[string]interface{})
+	if toolUse["toolUseId"] != "call_1" || toolUse["input"].(map[string]interface{})["city"] != "Rome" {
+		t.Errorf("unexpected toolUse: %v", toolUse)
+	}
+	toolResult := messages[2].(map[string]interface{})["content"].([]interface{})[0].(map[string]interface{})["toolResult"].(map[string]interface{})
+	if toolResult["toolUseId"] != "call_1" || toolResult["content"].([]interface{})[0].(map[string]interface{})["json"] == nil {
+		t.Errorf("unexpected toolResult: %v", toolResult)
+	}
+	toolConfig := gotReq["toolConfig"].(map[string]interface{})
+	spec := toolConfig["tools"].([]interface{})[0].(map[string]interface{})["toolSpec"].(map[string]interface{})
+	if spec["name"] != "get_weather" || spec["inputSchema"].(map[string]interface{})["json"] == nil {
+		t.Errorf("unexpected toolSpec: %v", spec)
+	}
+	if _, ok := toolConfig["toolChoice"].(map[string]interface{})["any"]; !ok {
+		t.Errorf("expected toolChoice any, got %v", toolConfig["toolChoice"])
+	}
+
+	// 响应转换
+	choice := resp.Choices[0]
+	if choice.FinishReason != "tool_calls" || choice.Message.Content != "Let me check." {
+		t.Errorf("unexpected choice: %+v", choice)
+	}
+	if len(choice.Message.ToolCalls) != 1 || choice.Message.ToolCalls[0].ID != "tooluse_1" || choice.Message.ToolCalls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected tool calls: %+v", choice.Message.ToolCalls)
+	}
+	if resp.Usage.TotalTokens != 30 || resp.Model != "anthropic.claude-3-5-sonnet-20240620-v1:0" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestBedrockAdapter_ChatCompletionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Amzn-ErrorType", "ValidationException:http://internal.amazon.com/coral/com.amazon.bedrock/")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"message":"The provided model identifier is invalid."}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewBedrockAdapter("AKID:secret", server.URL)
+	_, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "bad-model",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "ValidationException: The provided model identifier is invalid.") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestConvertToBedrockRequest_Content(t *testing.T) {
+	req, err := convertToBedrockRequest(&models.ChatCompletionRequest{
+		Model:           "anthropic.claude-3-7-sonnet-20250219-v1:0",
+		ReasoningEffort: "low",
+		Messages: []models.ChatMessage{{Role: "user", Content: []models.ContentPart{
+			{Type: "text", Text: "Summarize"},
+			{Type: "image_url", ImageURL: &models.ImageURL{URL: "data:image/png;base64,cG5n"}},
+			{Type: "document", Document: &models.Document{MediaType: "text/plain", Data: "hello", Title: "Q3: report.txt"}},
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("convertToBedrockRequest() error = %v", err)
+	}
+
+	blocks := req.Messages[0].Content
+	if len(blocks) != 3 || blocks[0].Text != "Summarize" {
+		t.Fatalf("unexpected blocks: %+v", blocks)
+	}
+	if img := blocks[1].Image; img == nil || img.Format != "png" || img.Source.Bytes != "cG5n" {
+		t.Errorf("unexpected image block: %+v", img)
+	}
+	if doc := blocks[2].Document; doc == nil || doc.Format != "txt" || doc.Name != "Q3 report txt 1" || doc.Source.Bytes != "aGVsbG8=" {
+		t.Errorf("unexpected document block: %+v", doc)
+	}
+
+	// Claude 按 reasoning_effort 开启思考，maxTokens 需大于思考预算
+	thinking := req.AdditionalModelRequestFields["thinking"].(map[string]interface{})
+	if thinking["budget_tokens"] != 1024 || req.InferenceConfig == nil || *req.InferenceConfig.MaxTokens != 1024+4096 {
+		t.Errorf("unexpected thinking config: %v, %+v", thinking, req.InferenceConfig)
+	}
+
+	if _, err := convertToBedrockRequest(&models.ChatCompletionRequest{
+		Model: "meta.llama3-70b-instruct-v1:0",
+		Messages: []models.ChatMessage{{Role: "user", Content: []models.ContentPart{
+			{Type: "image_url", ImageURL: &models.ImageURL{URL: "https://example.com/cat.png"}},
+		}}},
+	}); err == nil {
+		t.Error("expected error for remote image url")
+	}
+}
+
+func TestConvertToBedrockRequest_ThinkingBudgetWithinMaxTokens(t *testing.T) {
+	maxTokens := 4096
+	req, err := convertToBedrockRequest(&models.ChatCompletionRequest{
+		Model:           "anthropic.claude-3-7-sonnet-20250219-v1:0",
+		Messages:        []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		MaxTokens:       &maxTokens,
+		ReasoningEffort: "high",
+	})
+	if err != nil {
+		t.Fatalf("convertToBedrockRequest() error = %v", err)
+	}
+	thinking := req.AdditionalModelRequestFields["thinking"].(map[string]interface{})
+	if thinking["budget_tokens"] != 4095 || *req.InferenceConfig.MaxTokens != 4096 {
+		t.Errorf("unexpected thinking config: %v, %+v", thinking, req.InferenceConfig)
+	}
+
+	_, err = convertToBedrockRequest(&models.ChatCompletionRequest{
+		Model:     "anthropic.claude-3-7-sonnet-20250219-v1:0",
+		Messages:  []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		MaxTokens: &maxTokens,
+		Thinking:  &models.ThinkingConfig{Type: "enabled", BudgetTokens: 8192},
+	})
+	if err == nil || !strings.Contains(err.Error(), "must be greater than thinking.budget_tokens") {
+		t.Errorf("expected budget validation error, got %v", err)
+	}
+}
+
+func TestConvertToBedrockRequest_ThinkingConstraints(t *testing.T) {
+	temperature, topP := 0.2, 0.5
+	req, err := convertToBedrockRequest(&models.ChatCompletionRequest{
+		Model:           "anthropic.claude-3-7-sonnet-20250219-v1:0",
+		Messages:        []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		Temperature:     &temperature,
+		TopP:            &topP,
+		ReasoningEffort: "low",
+	})
+	if err != nil {
+		t.Fatalf("convertToBedrockRequest() error = %v", err)
+	}
+	if req.InferenceConfig.Temperature != nil || req.InferenceConfig.TopP != nil {
+		t.Errorf("expected sampling params dropped with thinking, got %+v", req.InferenceConfig)
+	}
+
+	_, err = convertToBedrockRequest(&models.ChatCompletionRequest{
+		Model:           "anthropic.claude-3-7-sonnet-20250219-v1:0",
+		Messages:        []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		Tools:           []models.Tool{{Type: "function", Function: models.FunctionDefinition{Name: "get_weather"}}},
+		ToolChoice:      "required",
+		ReasoningEffort: "low",
+	})
+	if err == nil || !strings.Contains(err.Error(), "not supported when thinking is enabled") {
+		t.Errorf("expected forced tool_choice to be rejected, got %v", err)
+	}
+}
+
+func TestConvertToBedrockRequest_ToolChoiceNone(t *testing.T) {
+	tools := []models.Tool{{Type: "function", Function: models.FunctionDefinition{Name: "get_weather"}}}
+
+	// 没有工具调用历史时不发送 toolConfig
+	req, err := convertToBedrockRequest(&models.ChatCompletionRequest{
+		Model:      "anthropic.claude-3-5-sonnet-20241022-v2:0",
+		Messages:   []models.ChatMessage{{Role: "user", Content: "Weather in Paris?"}},
+		Tools:      tools,
+		ToolChoice: "none",
+	})
+	if err != nil {
+		t.Fatalf("convertToBedrockRequest() error = %v", err)
+	}
+	if req.ToolConfig != nil {
+		t.Errorf("expected toolConfig to be omitted, got %+v", req.ToolConfig)
+	}
+
+	// 有工具调用历史时 Converse 必须携带 toolConfig，无法禁止调用工具
+	_, err = convertToBedrockRequest(&models.ChatCompletionRequest{
+		Model: "anthropic.claude-3-5-sonnet-20241022-v2:0",
+		Messages: []models.ChatMessage{
+			{Role: "user", Content: "Weather in Paris?"},
+			{Role: "assistant", ToolCalls: []models.ToolCall{{ID: "tooluse_1", Type: "function", Function: models.FunctionCall{Name: "get_weather", Arguments: `{}`}}}},
+			{Role: "tool", ToolCallID: "tooluse_1", Content: "20C"},
+		},
+		Tools:      tools,
+		ToolChoice: "none",
+	})
+	if err == nil || !strings.Contains(err.Error(), `tool_choice "none" is not supported`) {
+		t.Errorf("expected tool_choice none error, got %v", err)
+	}
+}
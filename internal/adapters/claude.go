@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gotoailab/llmhub/internal/models"
 )
@@ -33,6 +36,8 @@ func NewClaudeAdapter(apiKey, baseURL string) (Adapter, error) {
 	}, nil
 }
 
+func (a *ClaudeAdapter) supportsDocuments() {}
+
 func (a *ClaudeAdapter) GetProvider() Provider {
 	return Provider("claude")
 }
@@ -123,20 +128,21 @@ func (a *ClaudeAdapter) ChatCompletionStream(ctx context.Context, req *models.Ch
 		return nil, fmt.Errorf("claude stream error: status %d", resp.StatusCode)
 	}
 
-	return resp.Body, nil
+	// 将 Anthropic 原生事件转换为 OpenAI 格式的 SSE
+	return newClaudeStream(resp.Body, req), nil
 }
 
 // 检查模型是否支持工具调用
 func (a *ClaudeAdapter) supportsTools(modelName string) bool {
 	supportedModels := map[string]bool{
-		"claude-3-5-sonnet":           true,
-		"claude-3-5-sonnet-20241022":  true,
-		"claude-3-opus":               true,
-		"claude-3-opus-20240229":      true,
-		"claude-3-sonnet":             true,
-		"claude-3-sonnet-20240229":    true,
-		"claude-3-haiku":              false, // Haiku 不支持工具调用
-		"claude-3-haiku-20240307":     false,
+		"claude-3-5-sonnet":          true,
+		"claude-3-5-sonnet-20241022": true,
+		"claude-3-opus":              true,
+		"claude-3-opus-20240229":     true,
+		"claude-3-sonnet":            true,
+		"claude-3-sonnet-20240229":   true,
+		"claude-3-haiku":             false, // Haiku 不支持工具调用
+		"claude-3-haiku-20240307":    false,
 	}
 
 	supported, exists := supportedModels[modelName]
@@ -149,20 +155,33 @@ func (a *ClaudeAdapter) supportsTools(modelName string) bool {
 
 // Claude 请求结构
 type ClaudeRequest struct {
-	Model       string          `json:"model"`
-	MaxTokens   int             `json:"max_tokens"`
-	Messages    []ClaudeMessage `json:"messages"`
-	Temperature *float64        `json:"temperature,omitempty"`
-	TopP        *float64        `json:"top_p,omitempty"`
-	Stream      bool            `json:"stream,omitempty"`
-	Tools       []ClaudeTool    `json:"tools,omitempty"`
-	ToolChoice  interface{}     `json:"tool_choice,omitempty"`
-	System      string          `json:"system,omitempty"`
+	Model         string          `json:"model"`
+	MaxTokens     int             `json:"max_tokens"`
+	Messages      []ClaudeMessage `json:"messages"`
+	Temperature   *float64        `json:"temperature,omitempty"`
+	TopP          *float64        `json:"top_p,omitempty"`
+	StopSequences []string        `json:"stop_sequences,omitempty"`
+	Stream        bool            `json:"stream,omitempty"`
+	Tools         []ClaudeTool    `json:"tools,omitempty"`
+	ToolChoice    interface{}     `json:"tool_choice,omitempty"`
+	System        string          `json:"system,omitempty"`
+	Metadata      *ClaudeMetadata `json:"metadata,omitempty"`
+	Thinking      *ClaudeThinking `json:"thinking,omitempty"`
+}
+
+// ClaudeThinking 扩展思考配置
+type ClaudeThinking struct {
+	Type         string `json:"type"`
+	BudgetTokens int    `json:"budget_tokens,omitempty"`
 }
 
 type ClaudeMessage struct {
-	Role    string        `json:"role"`
-	Content interface{}   `json:"content"`
+	Role    string      `json:"role"`
+	Content interface{} `json:"content"`
+}
+
+type ClaudeMetadata struct {
+	UserID string `json:"user_id,omitempty"`
 }
 
 type ClaudeTool struct {
@@ -171,6 +190,46 @@ type ClaudeTool struct {
 	InputSchema interface{} `json:"input_schema,omitempty"`
 }
 
+type ClaudeTextBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// ClaudeImageBlock 图片内容块
+type ClaudeImageBlock struct {
+	Type   string       `json:"type"`
+	Source ClaudeSource `json:"source"`
+}
+
+// ClaudeDocumentBlock 文档内容块，Citations 开启后回答中的文本块会附带引用位置
+type ClaudeDocumentBlock struct {
+	Type      string                 `json:"type"`
+	Source    ClaudeSource           `json:"source"`
+	Title     string                 `json:"title,omitempty"`
+	Citations *ClaudeCitationsConfig `json:"citations,omitempty"`
+}
+
+type ClaudeCitationsConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// ClaudeSource 图片或文档的来源
+// Type 为 "base64" 时使用 MediaType 和 Data，为 "text" 时 Data 为纯文本，为 "url" 时使用 URL
+type ClaudeSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+// ClaudeThinkingBlock 思考块，多轮工具调用时需连同签名原样传回
+type ClaudeThinkingBlock struct {
+	Type      string `json:"type"`
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Data      string `json:"data,omitempty"`
+}
+
 type ClaudeToolUse struct {
 	Type  string      `json:"type"`
 	ID    string      `json:"id"`
@@ -179,10 +238,10 @@ type ClaudeToolUse struct {
 }
 
 type ClaudeToolResult struct {
-	Type       string      `json:"type"`
-	ToolUseID  string      `json:"tool_use_id"`
-	Content    interface{} `json:"content"`
-	IsError    bool        `json:"is_error,omitempty"`
+	Type      string      `json:"type"`
+	ToolUseID string      `json:"tool_use_id"`
+	Content   interface{} `json:"content"`
+	IsError   bool        `json:"is_error,omitempty"`
 }
 
 // Claude 响应结构
@@ -198,11 +257,59 @@ type ClaudeResponse struct {
 }
 
 type ClaudeContent struct {
-	Type  string      `json:"type"`
-	Text  string      `json:"text,omitempty"`
-	ID    string      `json:"id,omitempty"`
-	Name  string      `json:"name,omitempty"`
-	Input interface{} `json:"input,omitempty"`
+	Type      string      `json:"type"`
+	Text      string      `json:"text,omitempty"`
+	ID        string      `json:"id,omitempty"`
+	Name      string      `json:"name,omitempty"`
+	Input     interface{} `json:"input,omitempty"`
+	Thinking  string      `json:"thinking,omitempty"`
+	Signature string      `json:"signature,omitempty"`
+	Data      string      `json:"data,omitempty"` // redacted_thinking 的加密内容
+
+	Citations []ClaudeCitation `json:"citations,omitempty"`
+}
+
+// ClaudeCitation 文本块引用的文档位置
+// Type 为 char_location、page_location 或 content_block_location，分别使用对应的起止字段
+type ClaudeCitation struct {
+	Type            string `json:"type"`
+	CitedText       string `json:"cited_text"`
+	DocumentIndex   int    `json:"document_index"`
+	DocumentTitle   string `json:"document_title,omitempty"`
+	StartCharIndex  *int   `json:"start_char_index,omitempty"`
+	EndCharIndex    *int   `json:"end_char_index,omitempty"`
+	StartPageNumber *int   `json:"start_page_number,omitempty"`
+	EndPageNumber   *int   `json:"end_page_number,omitempty"`
+	StartBlockIndex *int   `json:"start_block_index,omitempty"`
+	EndBlockIndex   *int   `json:"end_block_index,omitempty"`
+}
+
+// toCitation 转换为通用引用，start/end 为所在文本块在回答中的字符位置
+func (c ClaudeCitation) toCitation(start, end int) models.Citation {
+	data := map[string]interface{}{"type": c.Type}
+	for key, value := range map[string]*int{
+		"start_char_index":  c.StartCharIndex,
+		"end_char_index":    c.EndCharIndex,
+		"start_page_number": c.StartPageNumber,
+		"end_page_number":   c.EndPageNumber,
+		"start_block_index": c.StartBlockIndex,
+		"end_block_index":   c.EndBlockIndex,
+	} {
+		if value != nil {
+			data[key] = *value
+		}
+	}
+	return models.Citation{
+		Start: start,
+		End:   end,
+		Text:  c.CitedText,
+		Sources: []models.CitationSource{{
+			Type:  "document",
+			ID:    strconv.Itoa(c.DocumentIndex),
+			Title: c.DocumentTitle,
+			Data:  data,
+		}},
+	}
 }
 
 type ClaudeUsage struct {
@@ -212,109 +319,241 @@ type ClaudeUsage struct {
 
 func (a *ClaudeAdapter) convertToClaudeRequest(req *models.ChatCompletionRequest) (*ClaudeRequest, error) {
 	messages := make([]ClaudeMessage, 0, len(req.Messages))
-	var systemMessage string
+	var systemParts []string
+
+	// 旧的 function_call 消息没有 ID，按函数名关联调用与结果
+	functionCallIDs := make(map[string]string)
 
 	for _, msg := range req.Messages {
-		// 处理 system 消息
-		if msg.Role == "system" {
-			if content, ok := msg.Content.(string); ok {
-				systemMessage = content
+		switch msg.Role {
+		case "system", "developer":
+			// 多条 system 消息按顺序合并
+			if text := contentToText(msg.Content); text != "" {
+				systemParts = append(systemParts, text)
 			}
-			continue
-		}
 
-		claudeMsg := ClaudeMessage{
-			Role: msg.Role,
-		}
-
-		// 处理工具调用消息
-		if len(msg.ToolCalls) > 0 {
-			content := make([]interface{}, 0)
-			
-			// 添加文本内容（如果有）
-			if textContent, ok := msg.Content.(string); ok && textContent != "" {
-				content = append(content, map[string]string{
-					"type": "text",
-					"text": textContent,
-				})
+		case "assistant":
+			// 思考块必须位于 assistant 消息的最前面
+			var blocks []interface{}
+			for _, tb := range msg.ThinkingBlocks {
+				blocks = append(blocks, ClaudeThinkingBlock(tb))
+			}
+			if text := contentToText(msg.Content); text != "" {
+				blocks = append(blocks, ClaudeTextBlock{Type: "text", Text: text})
 			}
-
-			// 添加工具调用
 			for _, tc := range msg.ToolCalls {
-				var input interface{}
-				if tc.Function.Arguments != "" {
-					json.Unmarshal([]byte(tc.Function.Arguments), &input)
-				}
-				
-				content = append(content, ClaudeToolUse{
+				blocks = append(blocks, ClaudeToolUse{
 					Type:  "tool_use",
 					ID:    tc.ID,
 					Name:  tc.Function.Name,
-					Input: input,
+					Input: parseToolArguments(tc.Function.Arguments),
 				})
 			}
-			claudeMsg.Content = content
-		} else if msg.ToolCallID != "" {
-			// 处理工具结果消息
-			claudeMsg.Content = []ClaudeToolResult{
-				{
-					Type:      "tool_result",
-					ToolUseID: msg.ToolCallID,
-					Content:   msg.Content,
-				},
+			if msg.FunctionCall != nil {
+				id := newToolCallID()
+				functionCallIDs[msg.FunctionCall.Name] = id
+				blocks = append(blocks, ClaudeToolUse{
+					Type:  "tool_use",
+					ID:    id,
+					Name:  msg.FunctionCall.Name,
+					Input: parseToolArguments(msg.FunctionCall.Arguments),
+				})
 			}
-		} else {
-			// 普通文本消息
-			claudeMsg.Content = msg.Content
-		}
+			messages = appendClaudeBlocks(messages, "assistant", blocks)
 
-		messages = append(messages, claudeMsg)
+		case "tool", "function":
+			// 工具结果以 user 角色发送，连续的多个结果合并到同一轮
+			toolUseID := msg.ToolCallID
+			if toolUseID == "" {
+				toolUseID = functionCallIDs[msg.Name]
+			}
+			messages = appendClaudeBlocks(messages, "user", []interface{}{ClaudeToolResult{
+				Type:      "tool_result",
+				ToolUseID: toolUseID,
+				Content:   contentToText(msg.Content),
+			}})
+
+		default:
+			blocks, err := toClaudeContentBlocks(msg.Content)
+			if err != nil {
+				return nil, err
+			}
+			if len(blocks) > 0 {
+				messages = appendClaudeBlocks(messages, "user", blocks)
+			}
+		}
 	}
 
 	claudeReq := &ClaudeRequest{
-		Model:       a.mapModelName(req.Model),
-		Messages:    messages,
-		MaxTokens:   getIntValue(req.MaxTokens),
-		Temperature: req.Temperature,
-		TopP:        req.TopP,
-		System:      systemMessage,
+		Model:         a.mapModelName(req.Model),
+		Messages:      messages,
+		MaxTokens:     getIntValue(maxOutputTokens(req)),
+		Temperature:   req.Temperature,
+		TopP:          req.TopP,
+		StopSequences: req.Stop,
+		System:        strings.Join(systemParts, "\n\n"),
+	}
+
+	// 未显式指定 thinking 时，按 reasoning_effort 换算思考预算
+	thinking, err := claudeThinking(req)
+	if err != nil {
+		return nil, err
+	}
+	if thinking != nil {
+		claudeReq.Thinking = &ClaudeThinking{
+			Type:         "enabled",
+			BudgetTokens: thinking.BudgetTokens,
+		}
+		// max_tokens 需大于思考预算，未指定时在预算之外再留出回复空间
+		if claudeReq.MaxTokens == 0 {
+			claudeReq.MaxTokens = thinking.BudgetTokens + 4096
+		}
+		// 开启思考时不能调整 temperature 和 top_p，忽略这两个采样参数
+		claudeReq.Temperature = nil
+		claudeReq.TopP = nil
 	}
 
 	if claudeReq.MaxTokens == 0 {
 		claudeReq.MaxTokens = 4096 // 默认值
 	}
 
-	// 转换工具定义
-	if len(req.Tools) > 0 {
-		tools := make([]ClaudeTool, 0, len(req.Tools))
-		for _, tool := range req.Tools {
-			tools = append(tools, ClaudeTool{
-				Name:        tool.Function.Name,
-				Description: tool.Function.Description,
-				InputSchema: tool.Function.Parameters,
-			})
+	if req.User != "" {
+		claudeReq.Metadata = &ClaudeMetadata{UserID: req.User}
+	}
+
+	// 转换工具定义，兼容旧的 Functions 格式
+	for _, tool := range req.Tools {
+		claudeReq.Tools = append(claudeReq.Tools, ClaudeTool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			InputSchema: tool.Function.Parameters,
+		})
+	}
+	for _, fn := range req.Functions {
+		claudeReq.Tools = append(claudeReq.Tools, ClaudeTool{
+			Name:        fn.Name,
+			Description: fn.Description,
+			InputSchema: fn.Parameters,
+		})
+	}
+
+	toolChoice := req.ToolChoice
+	if toolChoice == nil {
+		toolChoice = req.FunctionCall
+	}
+	if toolChoice != nil && len(claudeReq.Tools) > 0 {
+		choice, err := toClaudeToolChoice(toolChoice)
+		if err != nil {
+			return nil, err
+		}
+		// 开启思考时只支持 auto 和 none，不能强制调用工具
+		if claudeReq.Thinking != nil && (choice["type"] == "any" || choice["type"] == "tool") {
+			return nil, fmt.Errorf("tool_choice %v is not supported when thinking is enabled", toolChoice)
 		}
-		claudeReq.Tools = tools
+		claudeReq.ToolChoice = choice
+	}
+
+	return claudeReq, nil
+}
 
-		if req.ToolChoice != nil {
-			claudeReq.ToolChoice = req.ToolChoice
+// toClaudeContentBlocks 将 user 消息内容转换为 Claude 内容块，图片和文档分别转换为 image、document 块
+func toClaudeContentBlocks(content interface{}) ([]interface{}, error) {
+	parts, ok := contentParts(content)
+	if !ok {
+		return []interface{}{ClaudeTextBlock{Type: "text", Text: contentToText(content)}}, nil
+	}
+	blocks := make([]interface{}, 0, len(parts))
+	for _, part := range parts {
+		switch part.Type {
+		case "text":
+			if part.Text != "" {
+				blocks = append(blocks, ClaudeTextBlock{Type: "text", Text: part.Text})
+			}
+		case "image_url":
+			if part.ImageURL == nil {
+				return nil, fmt.Errorf("image_url content part requires a url")
+			}
+			source := ClaudeSource{Type: "url", URL: part.ImageURL.URL}
+			if mimeType, data, ok := parseDataURI(part.ImageURL.URL); ok {
+				source = ClaudeSource{Type: "base64", MediaType: mimeType, Data: data}
+			}
+			blocks = append(blocks, ClaudeImageBlock{Type: "image", Source: source})
+		case "document":
+			if part.Document == nil {
+				return nil, fmt.Errorf("document content part requires a document")
+			}
+			block, err := toClaudeDocumentBlock(part.Document)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, block)
+		default:
+			return nil, fmt.Errorf("unsupported content part type %q", part.Type)
 		}
 	}
+	return blocks, nil
+}
 
-	// 兼容旧的 Functions 格式
-	if len(req.Functions) > 0 {
-		tools := make([]ClaudeTool, 0, len(req.Functions))
-		for _, fn := range req.Functions {
-			tools = append(tools, ClaudeTool{
-				Name:        fn.Name,
-				Description: fn.Description,
-				InputSchema: fn.Parameters,
-			})
+// toClaudeDocumentBlock 将文档转换为 document 块，PDF 以 base64 发送，纯文本以 text 发送
+func toClaudeDocumentBlock(doc *models.Document) (ClaudeDocumentBlock, error) {
+	block := ClaudeDocumentBlock{Type: "document", Title: doc.Title}
+	switch doc.MediaType {
+	case "application/pdf":
+		block.Source = ClaudeSource{Type: "base64", MediaType: doc.MediaType, Data: doc.Data}
+	case "text/plain":
+		block.Source = ClaudeSource{Type: "text", MediaType: doc.MediaType, Data: doc.Data}
+	default:
+		return block, fmt.Errorf("unsupported document media type %q", doc.MediaType)
+	}
+	if doc.Citations {
+		block.Citations = &ClaudeCitationsConfig{Enabled: true}
+	}
+	return block, nil
+}
+
+// appendClaudeBlocks 追加一轮消息，与上一轮角色相同时合并内容块（Anthropic 要求 user/assistant 交替）
+func appendClaudeBlocks(messages []ClaudeMessage, role string, blocks []interface{}) []ClaudeMessage {
+	if len(blocks) == 0 {
+		return messages
+	}
+	if n := len(messages); n > 0 && messages[n-1].Role == role {
+		if prev, ok := messages[n-1].Content.([]interface{}); ok {
+			messages[n-1].Content = append(prev, blocks...)
+			return messages
 		}
-		claudeReq.Tools = tools
 	}
+	return append(messages, ClaudeMessage{Role: role, Content: blocks})
+}
 
-	return claudeReq, nil
+// toClaudeToolChoice 将 OpenAI 的 tool_choice 转换为 Anthropic 的 {type: auto|any|none|tool}
+func toClaudeToolChoice(toolChoice interface{}) (map[string]interface{}, error) {
+	switch v := toolChoice.(type) {
+	case string:
+		switch v {
+		case "auto":
+			return map[string]interface{}{"type": "auto"}, nil
+		case "required", "any":
+			return map[string]interface{}{"type": "any"}, nil
+		case "none":
+			return map[string]interface{}{"type": "none"}, nil
+		}
+	case map[string]interface{}:
+		// {"type": "function", "function": {"name": ...}} 或旧的 {"name": ...}
+		if fn, ok := v["function"].(map[string]interface{}); ok {
+			if name, ok := fn["name"].(string); ok && name != "" {
+				return map[string]interface{}{"type": "tool", "name": name}, nil
+			}
+		}
+		if name, ok := v["name"].(string); ok && name != "" {
+			return map[string]interface{}{"type": "tool", "name": name}, nil
+		}
+		// 已是 Anthropic 格式
+		switch v["type"] {
+		case "auto", "any", "none":
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported tool_choice %v", toolChoice)
 }
 
 func (a *ClaudeAdapter) convertFromClaudeResponse(claudeResp *ClaudeResponse, modelName string) *models.ChatCompletionResponse {
@@ -322,17 +561,35 @@ func (a *ClaudeAdapter) convertFromClaudeResponse(claudeResp *ClaudeResponse, mo
 		Role: "assistant",
 	}
 
-	var textContent string
+	var textContent, reasoningContent strings.Builder
 	var toolCalls []models.ToolCall
+	textLen := 0 // 已输出文本的字符数，用于计算引用位置
 
 	// 处理响应内容
 	for _, content := range claudeResp.Content {
 		switch content.Type {
 		case "text":
-			textContent = content.Text
+			start := textLen
+			textContent.WriteString(content.Text)
+			textLen += utf8.RuneCountInString(content.Text)
+			for _, citation := range content.Citations {
+				message.Citations = append(message.Citations, citation.toCitation(start, textLen))
+			}
+		case "thinking":
+			reasoningContent.WriteString(content.Thinking)
+			message.ThinkingBlocks = append(message.ThinkingBlocks, models.ThinkingBlock{
+				Type:      "thinking",
+				Thinking:  content.Thinking,
+				Signature: content.Signature,
+			})
+		case "redacted_thinking":
+			message.ThinkingBlocks = append(message.ThinkingBlocks, models.ThinkingBlock{
+				Type: "redacted_thinking",
+				Data: content.Data,
+			})
 		case "tool_use":
 			// 将输入参数序列化为 JSON 字符串
-			args := ""
+			args := "{}"
 			if content.Input != nil {
 				if argsBytes, err := json.Marshal(content.Input); err == nil {
 					args = string(argsBytes)
@@ -350,7 +607,8 @@ func (a *ClaudeAdapter) convertFromClaudeResponse(claudeResp *ClaudeResponse, mo
 		}
 	}
 
-	message.Content = textContent
+	message.Content = textContent.String()
+	message.ReasoningContent = reasoningContent.String()
 	if len(toolCalls) > 0 {
 		message.ToolCalls = toolCalls
 	}
@@ -364,7 +622,7 @@ func (a *ClaudeAdapter) convertFromClaudeResponse(claudeResp *ClaudeResponse, mo
 			{
 				Index:        0,
 				Message:      message,
-				FinishReason: claudeResp.StopReason,
+				FinishReason: mapClaudeStopReason(claudeResp.StopReason),
 			},
 		},
 		Usage: models.Usage{
@@ -0,0 +1,241 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// Claude 流式事件结构
+type ClaudeStreamEvent struct {
+	Type         string             `json:"type"`
+	Index        int                `json:"index"`
+	Message      *ClaudeResponse    `json:"message,omitempty"`
+	ContentBlock *ClaudeContent     `json:"content_block,omitempty"`
+	Delta        *ClaudeStreamDelta `json:"delta,omitempty"`
+	Usage        *ClaudeUsage       `json:"usage,omitempty"`
+	Error        *ClaudeError       `json:"error,omitempty"`
+}
+
+type ClaudeStreamDelta struct {
+	Type         string `json:"type,omitempty"`
+	Text         string `json:"text,omitempty"`
+	PartialJSON  string `json:"partial_json,omitempty"`
+	Thinking     string `json:"thinking,omitempty"`
+	Signature    string `json:"signature,omitempty"`
+	StopReason   string `json:"stop_reason,omitempty"`
+	StopSequence string `json:"stop_sequence,omitempty"`
+
+	Citation *ClaudeCitation `json:"citation,omitempty"` // citations_delta
+}
+
+type ClaudeError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// claudeStreamTranslator 将 Anthropic 原生事件流转换为 OpenAI chat.completion.chunk
+type claudeStreamTranslator struct {
+	reader  *SSEReader
+	id      string
+	model   string
+	created int64
+
+	// content block 下标到 OpenAI tool_calls 下标的映射
+	toolIndexes map[int]int
+	// 正在接收的思考块，块结束时连同签名一次性输出
+	thinking map[int]*models.ThinkingBlock
+	// 已输出文本的字符数、各文本块的起始位置及其引用，引用在块结束时输出
+	textLen    int
+	textStarts map[int]int
+	citations  map[int][]ClaudeCitation
+	usage      models.Usage
+}
+
+func newClaudeStream(body io.ReadCloser, req *models.ChatCompletionRequest) io.ReadCloser {
+	t := &claudeStreamTranslator{
+		reader:      NewSSEReader(body),
+		model:       req.Model,
+		created:     time.Now().Unix(),
+		toolIndexes: make(map[int]int),
+		thinking:    make(map[int]*models.ThinkingBlock),
+		textStarts:  make(map[int]int),
+		citations:   make(map[int][]ClaudeCitation),
+	}
+	return newChunkStream(req, t.next, body)
+}
+
+func (t *claudeStreamTranslator) next() ([]*models.ChatCompletionStreamResponse, error) {
+	sse, err := t.reader.Next()
+	if err != nil {
+		return nil, err
+	}
+	if len(sse.Data) == 0 {
+		return nil, nil
+	}
+
+	var event ClaudeStreamEvent
+	if err := json.Unmarshal(sse.Data, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode claude stream event: %w", err)
+	}
+
+	switch event.Type {
+	case "message_start":
+		if event.Message != nil {
+			t.id = event.Message.ID
+			t.usage.PromptTokens = event.Message.Usage.InputTokens
+			t.usage.CompletionTokens = event.Message.Usage.OutputTokens
+		}
+		return t.chunk(models.ChatMessageDelta{Role: "assistant"}, ""), nil
+
+	case "content_block_start":
+		if event.ContentBlock == nil {
+			return nil, nil
+		}
+		switch event.ContentBlock.Type {
+		case "text":
+			t.textStarts[event.Index] = t.textLen
+			return nil, nil
+		case "thinking":
+			t.thinking[event.Index] = &models.ThinkingBlock{Type: "thinking"}
+			return nil, nil
+		case "redacted_thinking":
+			return t.chunk(models.ChatMessageDelta{
+				ThinkingBlocks: []models.ThinkingBlock{{Type: "redacted_thinking", Data: event.ContentBlock.Data}},
+			}, ""), nil
+		case "tool_use":
+		default:
+			return nil, nil
+		}
+		index := len(t.toolIndexes)
+		t.toolIndexes[event.Index] = index
+		return t.chunk(models.ChatMessageDelta{
+			ToolCalls: []models.ToolCall{{
+				Index: &index,
+				ID:    event.ContentBlock.ID,
+				Type:  "function",
+				Function: models.FunctionCall{
+					Name: event.ContentBlock.Name,
+				},
+			}},
+		}, ""), nil
+
+	case "content_block_delta":
+		if event.Delta == nil {
+			return nil, nil
+		}
+		switch event.Delta.Type {
+		case "text_delta":
+			t.textLen += utf8.RuneCountInString(event.Delta.Text)
+			return t.chunk(models.ChatMessageDelta{Content: event.Delta.Text}, ""), nil
+		case "citations_delta":
+			if event.Delta.Citation != nil {
+				t.citations[event.Index] = append(t.citations[event.Index], *event.Delta.Citation)
+			}
+			return nil, nil
+		case "thinking_delta":
+			if block, ok := t.thinking[event.Index]; ok {
+				block.Thinking += event.Delta.Thinking
+			}
+			return t.chunk(models.ChatMessageDelta{ReasoningContent: event.Delta.Thinking}, ""), nil
+		case "signature_delta":
+			if block, ok := t.thinking[event.Index]; ok {
+				block.Signature += event.Delta.Signature
+			}
+			return nil, nil
+		case "input_json_delta":
+			index, ok := t.toolIndexes[event.Index]
+			if !ok || event.Delta.PartialJSON == "" {
+				return nil, nil
+			}
+			return t.chunk(models.ChatMessageDelta{
+				ToolCalls: []models.ToolCall{{
+					Index: &index,
+					Function: models.FunctionCall{
+						Arguments: event.Delta.PartialJSON,
+					},
+				}},
+			}, ""), nil
+		}
+		return nil, nil
+
+	case "content_block_stop":
+		if citations, ok := t.citations[event.Index]; ok {
+			delete(t.citations, event.Index)
+			start := t.textStarts[event.Index]
+			delta := models.ChatMessageDelta{}
+			for _, citation := range citations {
+				delta.Citations = append(delta.Citations, citation.toCitation(start, t.textLen))
+			}
+			return t.chunk(delta, ""), nil
+		}
+		block, ok := t.thinking[event.Index]
+		if !ok {
+			return nil, nil
+		}
+		delete(t.thinking, event.Index)
+		return t.chunk(models.ChatMessageDelta{ThinkingBlocks: []models.ThinkingBlock{*block}}, ""), nil
+
+	case "message_delta":
+		if event.Usage != nil {
+			t.usage.CompletionTokens = event.Usage.OutputTokens
+		}
+		t.usage.TotalTokens = t.usage.PromptTokens + t.usage.CompletionTokens
+
+		// 用量附在结束块上，由 chunkStream 按 stream_options 决定是否单独发送
+		finishReason := "stop"
+		if event.Delta != nil && event.Delta.StopReason != "" {
+			finishReason = mapClaudeStopReason(event.Delta.StopReason)
+		}
+		chunks := t.chunk(models.ChatMessageDelta{}, finishReason)
+		usage := t.usage
+		chunks[0].Usage = &usage
+		return chunks, nil
+
+	case "message_stop":
+		return nil, io.EOF
+
+	case "error":
+		if event.Error != nil {
+			return nil, fmt.Errorf("claude stream error: %s: %s", event.Error.Type, event.Error.Message)
+		}
+		return nil, fmt.Errorf("claude stream error: %s", string(sse.Data))
+	}
+
+	// ping 等事件无需转发
+	return nil, nil
+}
+
+func (t *claudeStreamTranslator) chunk(delta models.ChatMessageDelta, finishReason string) []*models.ChatCompletionStreamResponse {
+	return []*models.ChatCompletionStreamResponse{{
+		ID:      t.id,
+		Object:  "chat.completion.chunk",
+		Created: t.created,
+		Model:   t.model,
+		Choices: []models.ChatCompletionStreamChoice{{
+			Index:        0,
+			Delta:        delta,
+			FinishReason: finishReason,
+		}},
+	}}
+}
+
+// mapClaudeStopReason 将 Claude 的 stop_reason 映射为 OpenAI 的 finish_reason
+func mapClaudeStopReason(reason string) string {
+	switch reason {
+	case "end_turn", "stop_sequence":
+		return "stop"
+	case "max_tokens":
+		return "length"
+	case "tool_use":
+		return "tool_calls"
+	case "refusal":
+		return "content_filter"
+	default:
+		return reason
+	}
+}
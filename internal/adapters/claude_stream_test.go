@@ -0,0 +1,234 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestClaudeAdapter_ChatCompletionStream(t *testing.T) {
+	events := []struct{ name, data string }{
+		{"message_start", `{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","content":[],"model":"claude-3-5-sonnet-20241022","usage":{"input_tokens":25,"output_tokens":1}}}`},
+		{"content_block_start", `{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`},
+		{"ping", `{"type":"ping"}`},
+		{"content_block_delta", `{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Let me check"}}`},
+		{"content_block_stop", `{"type":"content_block_stop","index":0}`},
+		{"content_block_start", `{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{}}}`},
+		{"content_block_delta", `{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}`},
+		{"content_block_delta", `{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"Paris\"}"}}`},
+		{"content_block_stop", `{"type":"content_block_stop","index":1}`},
+		{"message_delta", `{"type":"message_delta","delta":{"stop_reason":"tool_use","stop_sequence":null},"usage":{"output_tokens":42}}`},
+		{"message_stop", `{"type":"message_stop"}`},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, e := range events {
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.name, e.data)
+		}
+	}))
+	defer server.Close()
+
+	adapter, _ := NewClaudeAdapter("test-key", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:         "claude-3-5-sonnet",
+		Messages:      []models.ChatMessage{{Role: "user", Content: "Weather in Paris?"}},
+		StreamOptions: &models.StreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, done := collectStreamChunks(t, stream)
+	if !done {
+		t.Error("expected stream to end with data: [DONE]")
+	}
+	if len(chunks) != 7 {
+		t.Fatalf("expected 7 chunks, got %d", len(chunks))
+	}
+
+	for _, chunk := range chunks {
+		if chunk.Object != "chat.completion.chunk" || chunk.ID != "msg_1" {
+			t.Errorf("unexpected chunk envelope: %+v", chunk)
+		}
+	}
+	if chunks[0].Choices[0].Delta.Role != "assistant" {
+		t.Errorf("expected role 'assistant', got %q", chunks[0].Choices[0].Delta.Role)
+	}
+	if chunks[1].Choices[0].Delta.Content != "Let me check" {
+		t.Errorf("expected text delta, got %q", chunks[1].Choices[0].Delta.Content)
+	}
+
+	start := chunks[2].Choices[0].Delta.ToolCalls
+	if len(start) != 1 || *start[0].Index != 0 || start[0].ID != "toolu_1" || start[0].Function.Name != "get_weather" {
+		t.Errorf("unexpected tool call start: %+v", start)
+	}
+
+	var args strings.Builder
+	for _, chunk := range chunks[3:5] {
+		tc := chunk.Choices[0].Delta.ToolCalls
+		if len(tc) != 1 || *tc[0].Index != 0 {
+			t.Fatalf("unexpected tool call delta: %+v", tc)
+		}
+		args.WriteString(tc[0].Function.Arguments)
+	}
+	if args.String() != `{"city":"Paris"}` {
+		t.Errorf("expected arguments to stitch into JSON, got %q", args.String())
+	}
+
+	if chunks[5].Choices[0].FinishReason != "tool_calls" {
+		t.Errorf("expected finish_reason 'tool_calls', got %q", chunks[5].Choices[0].FinishReason)
+	}
+
+	// 用量来自 message_start 的 input_tokens 和 message_delta 的 output_tokens
+	usage := chunks[6]
+	if len(usage.Choices) != 0 {
+		t.Errorf("expected usage chunk without choices, got %+v", usage.Choices)
+	}
+	if usage.Usage == nil || usage.Usage.PromptTokens != 25 || usage.Usage.CompletionTokens != 42 || usage.Usage.TotalTokens != 67 {
+		t.Errorf("unexpected usage: %+v", usage.Usage)
+	}
+}
+
+func TestClaudeAdapter_ChatCompletionStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_1\",\"usage\":{\"input_tokens\":1}}}\n\n")
+		fmt.Fprint(w, "event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n")
+	}))
+	defer server.Close()
+
+	adapter, _ := NewClaudeAdapter("test-key", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "claude-3-5-sonnet",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	reader := NewSSEReader(stream)
+	if _, err := reader.Next(); err != nil {
+		t.Fatalf("expected role chunk before the error, got %v", err)
+	}
+	if _, err := reader.Next(); err == nil || !strings.Contains(err.Error(), "Overloaded") {
+		t.Errorf("expected overloaded error, got %v", err)
+	}
+}
+
+func TestMapClaudeStopReason(t *testing.T) {
+	tests := map[string]string{
+		"end_turn":      "stop",
+		"stop_sequence": "stop",
+		"max_tokens":    "length",
+		"tool_use":      "tool_calls",
+	}
+	for reason, want := range tests {
+		if got := mapClaudeStopReason(reason); got != want {
+			t.Errorf("mapClaudeStopReason(%q) = %q, want %q", reason, got, want)
+		}
+	}
+}
+
+func TestClaudeAdapter_ChatCompletionStreamThinking(t *testing.T) {
+	events := []struct{ name, data string }{
+		{"message_start", `{"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","content":[],"usage":{"input_tokens":10,"output_tokens":1}}}`},
+		{"content_block_start", `{"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}`},
+		{"content_block_delta", `{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"Let me "}}`},
+		{"content_block_delta", `{"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"think."}}`},
+		{"content_block_delta", `{"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"sig_1"}}`},
+		{"content_block_stop", `{"type":"content_block_stop","index":0}`},
+		{"content_block_start", `{"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}`},
+		{"content_block_delta", `{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"42"}}`},
+		{"content_block_stop", `{"type":"content_block_stop","index":1}`},
+		{"message_delta", `{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":20}}`},
+		{"message_stop", `{"type":"message_stop"}`},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, e := range events {
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.name, e.data)
+		}
+	}))
+	defer server.Close()
+
+	adapter, _ := NewClaudeAdapter("test-key", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "claude-3-7-sonnet-20250219",
+		Messages: []models.ChatMessage{{Role: "user", Content: "What is the answer?"}},
+		Thinking: &models.ThinkingConfig{Type: "enabled", BudgetTokens: 1024},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, _ := collectStreamChunks(t, stream)
+	if len(chunks) != 6 {
+		t.Fatalf("expected 6 chunks, got %d", len(chunks))
+	}
+	if chunks[1].Choices[0].Delta.ReasoningContent != "Let me " || chunks[2].Choices[0].Delta.ReasoningContent != "think." {
+		t.Errorf("unexpected reasoning deltas")
+	}
+	blocks := chunks[3].Choices[0].Delta.ThinkingBlocks
+	if len(blocks) != 1 || blocks[0].Thinking != "Let me think." || blocks[0].Signature != "sig_1" {
+		t.Errorf("expected complete signed thinking block, got %+v", blocks)
+	}
+	if chunks[4].Choices[0].Delta.Content != "42" {
+		t.Errorf("unexpected content delta: %+v", chunks[4].Choices[0].Delta)
+	}
+}
+
+func TestClaudeAdapter_ChatCompletionStreamCitations(t *testing.T) {
+	events := []string{
+		`{"type":"message_start","message":{"id":"msg_4","type":"message","role":"assistant","content":[],"usage":{"input_tokens":10,"output_tokens":1}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Per the contract, "}}`,
+		`{"type":"content_block_stop","index":0}`,
+		`{"type":"content_block_start","index":1,"content_block":{"type":"text","text":"","citations":[]}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"citations_delta","citation":{"type":"char_location","cited_text":"ends 2026","document_index":0,"document_title":"Contract","start_char_index":10,"end_char_index":19}}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"it ends in 2026."}}`,
+		`{"type":"content_block_stop","index":1}`,
+		`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":9}}`,
+		`{"type":"message_stop"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, e := range events {
+			fmt.Fprintf(w, "data: %s\n\n", e)
+		}
+	}))
+	defer server.Close()
+
+	adapter, _ := NewClaudeAdapter("test-key", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "claude-3-5-sonnet",
+		Messages: []models.ChatMessage{{Role: "user", Content: "When does the contract end?"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, _ := collectStreamChunks(t, stream)
+	var citations []models.Citation
+	for _, chunk := range chunks {
+		for _, choice := range chunk.Choices {
+			citations = append(citations, choice.Delta.Citations...)
+		}
+	}
+	if len(citations) != 1 {
+		t.Fatalf("expected one citation, got %+v", citations)
+	}
+	if c := citations[0]; c.Start != 18 || c.End != 34 || c.Text != "ends 2026" || c.Sources[0].Data["start_char_index"] != float64(10) {
+		t.Errorf("unexpected citation: %+v", c)
+	}
+}
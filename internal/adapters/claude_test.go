@@ -0,0 +1,458 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestClaudeAdapter_ConvertRequest(t *testing.T) {
+	adapter := &ClaudeAdapter{}
+	claudeReq, err := adapter.convertToClaudeRequest(&models.ChatCompletionRequest{
+		Model: "claude-3-5-sonnet",
+		User:  "user-42",
+		Stop:  []string{"END"},
+		Messages: []models.ChatMessage{
+			{Role: "system", Content: "You are helpful."},
+			{Role: "system", Content: "Answer briefly."},
+			{Role: "user", Content: "Weather in Berlin and Paris?"},
+			{Role: "assistant", Content: "Checking.", ToolCalls: []models.ToolCall{
+				{ID: "toolu_1", Type: "function", Function: models.FunctionCall{Name: "get_weather", Arguments: `{"city":"Berlin"}`}},
+				{ID: "toolu_2", Type: "function", Function: models.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+			}},
+			{Role: "tool", ToolCallID: "toolu_1", Content: "20 degrees"},
+			{Role: "tool", ToolCallID: "toolu_2", Content: "25 degrees"},
+			{Role: "user", Content: "Which is warmer?"},
+		},
+		Tools: []models.Tool{{
+			Type:     "function",
+			Function: models.FunctionDefinition{Name: "get_weather"},
+		}},
+		ToolChoice: "required",
+	})
+	if err != nil {
+		t.Fatalf("convertToClaudeRequest() error = %v", err)
+	}
+
+	if claudeReq.System != "You are helpful.\n\nAnswer briefly." {
+		t.Errorf("expected merged system prompt, got %q", claudeReq.System)
+	}
+	if len(claudeReq.StopSequences) != 1 || claudeReq.StopSequences[0] != "END" {
+		t.Errorf("expected stop_sequences, got %v", claudeReq.StopSequences)
+	}
+	if claudeReq.Metadata == nil || claudeReq.Metadata.UserID != "user-42" {
+		t.Errorf("expected metadata.user_id, got %+v", claudeReq.Metadata)
+	}
+	if choice, ok := claudeReq.ToolChoice.(map[string]interface{}); !ok || choice["type"] != "any" {
+		t.Errorf("expected tool_choice any, got %v", claudeReq.ToolChoice)
+	}
+
+	// user、assistant、user（两个工具结果与后续问题合并）
+	if len(claudeReq.Messages) != 3 {
+		t.Fatalf("expected 3 alternating turns, got %d: %+v", len(claudeReq.Messages), claudeReq.Messages)
+	}
+	for i, role := range []string{"user", "assistant", "user"} {
+		if claudeReq.Messages[i].Role != role {
+			t.Errorf("message %d: expected role %s, got %s", i, role, claudeReq.Messages[i].Role)
+		}
+	}
+	if blocks := claudeReq.Messages[1].Content.([]interface{}); len(blocks) != 3 {
+		t.Errorf("expected text and two tool_use blocks, got %+v", blocks)
+	}
+	blocks := claudeReq.Messages[2].Content.([]interface{})
+	if len(blocks) != 3 {
+		t.Fatalf("expected two tool results and a text block, got %+v", blocks)
+	}
+	if result, ok := blocks[1].(ClaudeToolResult); !ok || result.ToolUseID != "toolu_2" || result.Content != "25 degrees" {
+		t.Errorf("unexpected tool result: %+v", blocks[1])
+	}
+	if text, ok := blocks[2].(ClaudeTextBlock); !ok || text.Text != "Which is warmer?" {
+		t.Errorf("unexpected text block: %+v", blocks[2])
+	}
+}
+
+func TestClaudeAdapter_ImageContent(t *testing.T) {
+	adapter := &ClaudeAdapter{}
+	claudeReq, err := adapter.convertToClaudeRequest(&models.ChatCompletionRequest{
+		Model: "claude-3-5-sonnet",
+		Messages: []models.ChatMessage{{Role: "user", Content: []models.ContentPart{
+			{Type: "image_url", ImageURL: &models.ImageURL{URL: "data:image/png;base64,iVBORw0K"}},
+			{Type: "image_url", ImageURL: &models.ImageURL{URL: "https://example.com/cat.jpg"}},
+			{Type: "text", Text: "Compare these."},
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("convertToClaudeRequest() error = %v", err)
+	}
+
+	blocks := claudeReq.Messages[0].Content.([]interface{})
+	if len(blocks) != 3 {
+		t.Fatalf("expected two image blocks and a text block, got %+v", blocks)
+	}
+	inline := blocks[0].(ClaudeImageBlock)
+	if inline.Type != "image" || inline.Source.Type != "base64" || inline.Source.MediaType != "image/png" || inline.Source.Data != "iVBORw0K" {
+		t.Errorf("unexpected base64 image block: %+v", inline)
+	}
+	if remote := blocks[1].(ClaudeImageBlock); remote.Source.Type != "url" || remote.Source.URL != "https://example.com/cat.jpg" {
+		t.Errorf("unexpected url image block: %+v", remote)
+	}
+
+	if _, err := adapter.convertToClaudeRequest(&models.ChatCompletionRequest{
+		Model:    "claude-3-5-sonnet",
+		Messages: []models.ChatMessage{{Role: "user", Content: []models.ContentPart{{Type: "input_audio"}}}},
+	}); err == nil {
+		t.Error("expected error for unsupported content part")
+	}
+}
+
+func TestClaudeAdapter_Document(t *testing.T) {
+	var gotReq map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		fmt.Fprint(w, `{
+			"id": "msg_3",
+			"type": "message",
+			"role": "assistant",
+			"content": [
+				{"type": "text", "text": "The contract "},
+				{"type": "text", "text": "ends in 2026.", "citations": [{
+					"type": "page_location",
+					"cited_text": "This agreement terminates on 31 December 2026.",
+					"document_index": 0,
+					"document_title": "Contract",
+					"start_page_number": 3,
+					"end_page_number": 4
+				}]}
+			],
+			"stop_reason": "end_turn",
+			"usage": {"input_tokens": 3000, "output_tokens": 12}
+		}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewClaudeAdapter("test-key", server.URL)
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "claude-3-5-sonnet",
+		Messages: []models.ChatMessage{{Role: "user", Content: []models.ContentPart{
+			{Type: "document", Document: &models.Document{MediaType: "application/pdf", Data: "JVBERi0x", Title: "Contract", Citations: true}},
+			{Type: "document", Document: &models.Document{MediaType: "text/plain", Data: "Appendix A"}},
+			{Type: "text", Text: "When does the contract end?"},
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	blocks := gotReq["messages"].([]interface{})[0].(map[string]interface{})["content"].([]interface{})
+	pdf := blocks[0].(map[string]interface{})
+	source := pdf["source"].(map[string]interface{})
+	if pdf["type"] != "document" || pdf["title"] != "Contract" || source["type"] != "base64" || source["media_type"] != "application/pdf" {
+		t.Errorf("unexpected pdf block: %v", pdf)
+	}
+	if citations, ok := pdf["citations"].(map[string]interface{}); !ok || citations["enabled"] != true {
+		t.Errorf("expected citations enabled, got %v", pdf["citations"])
+	}
+	text := blocks[1].(map[string]interface{})
+	if source := text["source"].(map[string]interface{}); source["type"] != "text" || source["data"] != "Appendix A" {
+		t.Errorf("unexpected text document block: %v", text)
+	}
+	if _, ok := text["citations"]; ok {
+		t.Errorf("expected no citations config, got %v", text["citations"])
+	}
+
+	msg := resp.Choices[0].Message
+	if msg.Content != "The contract ends in 2026." || len(msg.Citations) != 1 {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	citation := msg.Citations[0]
+	if citation.Start != 13 || citation.End != 26 || citation.Text != "This agreement terminates on 31 December 2026." {
+		t.Errorf("unexpected citation: %+v", citation)
+	}
+	if src := citation.Sources[0]; src.Type != "document" || src.ID != "0" || src.Title != "Contract" || src.Data["start_page_number"] != 3 {
+		t.Errorf("unexpected citation source: %+v", src)
+	}
+
+	if _, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "claude-3-5-sonnet",
+		Messages: []models.ChatMessage{{Role: "user", Content: []models.ContentPart{
+			{Type: "document", Document: &models.Document{MediaType: "application/msword", Data: "AAAA"}},
+		}}},
+	}); err == nil {
+		t.Error("expected error for unsupported document media type")
+	}
+}
+
+func TestClaudeToolChoice(t *testing.T) {
+	tests := []struct {
+		choice   interface{}
+		wantType string
+		wantName string
+	}{
+		{"auto", "auto", ""},
+		{"required", "any", ""},
+		{"none", "none", ""},
+		{map[string]interface{}{"type": "function", "function": map[string]interface{}{"name": "get_weather"}}, "tool", "get_weather"},
+		{map[string]interface{}{"name": "get_weather"}, "tool", "get_weather"},
+	}
+	for _, tt := range tests {
+		got, err := toClaudeToolChoice(tt.choice)
+		if err != nil {
+			t.Errorf("toClaudeToolChoice(%v) error = %v", tt.choice, err)
+			continue
+		}
+		if got["type"] != tt.wantType || (tt.wantName != "" && got["name"] != tt.wantName) {
+			t.Errorf("toClaudeToolChoice(%v) = %v", tt.choice, got)
+		}
+	}
+	if _, err := toClaudeToolChoice("sometimes"); err == nil {
+		t.Error("expected error for unknown tool_choice")
+	}
+}
+
+func TestClaudeAdapter_ChatCompletion(t *testing.T) {
+	var gotReq map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/messages" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		fmt.Fprint(w, `{
+			"id": "msg_1",
+			"type": "message",
+			"role": "assistant",
+			"model": "claude-3-5-sonnet-20241022",
+			"content": [
+				{"type": "text", "text": "Let me check "},
+				{"type": "text", "text": "the weather."},
+				{"type": "tool_use", "id": "toolu_1", "name": "get_weather", "input": {"city": "Paris"}}
+			],
+			"stop_reason": "tool_use",
+			"usage": {"input_tokens": 20, "output_tokens": 15}
+		}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewClaudeAdapter("test-key", server.URL)
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "claude-3-5-sonnet",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Weather in Paris?"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	if gotReq["model"] != "claude-3-5-sonnet-20241022" {
+		t.Errorf("expected mapped model name, got %v", gotReq["model"])
+	}
+
+	choice := resp.Choices[0]
+	if choice.Message.Content != "Let me check the weather." {
+		t.Errorf("expected concatenated text blocks, got %q", choice.Message.Content)
+	}
+	if choice.FinishReason != "tool_calls" {
+		t.Errorf("expected finish_reason 'tool_calls', got %q", choice.FinishReason)
+	}
+	if len(choice.Message.ToolCalls) != 1 || choice.Message.ToolCalls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected tool calls: %+v", choice.Message.ToolCalls)
+	}
+	if resp.Usage.TotalTokens != 35 {
+		t.Errorf("expected 35 total tokens, got %d", resp.Usage.TotalTokens)
+	}
+}
+
+func TestClaudeAdapter_ReasoningEffort(t *testing.T) {
+	adapter := &ClaudeAdapter{}
+	maxTokens := 20000
+	claudeReq, err := adapter.convertToClaudeRequest(&models.ChatCompletionRequest{
+		Model: "claude-3-7-sonnet-20250219",
+		Messages: []models.ChatMessage{
+			{Role: "developer", Content: "Be brief."},
+			{Role: "user", Content: "Hi"},
+		},
+		MaxCompletionTokens: &maxTokens,
+		ReasoningEffort:     "medium",
+	})
+	if err != nil {
+		t.Fatalf("convertToClaudeRequest() error = %v", err)
+	}
+	if claudeReq.System != "Be brief." {
+		t.Errorf("expected developer message as system prompt, got %q", claudeReq.System)
+	}
+	if claudeReq.Thinking == nil || claudeReq.Thinking.BudgetTokens != 8192 || claudeReq.MaxTokens != 20000 {
+		t.Errorf("unexpected thinking %+v with max_tokens %d", claudeReq.Thinking, claudeReq.MaxTokens)
+	}
+
+	claudeReq, _ = adapter.convertToClaudeRequest(&models.ChatCompletionRequest{
+		Model:           "claude-3-7-sonnet-20250219",
+		Messages:        []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		ReasoningEffort: "minimal",
+	})
+	if claudeReq.Thinking != nil {
+		t.Errorf("expected no thinking for minimal effort, got %+v", claudeReq.Thinking)
+	}
+
+	if _, err := adapter.convertToClaudeRequest(&models.ChatCompletionRequest{
+		Model:           "claude-3-7-sonnet-20250219",
+		Messages:        []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		ReasoningEffort: "extreme",
+	}); err == nil {
+		t.Error("expected error for unsupported reasoning_effort")
+	}
+}
+
+func TestClaudeAdapter_ThinkingBudgetWithinMaxTokens(t *testing.T) {
+	adapter := &ClaudeAdapter{}
+	maxTokens := 4096
+	claudeReq, err := adapter.convertToClaudeRequest(&models.ChatCompletionRequest{
+		Model:           "claude-3-7-sonnet-20250219",
+		Messages:        []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		MaxTokens:       &maxTokens,
+		ReasoningEffort: "high",
+	})
+	if err != nil {
+		t.Fatalf("convertToClaudeRequest() error = %v", err)
+	}
+	// reasoning_effort 换算的预算收紧到 max_tokens-1
+	if claudeReq.Thinking == nil || claudeReq.Thinking.BudgetTokens != 4095 || claudeReq.MaxTokens != 4096 {
+		t.Errorf("unexpected thinking %+v with max_tokens %d", claudeReq.Thinking, claudeReq.MaxTokens)
+	}
+
+	// 收紧后低于最小预算时不开启思考
+	maxTokens = 1000
+	claudeReq, err = adapter.convertToClaudeRequest(&models.ChatCompletionRequest{
+		Model:           "claude-3-7-sonnet-20250219",
+		Messages:        []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		MaxTokens:       &maxTokens,
+		ReasoningEffort: "medium",
+	})
+	if err != nil || claudeReq.Thinking != nil {
+		t.Errorf("expected thinking to be skipped, got %+v, %v", claudeReq.Thinking, err)
+	}
+
+	// 显式指定的预算不小于 max_tokens 时返回错误
+	_, err = adapter.convertToClaudeRequest(&models.ChatCompletionRequest{
+		Model:     "claude-3-7-sonnet-20250219",
+		Messages:  []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		MaxTokens: &maxTokens,
+		Thinking:  &models.ThinkingConfig{Type: "enabled", BudgetTokens: 2048},
+	})
+	if err == nil || !strings.Contains(err.Error(), "must be greater than thinking.budget_tokens") {
+		t.Errorf("expected budget validation error, got %v", err)
+	}
+}
+
+func TestClaudeAdapter_ThinkingConstraints(t *testing.T) {
+	adapter := &ClaudeAdapter{}
+	temperature, topP := 0.2, 0.5
+	claudeReq, err := adapter.convertToClaudeRequest(&models.ChatCompletionRequest{
+		Model:           "claude-3-7-sonnet-20250219",
+		Messages:        []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		Temperature:     &temperature,
+		TopP:            &topP,
+		ReasoningEffort: "low",
+	})
+	if err != nil {
+		t.Fatalf("convertToClaudeRequest() error = %v", err)
+	}
+	// 开启思考时不发送 temperature 和 top_p
+	if claudeReq.Thinking == nil || claudeReq.Temperature != nil || claudeReq.TopP != nil {
+		t.Errorf("expected sampling params dropped with thinking, got %+v", claudeReq)
+	}
+
+	tools := []models.Tool{{Type: "function", Function: models.FunctionDefinition{Name: "get_weather"}}}
+	for _, choice := range []interface{}{"required", map[string]interface{}{"type": "function", "function": map[string]interface{}{"name": "get_weather"}}} {
+		_, err = adapter.convertToClaudeRequest(&models.ChatCompletionRequest{
+			Model:           "claude-3-7-sonnet-20250219",
+			Messages:        []models.ChatMessage{{Role: "user", Content: "Hi"}},
+			Tools:           tools,
+			ToolChoice:      choice,
+			ReasoningEffort: "low",
+		})
+		if err == nil || !strings.Contains(err.Error(), "not supported when thinking is enabled") {
+			t.Errorf("expected forced tool_choice %v to be rejected, got %v", choice, err)
+		}
+	}
+
+	claudeReq, err = adapter.convertToClaudeRequest(&models.ChatCompletionRequest{
+		Model:           "claude-3-7-sonnet-20250219",
+		Messages:        []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		Tools:           tools,
+		ToolChoice:      "auto",
+		ReasoningEffort: "low",
+	})
+	if err != nil || claudeReq.ToolChoice.(map[string]interface{})["type"] != "auto" {
+		t.Errorf("expected auto tool_choice with thinking, got %v, %v", claudeReq.ToolChoice, err)
+	}
+}
+
+func TestClaudeAdapter_Thinking(t *testing.T) {
+	var gotReq ClaudeRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		fmt.Fprint(w, `{
+			"id": "msg_2",
+			"type": "message",
+			"role": "assistant",
+			"content": [
+				{"type": "thinking", "thinking": "20 < 25, so Paris.", "signature": "sig_2"},
+				{"type": "redacted_thinking", "data": "encrypted"},
+				{"type": "text", "text": "Paris is warmer."}
+			],
+			"stop_reason": "end_turn",
+			"usage": {"input_tokens": 50, "output_tokens": 30}
+		}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewClaudeAdapter("test-key", server.URL)
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "claude-3-7-sonnet-20250219",
+		Thinking: &models.ThinkingConfig{Type: "enabled", BudgetTokens: 2048},
+		Messages: []models.ChatMessage{
+			{Role: "user", Content: "Which is warmer, Berlin or Paris?"},
+			{
+				Role:             "assistant",
+				ReasoningContent: "I need the weather.",
+				ThinkingBlocks:   []models.ThinkingBlock{{Type: "thinking", Thinking: "I need the weather.", Signature: "sig_1"}},
+				ToolCalls: []models.ToolCall{{
+					ID:       "toolu_1",
+					Type:     "function",
+					Function: models.FunctionCall{Name: "get_weather", Arguments: `{}`},
+				}},
+			},
+			{Role: "tool", ToolCallID: "toolu_1", Content: "Berlin 20, Paris 25"},
+		},
+		Tools: []models.Tool{{Type: "function", Function: models.FunctionDefinition{Name: "get_weather"}}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	// 请求：开启思考并回传签名的思考块
+	if gotReq.Thinking == nil || gotReq.Thinking.Type != "enabled" || gotReq.Thinking.BudgetTokens != 2048 {
+		t.Errorf("unexpected thinking config: %+v", gotReq.Thinking)
+	}
+	if gotReq.MaxTokens <= 2048 {
+		t.Errorf("expected max_tokens above the thinking budget, got %d", gotReq.MaxTokens)
+	}
+	blocks := gotReq.Messages[1].Content.([]interface{})
+	first := blocks[0].(map[string]interface{})
+	if first["type"] != "thinking" || first["signature"] != "sig_1" || first["thinking"] != "I need the weather." {
+		t.Errorf("expected signed thinking block first, got %v", first)
+	}
+
+	// 响应：思考内容与思考块
+	msg := resp.Choices[0].Message
+	if msg.Content != "Paris is warmer." || msg.ReasoningContent != "20 < 25, so Paris." {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+	if len(msg.ThinkingBlocks) != 2 || msg.ThinkingBlocks[0].Signature != "sig_2" || msg.ThinkingBlocks[1].Data != "encrypted" {
+		t.Errorf("unexpected thinking blocks: %+v", msg.ThinkingBlocks)
+	}
+}
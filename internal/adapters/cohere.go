@@ -1,24 +1,42 @@
 package adapters
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gotoailab/llmhub/internal/models"
 )
 
 // CohereAdapter Cohere适配器
+// 使用 Cohere 原生的 /v2/chat 接口，引用（citations）作为消息元数据返回
 type CohereAdapter struct {
-	*openAICompatibleAdapter
+	apiKey  string
+	baseURL string
+	client  *http.Client
 }
 
 // NewCohereAdapter 创建Cohere适配器
 func NewCohereAdapter(apiKey, baseURL string) (Adapter, error) {
 	if baseURL == "" {
-		baseURL = "https://api.cohere.ai/v1"
+		baseURL = "https://api.cohere.com"
 	}
-	adapter := NewOpenAICompatibleAdapter(Provider("cohere"), apiKey, baseURL, "/chat/completions", "Bearer")
-	return &CohereAdapter{adapter}, nil
+	// 兼容旧配置中带版本号的地址
+	baseURL = strings.TrimRight(baseURL, "/")
+	baseURL = strings.TrimSuffix(strings.TrimSuffix(baseURL, "/v1"), "/v2")
+
+	return &CohereAdapter{
+		apiKey:  apiKey,
+		baseURL: baseURL,
+		client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}, nil
 }
 
 func (a *CohereAdapter) GetProvider() Provider {
@@ -26,9 +44,357 @@ func (a *CohereAdapter) GetProvider() Provider {
 }
 
 func (a *CohereAdapter) ChatCompletion(ctx context.Context, req *models.ChatCompletionRequest) (*models.ChatCompletionResponse, error) {
-	return a.openAICompatibleAdapter.ChatCompletion(ctx, req)
+	cohereReq, err := a.convertToCohereRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+	cohereReq.Stream = false
+
+	resp, err := a.do(ctx, cohereReq)
+	if err != nil {
+		return nil, fmt.Errorf("cohere api error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var cohereResp CohereResponse
+	if err := json.NewDecoder(resp.Body).Decode(&cohereResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return a.convertFromCohereResponse(&cohereResp, req.Model), nil
 }
 
 func (a *CohereAdapter) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
-	return a.openAICompatibleAdapter.ChatCompletionStream(ctx, req)
+	cohereReq, err := a.convertToCohereRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+	cohereReq.Stream = true
+
+	resp, err := a.do(ctx, cohereReq)
+	if err != nil {
+		return nil, fmt.Errorf("cohere stream error: %w", err)
+	}
+
+	return newCohereStream(resp.Body, req), nil
+}
+
+func (a *CohereAdapter) do(ctx context.Context, cohereReq *CohereRequest) (*http.Response, error) {
+	reqBody, err := json.Marshal(cohereReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", a.baseURL+"/v2/chat", bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+a.apiKey)
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("status %d, body: %s", resp.StatusCode, string(body))
+	}
+	return resp, nil
+}
+
+type CohereRequest struct {
+	Model            string                `json:"model"`
+	Messages         []CohereMessage       `json:"messages"`
+	Tools            []models.Tool         `json:"tools,omitempty"`
+	ToolChoice       string                `json:"tool_choice,omitempty"` // "REQUIRED" 或 "NONE"，不设置时由模型决定
+	ResponseFormat   *CohereResponseFormat `json:"response_format,omitempty"`
+	Stream           bool                  `json:"stream"`
+	MaxTokens        *int                  `json:"max_tokens,omitempty"`
+	Temperature      *float64              `json:"temperature,omitempty"`
+	P                *float64              `json:"p,omitempty"`
+	Seed             *int                  `json:"seed,omitempty"`
+	StopSequences    []string              `json:"stop_sequences,omitempty"`
+	FrequencyPenalty *float64              `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64              `json:"presence_penalty,omitempty"`
+}
+
+type CohereMessage struct {
+	Role       string            `json:"role"`
+	Content    interface{}       `json:"content,omitempty"` // 请求中为字符串，响应中为内容块数组
+	ToolPlan   string            `json:"tool_plan,omitempty"`
+	ToolCalls  []models.ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string            `json:"tool_call_id,omitempty"`
+}
+
+type CohereResponseFormat struct {
+	Type       string      `json:"type"`
+	JSONSchema interface{} `json:"json_schema,omitempty"`
+}
+
+type CohereResponse struct {
+	ID           string                `json:"id"`
+	FinishReason string                `json:"finish_reason"`
+	Message      CohereResponseMessage `json:"message"`
+	Usage        *CohereUsage          `json:"usage,omitempty"`
+}
+
+type CohereResponseMessage struct {
+	Role      string               `json:"role"`
+	Content   []CohereContentBlock `json:"content,omitempty"`
+	ToolPlan  string               `json:"tool_plan,omitempty"`
+	ToolCalls []models.ToolCall    `json:"tool_calls,omitempty"`
+	Citations []CohereCitation     `json:"citations,omitempty"`
+}
+
+type CohereContentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+type CohereCitation struct {
+	Start   int                    `json:"start"`
+	End     int                    `json:"end"`
+	Text    string                 `json:"text"`
+	Sources []CohereCitationSource `json:"sources,omitempty"`
+}
+
+type CohereCitationSource struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id,omitempty"`
+	ToolOutput map[string]interface{} `json:"tool_output,omitempty"`
+	Document   map[string]interface{} `json:"document,omitempty"`
+}
+
+type CohereUsage struct {
+	BilledUnits *CohereTokens `json:"billed_units,omitempty"`
+	Tokens      *CohereTokens `json:"tokens,omitempty"`
+}
+
+type CohereTokens struct {
+	InputTokens  float64 `json:"input_tokens"`
+	OutputTokens float64 `json:"output_tokens"`
+}
+
+// toUsage 优先使用计费用量，缺失时回退到实际 token 数
+func (u *CohereUsage) toUsage() *models.Usage {
+	if u == nil {
+		return nil
+	}
+	tokens := u.BilledUnits
+	if tokens == nil {
+		tokens = u.Tokens
+	}
+	if tokens == nil {
+		return nil
+	}
+	return &models.Usage{
+		PromptTokens:     int(tokens.InputTokens),
+		CompletionTokens: int(tokens.OutputTokens),
+		TotalTokens:      int(tokens.InputTokens + tokens.OutputTokens),
+	}
+}
+
+func (a *CohereAdapter) convertToCohereRequest(req *models.ChatCompletionRequest) (*CohereRequest, error) {
+	// 消息内容按纯文本发送，图片会被丢弃
+	if hasContentPart(req.Messages, "image_url") {
+		return nil, fmt.Errorf("image input not supported for provider %s", a.GetProvider())
+	}
+
+	cohereReq := &CohereRequest{
+		Model:            req.Model,
+		Messages:         make([]CohereMessage, 0, len(req.Messages)),
+		MaxTokens:        maxOutputTokens(req),
+		Temperature:      req.Temperature,
+		P:                req.TopP,
+		Seed:             req.Seed,
+		StopSequences:    req.Stop,
+		FrequencyPenalty: req.FrequencyPenalty,
+		PresencePenalty:  req.PresencePenalty,
+	}
+
+	for _, msg := range req.Messages {
+		cohereMsg := CohereMessage{
+			Role:    nativeRole(msg.Role),
+			Content: contentToText(msg.Content),
+		}
+
+		switch msg.Role {
+		case "assistant":
+			cohereMsg.ToolCalls = msg.ToolCalls
+			if msg.FunctionCall != nil {
+				cohereMsg.ToolCalls = append(cohereMsg.ToolCalls, models.ToolCall{
+					ID:       newToolCallID(),
+					Type:     "function",
+					Function: *msg.FunctionCall,
+				})
+			}
+			// 带工具调用的 assistant 消息中，文本即模型给出的 tool_plan
+			if len(cohereMsg.ToolCalls) > 0 {
+				cohereMsg.ToolPlan = contentToText(msg.Content)
+				cohereMsg.Content = nil
+			}
+		case "tool", "function":
+			cohereMsg.Role = "tool"
+			cohereMsg.ToolCallID = msg.ToolCallID
+		}
+
+		cohereReq.Messages = append(cohereReq.Messages, cohereMsg)
+	}
+
+	cohereReq.Tools = append(cohereReq.Tools, req.Tools...)
+	for _, fn := range req.Functions {
+		cohereReq.Tools = append(cohereReq.Tools, models.Tool{Type: "function", Function: fn})
+	}
+
+	toolChoice := req.ToolChoice
+	if toolChoice == nil {
+		toolChoice = req.FunctionCall
+	}
+	if toolChoice != nil {
+		choice, tools, err := toCohereToolChoice(toolChoice, cohereReq.Tools)
+		if err != nil {
+			return nil, err
+		}
+		cohereReq.ToolChoice = choice
+		cohereReq.Tools = tools
+	}
+
+	if req.ResponseFormat != nil {
+		switch req.ResponseFormat.Type {
+		case "json_object":
+			cohereReq.ResponseFormat = &CohereResponseFormat{Type: "json_object"}
+		case "json_schema":
+			if req.ResponseFormat.JSONSchema == nil || req.ResponseFormat.JSONSchema.Schema == nil {
+				return nil, fmt.Errorf("response_format json_schema requires a schema")
+			}
+			cohereReq.ResponseFormat = &CohereResponseFormat{
+				Type:       "json_object",
+				JSONSchema: req.ResponseFormat.JSONSchema.Schema,
+			}
+		}
+	}
+
+	return cohereReq, nil
+}
+
+// toCohereToolChoice 转换 tool_choice
+// Cohere 只支持 REQUIRED 和 NONE，指定函数时只保留该工具并设为 REQUIRED
+func toCohereToolChoice(toolChoice interface{}, tools []models.Tool) (string, []models.Tool, error) {
+	var name string
+	switch v := toolChoice.(type) {
+	case string:
+		switch v {
+		case "auto":
+			return "", tools, nil
+		case "none":
+			return "NONE", tools, nil
+		case "required", "any":
+			return "REQUIRED", tools, nil
+		}
+		return "", nil, fmt.Errorf("unsupported tool_choice %q", v)
+	case map[string]interface{}:
+		if fn, ok := v["function"].(map[string]interface{}); ok {
+			name, _ = fn["name"].(string)
+		} else {
+			name, _ = v["name"].(string)
+		}
+	}
+	if name == "" {
+		return "", nil, fmt.Errorf("unsupported tool_choice %v", toolChoice)
+	}
+
+	for _, tool := range tools {
+		if tool.Function.Name == name {
+			return "REQUIRED", []models.Tool{tool}, nil
+		}
+	}
+	return "", nil, fmt.Errorf("tool_choice function %q not found in tools", name)
+}
+
+func (a *CohereAdapter) convertFromCohereResponse(cohereResp *CohereResponse, modelName string) *models.ChatCompletionResponse {
+	var content strings.Builder
+	for _, block := range cohereResp.Message.Content {
+		if block.Type == "text" {
+			content.WriteString(block.Text)
+		}
+	}
+
+	message := models.ChatMessage{
+		Role:      "assistant",
+		Content:   content.String(),
+		ToolCalls: cohereResp.Message.ToolCalls,
+		Citations: citationsFromCohere(cohereResp.Message.Citations),
+	}
+	// 只有工具调用时，tool_plan 作为回复文本返回，下一轮请求时再还原为 tool_plan
+	if content.Len() == 0 && cohereResp.Message.ToolPlan != "" {
+		message.Content = cohereResp.Message.ToolPlan
+	}
+
+	resp := &models.ChatCompletionResponse{
+		ID:      cohereResp.ID,
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   modelName,
+		Choices: []models.ChatCompletionChoice{
+			{
+				Index:        0,
+				Message:      message,
+				FinishReason: mapCohereFinishReason(cohereResp.FinishReason),
+			},
+		},
+	}
+	if usage := cohereResp.Usage.toUsage(); usage != nil {
+		resp.Usage = *usage
+	}
+	return resp
+}
+
+// citationsFromCohere 将 Cohere 的引用转换为通用格式
+func citationsFromCohere(citations []CohereCitation) []models.Citation {
+	var result []models.Citation
+	for _, c := range citations {
+		citation := models.Citation{
+			Start: c.Start,
+			End:   c.End,
+			Text:  c.Text,
+		}
+		for _, s := range c.Sources {
+			source := models.CitationSource{
+				Type: s.Type,
+				ID:   s.ID,
+				Data: s.Document,
+			}
+			if s.Type == "tool" {
+				source.Data = s.ToolOutput
+			}
+			if title, ok := source.Data["title"].(string); ok {
+				source.Title = title
+			}
+			citation.Sources = append(citation.Sources, source)
+		}
+		result = append(result, citation)
+	}
+	return result
+}
+
+// mapCohereFinishReason 将 Cohere 的 finish_reason 映射为 OpenAI 的 finish_reason
+func mapCohereFinishReason(reason string) string {
+	switch reason {
+	case "COMPLETE", "STOP_SEQUENCE":
+		return "stop"
+	case "MAX_TOKENS":
+		return "length"
+	case "TOOL_CALL":
+		return "tool_calls"
+	case "ERROR_TOXIC":
+		return "content_filter"
+	case "":
+		return ""
+	default:
+		return strings.ToLower(reason)
+	}
 }
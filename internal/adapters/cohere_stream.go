@@ -0,0 +1,154 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// CohereStreamEvent Cohere v2 流式事件，type 决定 delta 中携带的字段
+type CohereStreamEvent struct {
+	Type  string             `json:"type"`
+	ID    string             `json:"id,omitempty"`
+	Index int                `json:"index"`
+	Delta *CohereStreamDelta `json:"delta,omitempty"`
+}
+
+type CohereStreamDelta struct {
+	Message      *CohereStreamMessage `json:"message,omitempty"`
+	FinishReason string               `json:"finish_reason,omitempty"`
+	Usage        *CohereUsage         `json:"usage,omitempty"`
+	Error        string               `json:"error,omitempty"`
+}
+
+// CohereStreamMessage 流式事件中的消息片段，content、tool_calls、citations 均为单个对象
+type CohereStreamMessage struct {
+	Role      string              `json:"role,omitempty"`
+	Content   *CohereContentBlock `json:"content,omitempty"`
+	ToolPlan  string              `json:"tool_plan,omitempty"`
+	ToolCalls *models.ToolCall    `json:"tool_calls,omitempty"`
+	Citations *CohereCitation     `json:"citations,omitempty"`
+}
+
+// cohereStreamTranslator 将 Cohere 的类型化事件流转换为 OpenAI chat.completion.chunk
+type cohereStreamTranslator struct {
+	reader  *SSEReader
+	id      string
+	model   string
+	created int64
+	started bool
+}
+
+func newCohereStream(body io.ReadCloser, req *models.ChatCompletionRequest) io.ReadCloser {
+	t := &cohereStreamTranslator{
+		reader:  NewSSEReader(body),
+		id:      newResponseID(),
+		model:   req.Model,
+		created: time.Now().Unix(),
+	}
+	return newChunkStream(req, t.next, body)
+}
+
+func (t *cohereStreamTranslator) next() ([]*models.ChatCompletionStreamResponse, error) {
+	sse, err := t.reader.Next()
+	if err != nil {
+		return nil, err
+	}
+	if len(sse.Data) == 0 {
+		return nil, nil
+	}
+
+	var event CohereStreamEvent
+	if err := json.Unmarshal(sse.Data, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode cohere stream event: %w", err)
+	}
+
+	var message CohereStreamMessage
+	if event.Delta != nil && event.Delta.Message != nil {
+		message = *event.Delta.Message
+	}
+
+	switch event.Type {
+	case "message-start":
+		if event.ID != "" {
+			t.id = event.ID
+		}
+		return t.start(), nil
+
+	case "content-delta":
+		if message.Content == nil || message.Content.Text == "" {
+			return nil, nil
+		}
+		return append(t.start(), t.chunk(models.ChatMessageDelta{Content: message.Content.Text}, "")), nil
+
+	case "tool-plan-delta":
+		// 与非流式一致，tool_plan 作为回复文本输出
+		if message.ToolPlan == "" {
+			return nil, nil
+		}
+		return append(t.start(), t.chunk(models.ChatMessageDelta{Content: message.ToolPlan}, "")), nil
+
+	case "tool-call-start", "tool-call-delta":
+		if message.ToolCalls == nil {
+			return nil, nil
+		}
+		index := event.Index
+		toolCall := models.ToolCall{
+			Index:    &index,
+			Function: message.ToolCalls.Function,
+		}
+		// ID、类型和函数名只在 tool-call-start 中出现
+		if event.Type == "tool-call-start" {
+			toolCall.ID = message.ToolCalls.ID
+			toolCall.Type = "function"
+		}
+		return append(t.start(), t.chunk(models.ChatMessageDelta{ToolCalls: []models.ToolCall{toolCall}}, "")), nil
+
+	case "citation-start":
+		if message.Citations == nil {
+			return nil, nil
+		}
+		citations := citationsFromCohere([]CohereCitation{*message.Citations})
+		return append(t.start(), t.chunk(models.ChatMessageDelta{Citations: citations}, "")), nil
+
+	case "message-end":
+		if event.Delta == nil {
+			return t.start(), io.EOF
+		}
+		if event.Delta.Error != "" {
+			return nil, fmt.Errorf("cohere stream error: %s", event.Delta.Error)
+		}
+		chunks := append(t.start(), t.chunk(models.ChatMessageDelta{}, mapCohereFinishReason(event.Delta.FinishReason)))
+		chunks[len(chunks)-1].Usage = event.Delta.Usage.toUsage()
+		return chunks, io.EOF
+	}
+
+	// content-start、content-end、tool-call-end、citation-end 等事件无需转发
+	return nil, nil
+}
+
+// start 在第一次输出前发送带 role 的块
+func (t *cohereStreamTranslator) start() []*models.ChatCompletionStreamResponse {
+	if t.started {
+		return nil
+	}
+	t.started = true
+	return []*models.ChatCompletionStreamResponse{t.chunk(models.ChatMessageDelta{Role: "assistant"}, "")}
+}
+
+func (t *cohereStreamTranslator) chunk(delta models.ChatMessageDelta, finishReason string) *models.ChatCompletionStreamResponse {
+	return &models.ChatCompletionStreamResponse{
+		ID:      t.id,
+		Object:  "chat.completion.chunk",
+		Created: t.created,
+		Model:   t.model,
+		Choices: []models.ChatCompletionStreamChoice{{
+			Index:        0,
+			Delta:        delta,
+			FinishReason: finishReason,
+		}},
+	}
+}
@@ -0,0 +1,244 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestCohereAdapter_ChatCompletion(t *testing.T) {
+	var gotReq map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/chat" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("unexpected Authorization header %q", r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		fmt.Fprint(w, `{
+			"id": "resp_1",
+			"finish_reason": "COMPLETE",
+			"message": {
+				"role": "assistant",
+				"content": [{"type": "text", "text": "It is 20 degrees "}, {"type": "text", "text": "in Berlin."}],
+				"citations": [{"start": 6, "end": 16, "text": "20 degrees", "sources": [{"type": "tool", "id": "call_1:0", "tool_output": {"temperature": "20"}}]}]
+			},
+			"usage": {"billed_units": {"input_tokens": 40, "output_tokens": 9}, "tokens": {"input_tokens": 250, "output_tokens": 9}}
+		}`)
+	}))
+	defer server.Close()
+
+	topP, maxTokens := 0.9, 100
+	adapter, _ := NewCohereAdapter("test-key", server.URL+"/v1")
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "command-r-plus",
+		Messages: []models.ChatMessage{
+			{Role: "system", Content: "Be brief."},
+			{Role: "user", Content: "Weather in Berlin?"},
+			{Role: "assistant", Content: "I will look up the weather.", ToolCalls: []models.ToolCall{{
+				ID:       "call_1",
+				Type:     "function",
+				Function: models.FunctionCall{Name: "get_weather", Arguments: `{"city":"Berlin"}`},
+			}}},
+			{Role: "tool", ToolCallID: "call_1", Content: `{"temperature":"20"}`},
+		},
+		Tools: []models.Tool{
+			{Type: "function", Function: models.FunctionDefinition{Name: "get_weather"}},
+			{Type: "function", Function: models.FunctionDefinition{Name: "get_time"}},
+		},
+		ToolChoice: map[string]interface{}{"type": "function", "function": map[string]interface{}{"name": "get_weather"}},
+		TopP:       &topP,
+		MaxTokens:  &maxTokens,
+		Stop:       []string{"END"},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	// 请求转换
+	if gotReq["p"] != 0.9 || gotReq["max_tokens"] != float64(100) || gotReq["tool_choice"] != "REQUIRED" {
+		t.Errorf("unexpected request parameters: %v", gotReq)
+	}
+	if stop, ok := gotReq["stop_sequences"].([]interface{}); !ok || len(stop) != 1 {
+		t.Errorf("expected stop_sequences, got %v", gotReq["stop_sequences"])
+	}
+	if tools := gotReq["tools"].([]interface{}); len(tools) != 1 {
+		t.Errorf("expected tools restricted to the chosen function, got %v", tools)
+	}
+	messages := gotReq["messages"].([]interface{})
+	assistant := messages[2].(map[string]interface{})
+	if assistant["tool_plan"] != "I will look up the weather." || assistant["content"] != nil {
+		t.Errorf("expected assistant text sent as tool_plan, got %v", assistant)
+	}
+	if tool := messages[3].(map[string]interface{}); tool["role"] != "tool" || tool["tool_call_id"] != "call_1" {
+		t.Errorf("unexpected tool message: %v", tool)
+	}
+
+	// 响应转换
+	choice := resp.Choices[0]
+	if choice.Message.Content != "It is 20 degrees in Berlin." || choice.FinishReason != "stop" {
+		t.Errorf("unexpected choice: %+v", choice)
+	}
+	if len(choice.Message.Citations) != 1 {
+		t.Fatalf("expected one citation, got %+v", choice.Message.Citations)
+	}
+	citation := choice.Message.Citations[0]
+	if citation.Text != "20 degrees" || citation.Start != 6 || citation.End != 16 {
+		t.Errorf("unexpected citation: %+v", citation)
+	}
+	if len(citation.Sources) != 1 || citation.Sources[0].Type != "tool" || citation.Sources[0].Data["temperature"] != "20" {
+		t.Errorf("unexpected citation sources: %+v", citation.Sources)
+	}
+	if resp.Usage.PromptTokens != 40 || resp.Usage.CompletionTokens != 9 || resp.Usage.TotalTokens != 49 {
+		t.Errorf("expected billed usage, got %+v", resp.Usage)
+	}
+}
+
+func TestCohereAdapter_ToolCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"id": "resp_2",
+			"finish_reason": "TOOL_CALL",
+			"message": {
+				"role": "assistant",
+				"tool_plan": "I will check the weather in Paris.",
+				"tool_calls": [{"id": "get_weather_abc", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\":\"Paris\"}"}}]
+			},
+			"usage": {"billed_units": {"input_tokens": 30, "output_tokens": 12}}
+		}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewCohereAdapter("test-key", server.URL)
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "command-r-plus",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Weather in Paris?"}},
+		Tools:    []models.Tool{{Type: "function", Function: models.FunctionDefinition{Name: "get_weather"}}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	choice := resp.Choices[0]
+	if choice.FinishReason != "tool_calls" || choice.Message.Content != "I will check the weather in Paris." {
+		t.Errorf("unexpected choice: %+v", choice)
+	}
+	if len(choice.Message.ToolCalls) != 1 || choice.Message.ToolCalls[0].ID != "get_weather_abc" ||
+		choice.Message.ToolCalls[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected tool calls: %+v", choice.Message.ToolCalls)
+	}
+}
+
+func TestCohereToolChoice(t *testing.T) {
+	tools := []models.Tool{{Type: "function", Function: models.FunctionDefinition{Name: "f"}}}
+	tests := []struct {
+		choice interface{}
+		want   string
+	}{
+		{"auto", ""},
+		{"none", "NONE"},
+		{"required", "REQUIRED"},
+		{map[string]interface{}{"name": "f"}, "REQUIRED"},
+	}
+	for _, tt := range tests {
+		got, _, err := toCohereToolChoice(tt.choice, tools)
+		if err != nil || got != tt.want {
+			t.Errorf("toCohereToolChoice(%v) = %q, %v, want %q", tt.choice, got, err, tt.want)
+		}
+	}
+	if _, _, err := toCohereToolChoice(map[string]interface{}{"name": "missing"}, tools); err == nil {
+		t.Error("expected error for unknown function")
+	}
+}
+
+func TestCohereAdapter_ChatCompletionStream(t *testing.T) {
+	events := []string{
+		`{"type":"message-start","id":"resp_3","delta":{"message":{"role":"assistant"}}}`,
+		`{"type":"tool-plan-delta","delta":{"message":{"tool_plan":"Checking."}}}`,
+		`{"type":"tool-call-start","index":0,"delta":{"message":{"tool_calls":{"id":"get_weather_1","type":"function","function":{"name":"get_weather","arguments":""}}}}}`,
+		`{"type":"tool-call-delta","index":0,"delta":{"message":{"tool_calls":{"function":{"arguments":"{\"city\":"}}}}}`,
+		`{"type":"tool-call-delta","index":0,"delta":{"message":{"tool_calls":{"function":{"arguments":"\"Paris\"}"}}}}}`,
+		`{"type":"tool-call-end","index":0}`,
+		`{"type":"content-start","index":0,"delta":{"message":{"content":{"type":"text","text":""}}}}`,
+		`{"type":"content-delta","index":0,"delta":{"message":{"content":{"text":"Sunny"}}}}`,
+		`{"type":"citation-start","index":0,"delta":{"message":{"citations":{"start":0,"end":5,"text":"Sunny","sources":[{"type":"document","id":"doc_0","document":{"title":"Forecast"}}]}}}}`,
+		`{"type":"content-end","index":0}`,
+		`{"type":"message-end","delta":{"finish_reason":"COMPLETE","usage":{"billed_units":{"input_tokens":5,"output_tokens":2}}}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CohereRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream {
+			t.Error("expected stream true")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			var typ struct {
+				Type string `json:"type"`
+			}
+			json.Unmarshal([]byte(event), &typ)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", typ.Type, event)
+		}
+	}))
+	defer server.Close()
+
+	adapter, _ := NewCohereAdapter("test-key", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:         "command-r-plus",
+		Messages:      []models.ChatMessage{{Role: "user", Content: "Weather in Paris?"}},
+		StreamOptions: &models.StreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, done := collectStreamChunks(t, stream)
+	if !done {
+		t.Error("expected stream to end with data: [DONE]")
+	}
+	if len(chunks) != 9 {
+		t.Fatalf("expected 9 chunks, got %d", len(chunks))
+	}
+	if chunks[0].ID != "resp_3" || chunks[0].Choices[0].Delta.Role != "assistant" {
+		t.Errorf("unexpected role chunk: %+v", chunks[0])
+	}
+	if chunks[1].Choices[0].Delta.Content != "Checking." {
+		t.Errorf("expected tool plan as content, got %+v", chunks[1].Choices[0].Delta)
+	}
+
+	var args strings.Builder
+	for _, chunk := range chunks[2:5] {
+		tc := chunk.Choices[0].Delta.ToolCalls
+		if len(tc) != 1 || tc[0].Index == nil || *tc[0].Index != 0 {
+			t.Fatalf("unexpected tool call delta: %+v", tc)
+		}
+		args.WriteString(tc[0].Function.Arguments)
+	}
+	if tc := chunks[2].Choices[0].Delta.ToolCalls[0]; tc.ID != "get_weather_1" || tc.Function.Name != "get_weather" {
+		t.Errorf("unexpected tool call start: %+v", tc)
+	}
+	if args.String() != `{"city":"Paris"}` {
+		t.Errorf("unexpected tool arguments %q", args.String())
+	}
+
+	if chunks[5].Choices[0].Delta.Content != "Sunny" {
+		t.Errorf("unexpected content delta: %+v", chunks[5].Choices[0].Delta)
+	}
+	if c := chunks[6].Choices[0].Delta.Citations; len(c) != 1 || c[0].Sources[0].Title != "Forecast" {
+		t.Errorf("unexpected citation delta: %+v", c)
+	}
+	if chunks[7].Choices[0].FinishReason != "stop" {
+		t.Errorf("expected finish_reason 'stop', got %q", chunks[7].Choices[0].FinishReason)
+	}
+	if chunks[8].Usage == nil || chunks[8].Usage.TotalTokens != 7 {
+		t.Errorf("expected usage chunk with 7 total tokens, got %+v", chunks[8].Usage)
+	}
+}
@@ -0,0 +1,84 @@
+package adapters
+
+import (
+	"encoding/json"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// contentParts 将消息内容统一转换为 []models.ContentPart
+// 支持字符串、[]models.ContentPart 以及 JSON 解码得到的 []interface{}，无法识别时返回 false
+func contentParts(content interface{}) ([]models.ContentPart, bool) {
+	switch v := content.(type) {
+	case nil:
+		return nil, true
+	case string:
+		if v == "" {
+			return nil, true
+		}
+		return []models.ContentPart{{Type: "text", Text: v}}, true
+	case []models.ContentPart:
+		return v, true
+	case []interface{}, []map[string]interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, false
+		}
+		var parts []models.ContentPart
+		if err := json.Unmarshal(data, &parts); err != nil {
+			return nil, false
+		}
+		return parts, true
+	}
+	return nil, false
+}
+
+// hasContentPart 判断对话中是否包含指定类型的内容片段，如 "image_url"、"document"
+func hasContentPart(msgs []models.ChatMessage, partType string) bool {
+	for _, msg := range msgs {
+		if _, isString := msg.Content.(string); isString {
+			continue
+		}
+		parts, _ := contentParts(msg.Content)
+		for _, part := range parts {
+			if part.Type == partType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseDataURI 解析 data:<mime>;base64,<data> 形式的 data URI，返回 MIME 类型和 base64 数据
+func parseDataURI(uri string) (mimeType, data string, ok bool) {
+	rest, found := strings.CutPrefix(uri, "data:")
+	if !found {
+		return "", "", false
+	}
+	meta, data, found := strings.Cut(rest, ",")
+	if !found {
+		return "", "", false
+	}
+	mimeType, found = strings.CutSuffix(meta, ";base64")
+	if !found {
+		return "", "", false
+	}
+	return mimeType, data, true
+}
+
+// mimeTypeFromURL 根据 URL 的扩展名推断 MIME 类型，无法推断时返回 fallback
+func mimeTypeFromURL(rawURL, fallback string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fallback
+	}
+	if mimeType := mime.TypeByExtension(path.Ext(u.Path)); mimeType != "" {
+		mimeType, _, _ = strings.Cut(mimeType, ";")
+		return mimeType
+	}
+	return fallback
+}
@@ -0,0 +1,74 @@
+package adapters
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestContentParts(t *testing.T) {
+	// JSON 解码得到的内容与类型化的片段结果一致
+	decoded := []interface{}{
+		map[string]interface{}{"type": "text", "text": "Describe "},
+		map[string]interface{}{"type": "image_url", "image_url": map[string]interface{}{"url": "data:image/png;base64,AAAA", "detail": "low"}},
+		map[string]interface{}{"type": "text", "text": "this."},
+	}
+	parts, ok := contentParts(decoded)
+	if !ok || len(parts) != 3 || parts[1].ImageURL == nil || parts[1].ImageURL.Detail != "low" {
+		t.Fatalf("unexpected parts: %+v", parts)
+	}
+	if text := contentToText(decoded); text != "Describe this." {
+		t.Errorf("contentToText() = %q", text)
+	}
+	if !hasContentPart([]models.ChatMessage{{Role: "user", Content: decoded}}, "image_url") {
+		t.Error("expected image content to be detected")
+	}
+	if hasContentPart([]models.ChatMessage{{Role: "user", Content: "hi"}}, "image_url") {
+		t.Error("expected no image content for plain text")
+	}
+}
+
+func TestParseDataURI(t *testing.T) {
+	mimeType, data, ok := parseDataURI("data:image/jpeg;base64,/9j/4AAQ")
+	if !ok || mimeType != "image/jpeg" || data != "/9j/4AAQ" {
+		t.Errorf("parseDataURI() = %q, %q, %v", mimeType, data, ok)
+	}
+	for _, uri := range []string{"https://example.com/cat.jpg", "data:text/plain,hello"} {
+		if _, _, ok := parseDataURI(uri); ok {
+			t.Errorf("expected %q not to parse as base64 data URI", uri)
+		}
+	}
+}
+
+func TestTextOnlyAdapters_RejectImages(t *testing.T) {
+	req := &models.ChatCompletionRequest{
+		Model: "m",
+		Messages: []models.ChatMessage{{Role: "user", Content: []models.ContentPart{
+			{Type: "text", Text: "What is in this image?"},
+			{Type: "image_url", ImageURL: &models.ImageURL{URL: "https://example.com/cat.png"}},
+		}}},
+	}
+
+	factories := map[string]func() (Adapter, error){
+		"ernie":   func() (Adapter, error) { return NewErnieAdapter("ak:sk", "http://127.0.0.1:0") },
+		"spark":   func() (Adapter, error) { return NewSparkAdapter("app-id:api-key:api-secret", "ws://127.0.0.1:0") },
+		"hunyuan": func() (Adapter, error) { return NewHunyuanAdapter("secret-id:secret-key", "http://127.0.0.1:0") },
+		"coze":    func() (Adapter, error) { return NewCozeAdapter("token", "http://127.0.0.1:0") },
+		"cohere":  func() (Adapter, error) { return NewCohereAdapter("key", "http://127.0.0.1:0") },
+	}
+	for provider, factory := range factories {
+		adapter, err := factory()
+		if err != nil {
+			t.Fatalf("%s: create adapter error = %v", provider, err)
+		}
+		want := "image input not supported for provider " + provider
+		if _, err := adapter.ChatCompletion(context.Background(), req); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected image error, got %v", provider, err)
+		}
+		if _, err := adapter.ChatCompletionStream(context.Background(), req); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected image error from stream, got %v", provider, err)
+		}
+	}
+}
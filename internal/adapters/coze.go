@@ -1,24 +1,50 @@
 package adapters
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/gotoailab/llmhub/internal/models"
 )
 
+// 非流式对话的轮询间隔
+const cozeDefaultPollInterval = time.Second
+
+// 未指定 user 时使用的默认用户 ID
+const cozeDefaultUserID = "llmhub"
+
 // CozeAdapter Coze适配器
+// 使用 Coze v3 对话接口，模型名称即 Bot ID，可带 "coze/" 前缀，如 "coze/7351234567890123456"
 type CozeAdapter struct {
-	*openAICompatibleAdapter
+	apiKey       string
+	baseURL      string
+	client       *http.Client
+	pollInterval time.Duration
 }
 
 // NewCozeAdapter 创建Coze适配器
 func NewCozeAdapter(apiKey, baseURL string) (Adapter, error) {
 	if baseURL == "" {
-		baseURL = "https://api.coze.cn/v1"
+		baseURL = "https://api.coze.cn"
 	}
-	adapter := NewOpenAICompatibleAdapter(Provider("coze"), apiKey, baseURL, "/chat/completions", "Bearer")
-	return &CozeAdapter{adapter}, nil
+	// 兼容旧配置中带 /v1 的地址
+	baseURL = strings.TrimSuffix(strings.TrimRight(baseURL, "/"), "/v1")
+
+	return &CozeAdapter{
+		apiKey:  apiKey,
+		baseURL: baseURL,
+		client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+		pollInterval: cozeDefaultPollInterval,
+	}, nil
 }
 
 func (a *CozeAdapter) GetProvider() Provider {
@@ -26,9 +52,315 @@ func (a *CozeAdapter) GetProvider() Provider {
 }
 
 func (a *CozeAdapter) ChatCompletion(ctx context.Context, req *models.ChatCompletionRequest) (*models.ChatCompletionResponse, error) {
-	return a.openAICompatibleAdapter.ChatCompletion(ctx, req)
+	cozeReq, err := a.convertToCozeRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+	// 非流式对话必须保存历史，才能通过 message/list 读取回复
+	cozeReq.Stream = false
+	cozeReq.AutoSaveHistory = true
+
+	reqBody, err := json.Marshal(cozeReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	var chat CozeChat
+	if err := a.call(ctx, "POST", "/v3/chat", nil, reqBody, &chat); err != nil {
+		return nil, err
+	}
+
+	// 轮询直到对话结束
+	for !chat.finished() {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(a.pollInterval):
+		}
+
+		query := url.Values{}
+		query.Set("conversation_id", chat.ConversationID)
+		query.Set("chat_id", chat.ID)
+		if err := a.call(ctx, "GET", "/v3/chat/retrieve", query, nil, &chat); err != nil {
+			return nil, err
+		}
+	}
+	if err := chat.err(); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("conversation_id", chat.ConversationID)
+	query.Set("chat_id", chat.ID)
+	var messages []CozeMessage
+	if err := a.call(ctx, "GET", "/v3/chat/message/list", query, nil, &messages); err != nil {
+		return nil, err
+	}
+
+	return a.convertFromCozeChat(&chat, messages, req.Model), nil
 }
 
 func (a *CozeAdapter) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
-	return a.openAICompatibleAdapter.ChatCompletionStream(ctx, req)
+	cozeReq, err := a.convertToCozeRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+	cozeReq.Stream = true
+
+	reqBody, err := json.Marshal(cozeReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := a.newRequest(ctx, "POST", "/v3/chat", nil, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("coze stream error: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("coze stream error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	// 参数错误时 Coze 直接返回 JSON 而不是 SSE
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		defer resp.Body.Close()
+		var envelope CozeEnvelope
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return nil, fmt.Errorf("coze stream error: code %d, message: %s", envelope.Code, envelope.Msg)
+	}
+
+	return newCozeStream(resp.Body, req), nil
+}
+
+func (a *CozeAdapter) newRequest(ctx context.Context, method, path string, query url.Values, body []byte) (*http.Request, error) {
+	endpoint := a.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Authorization", "Bearer "+a.apiKey)
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	return httpReq, nil
+}
+
+// call 调用 Coze 接口并将 data 字段解析到 out
+func (a *CozeAdapter) call(ctx context.Context, method, path string, query url.Values, body []byte, out interface{}) error {
+	httpReq, err := a.newRequest(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("coze api error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("coze api error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var envelope CozeEnvelope
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if envelope.Code != 0 {
+		return fmt.Errorf("coze api error: code %d, message: %s", envelope.Code, envelope.Msg)
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("failed to decode response data: %w", err)
+	}
+	return nil
+}
+
+type CozeRequest struct {
+	BotID              string        `json:"bot_id"`
+	UserID             string        `json:"user_id"`
+	AdditionalMessages []CozeMessage `json:"additional_messages"`
+	Stream             bool          `json:"stream"`
+	AutoSaveHistory    bool          `json:"auto_save_history"`
+}
+
+type CozeMessage struct {
+	ID             string `json:"id,omitempty"`
+	ConversationID string `json:"conversation_id,omitempty"`
+	ChatID         string `json:"chat_id,omitempty"`
+	Role           string `json:"role"`
+	Type           string `json:"type,omitempty"`
+	Content        string `json:"content"`
+	ContentType    string `json:"content_type"`
+}
+
+// CozeEnvelope Coze 接口的响应外层结构
+type CozeEnvelope struct {
+	Code int             `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+type CozeChat struct {
+	ID             string         `json:"id"`
+	ConversationID string         `json:"conversation_id"`
+	BotID          string         `json:"bot_id"`
+	CreatedAt      int64          `json:"created_at"`
+	Status         string         `json:"status"`
+	LastError      *CozeLastError `json:"last_error,omitempty"`
+	Usage          *CozeUsage     `json:"usage,omitempty"`
+}
+
+type CozeLastError struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+type CozeUsage struct {
+	TokenCount  int `json:"token_count"`
+	OutputCount int `json:"output_count"`
+	InputCount  int `json:"input_count"`
+}
+
+func (u *CozeUsage) toUsage() *models.Usage {
+	if u == nil {
+		return nil
+	}
+	return &models.Usage{
+		PromptTokens:     u.InputCount,
+		CompletionTokens: u.OutputCount,
+		TotalTokens:      u.TokenCount,
+	}
+}
+
+// finished 判断对话是否已结束（包括失败和取消）
+func (c *CozeChat) finished() bool {
+	switch c.Status {
+	case "created", "in_progress":
+		return false
+	}
+	return true
+}
+
+// err 返回对话未正常完成的原因
+func (c *CozeChat) err() error {
+	switch c.Status {
+	case "completed":
+		return nil
+	case "failed":
+		if c.LastError != nil {
+			return fmt.Errorf("coze chat failed: code %d, message: %s", c.LastError.Code, c.LastError.Msg)
+		}
+		return fmt.Errorf("coze chat failed")
+	case "requires_action":
+		return fmt.Errorf("coze chat requires action: client-side plugins are not supported")
+	default:
+		return fmt.Errorf("coze chat ended with status %q", c.Status)
+	}
+}
+
+func (a *CozeAdapter) convertToCozeRequest(req *models.ChatCompletionRequest) (*CozeRequest, error) {
+	// 消息内容按纯文本发送，图片会被丢弃
+	if hasContentPart(req.Messages, "image_url") {
+		return nil, fmt.Errorf("image input not supported for provider %s", a.GetProvider())
+	}
+
+	cozeReq := &CozeRequest{
+		BotID:              a.mapBotID(req.Model),
+		UserID:             req.User,
+		AdditionalMessages: make([]CozeMessage, 0, len(req.Messages)),
+	}
+	if cozeReq.UserID == "" {
+		cozeReq.UserID = cozeDefaultUserID
+	}
+
+	// Bot 的插件在 Coze 平台配置，不支持调用方定义的工具
+	if len(req.Tools) > 0 || len(req.Functions) > 0 {
+		return nil, fmt.Errorf("tool use not supported for provider %s", a.GetProvider())
+	}
+
+	for _, msg := range req.Messages {
+		role, msgType := msg.Role, ""
+		switch nativeRole(msg.Role) {
+		case "user":
+			msgType = "question"
+		case "assistant":
+			msgType = "answer"
+		case "system":
+			// additional_messages 只接受 user 和 assistant，system 消息作为用户消息发送，与 Bot 人设叠加
+			role, msgType = "user", "question"
+		default:
+			return nil, fmt.Errorf("message role %q not supported for provider %s", msg.Role, a.GetProvider())
+		}
+		cozeReq.AdditionalMessages = append(cozeReq.AdditionalMessages, CozeMessage{
+			Role:        role,
+			Type:        msgType,
+			Content:     contentToText(msg.Content),
+			ContentType: "text",
+		})
+	}
+	if len(cozeReq.AdditionalMessages) == 0 {
+		return nil, fmt.Errorf("at least one user message is required")
+	}
+
+	return cozeReq, nil
+}
+
+func (a *CozeAdapter) convertFromCozeChat(chat *CozeChat, messages []CozeMessage, modelName string) *models.ChatCompletionResponse {
+	// 只取 Bot 的回答，忽略插件调用、推荐问题等中间消息
+	var content strings.Builder
+	for _, msg := range messages {
+		if msg.Role == "assistant" && msg.Type == "answer" {
+			content.WriteString(msg.Content)
+		}
+	}
+
+	created := chat.CreatedAt
+	if created == 0 {
+		created = time.Now().Unix()
+	}
+
+	resp := &models.ChatCompletionResponse{
+		ID:      chat.ID,
+		Object:  "chat.completion",
+		Created: created,
+		Model:   modelName,
+		Choices: []models.ChatCompletionChoice{
+			{
+				Index: 0,
+				Message: models.ChatMessage{
+					Role:    "assistant",
+					Content: content.String(),
+				},
+				FinishReason: "stop",
+			},
+		},
+	}
+	if usage := chat.Usage.toUsage(); usage != nil {
+		resp.Usage = *usage
+	}
+	return resp
+}
+
+// mapBotID 将模型名称映射为 Bot ID
+func (a *CozeAdapter) mapBotID(modelName string) string {
+	return strings.TrimPrefix(modelName, "coze/")
 }
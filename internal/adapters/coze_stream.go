@@ -0,0 +1,108 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// cozeStreamTranslator 将 Coze v3 对话事件流转换为 OpenAI chat.completion.chunk
+type cozeStreamTranslator struct {
+	reader  *SSEReader
+	id      string
+	model   string
+	created int64
+	started bool
+}
+
+func newCozeStream(body io.ReadCloser, req *models.ChatCompletionRequest) io.ReadCloser {
+	t := &cozeStreamTranslator{
+		reader:  NewSSEReader(body),
+		model:   req.Model,
+		created: time.Now().Unix(),
+	}
+	return newChunkStream(req, t.next, body)
+}
+
+func (t *cozeStreamTranslator) next() ([]*models.ChatCompletionStreamResponse, error) {
+	sse, err := t.reader.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch sse.Event {
+	case "conversation.chat.created":
+		var chat CozeChat
+		if err := json.Unmarshal(sse.Data, &chat); err != nil {
+			return nil, fmt.Errorf("failed to decode coze stream event: %w", err)
+		}
+		t.id = chat.ID
+		return t.start(), nil
+
+	case "conversation.message.delta":
+		var msg CozeMessage
+		if err := json.Unmarshal(sse.Data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to decode coze stream event: %w", err)
+		}
+		if msg.Type != "answer" || msg.Content == "" {
+			return nil, nil
+		}
+		if t.id == "" {
+			t.id = msg.ChatID
+		}
+		return append(t.start(), t.chunk(models.ChatMessageDelta{Content: msg.Content}, "")), nil
+
+	case "conversation.chat.completed":
+		var chat CozeChat
+		if err := json.Unmarshal(sse.Data, &chat); err != nil {
+			return nil, fmt.Errorf("failed to decode coze stream event: %w", err)
+		}
+		chunks := append(t.start(), t.chunk(models.ChatMessageDelta{}, "stop"))
+		chunks[len(chunks)-1].Usage = chat.Usage.toUsage()
+		return chunks, nil
+
+	case "conversation.chat.failed", "conversation.chat.requires_action":
+		var chat CozeChat
+		if err := json.Unmarshal(sse.Data, &chat); err != nil {
+			return nil, fmt.Errorf("failed to decode coze stream event: %w", err)
+		}
+		return nil, chat.err()
+
+	case "error":
+		var lastError CozeLastError
+		json.Unmarshal(sse.Data, &lastError)
+		return nil, fmt.Errorf("coze stream error: code %d, message: %s", lastError.Code, lastError.Msg)
+
+	case "done":
+		return nil, io.EOF
+	}
+
+	// in_progress、message.completed 等事件无需转发
+	return nil, nil
+}
+
+// start 在第一次输出前发送带 role 的块
+func (t *cozeStreamTranslator) start() []*models.ChatCompletionStreamResponse {
+	if t.started {
+		return nil
+	}
+	t.started = true
+	return []*models.ChatCompletionStreamResponse{t.chunk(models.ChatMessageDelta{Role: "assistant"}, "")}
+}
+
+func (t *cozeStreamTranslator) chunk(delta models.ChatMessageDelta, finishReason string) *models.ChatCompletionStreamResponse {
+	return &models.ChatCompletionStreamResponse{
+		ID:      t.id,
+		Object:  "chat.completion.chunk",
+		Created: t.created,
+		Model:   t.model,
+		Choices: []models.ChatCompletionStreamChoice{{
+			Index:        0,
+			Delta:        delta,
+			FinishReason: finishReason,
+		}},
+	}
+}
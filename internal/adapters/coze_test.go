@@ -0,0 +1,200 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestCozeAdapter_ChatCompletion(t *testing.T) {
+	var gotReq CozeRequest
+	retrieves := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("unexpected Authorization header %q", r.Header.Get("Authorization"))
+		}
+		switch r.URL.Path {
+		case "/v3/chat":
+			json.NewDecoder(r.Body).Decode(&gotReq)
+			fmt.Fprint(w, `{"code":0,"msg":"","data":{"id":"chat_1","conversation_id":"conv_1","bot_id":"7351","created_at":1718000000,"status":"in_progress"}}`)
+		case "/v3/chat/retrieve":
+			if r.URL.Query().Get("conversation_id") != "conv_1" || r.URL.Query().Get("chat_id") != "chat_1" {
+				t.Errorf("unexpected retrieve query %s", r.URL.RawQuery)
+			}
+			retrieves++
+			if retrieves < 2 {
+				fmt.Fprint(w, `{"code":0,"data":{"id":"chat_1","conversation_id":"conv_1","status":"in_progress"}}`)
+				return
+			}
+			fmt.Fprint(w, `{"code":0,"data":{"id":"chat_1","conversation_id":"conv_1","created_at":1718000000,"status":"completed","usage":{"token_count":30,"output_count":10,"input_count":20}}}`)
+		case "/v3/chat/message/list":
+			fmt.Fprint(w, `{"code":0,"data":[
+				{"role":"assistant","type":"function_call","content":"{\"name\":\"search\"}","content_type":"text"},
+				{"role":"assistant","type":"answer","content":"Hello from bot","content_type":"text"},
+				{"role":"assistant","type":"follow_up","content":"Anything else?","content_type":"text"}
+			]}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	adapter, _ := NewCozeAdapter("test-token", server.URL+"/v1")
+	adapter.(*CozeAdapter).pollInterval = time.Millisecond
+
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "coze/7351",
+		User:  "user-42",
+		Messages: []models.ChatMessage{
+			{Role: "system", Content: "Be brief."},
+			{Role: "user", Content: "Hi"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	// 请求转换
+	if gotReq.BotID != "7351" || gotReq.UserID != "user-42" || gotReq.Stream || !gotReq.AutoSaveHistory {
+		t.Errorf("unexpected request: %+v", gotReq)
+	}
+	// system 消息作为用户消息发送
+	if len(gotReq.AdditionalMessages) != 2 || gotReq.AdditionalMessages[0].Role != "user" || gotReq.AdditionalMessages[0].Content != "Be brief." ||
+		gotReq.AdditionalMessages[1].Role != "user" || gotReq.AdditionalMessages[1].Content != "Hi" {
+		t.Errorf("unexpected additional_messages: %+v", gotReq.AdditionalMessages)
+	}
+	if retrieves != 2 {
+		t.Errorf("expected 2 retrieve calls, got %d", retrieves)
+	}
+
+	// 响应转换
+	if resp.ID != "chat_1" || resp.Model != "coze/7351" || resp.Created != 1718000000 {
+		t.Errorf("unexpected response metadata: %+v", resp)
+	}
+	if resp.Choices[0].Message.Content != "Hello from bot" || resp.Choices[0].FinishReason != "stop" {
+		t.Errorf("unexpected choice: %+v", resp.Choices[0])
+	}
+	if resp.Usage.PromptTokens != 20 || resp.Usage.CompletionTokens != 10 || resp.Usage.TotalTokens != 30 {
+		t.Errorf("unexpected usage: %+v", resp.Usage)
+	}
+}
+
+func TestCozeAdapter_ChatCompletionFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":0,"data":{"id":"chat_1","conversation_id":"conv_1","status":"failed","last_error":{"code":4011,"msg":"insufficient balance"}}}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewCozeAdapter("test-token", server.URL)
+	_, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "7351",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "insufficient balance") {
+		t.Errorf("expected chat failure, got %v", err)
+	}
+}
+
+func TestCozeAdapter_ChatCompletionStream(t *testing.T) {
+	events := []string{
+		"event:conversation.chat.created\ndata:{\"id\":\"chat_1\",\"conversation_id\":\"conv_1\",\"status\":\"created\"}\n\n",
+		"event:conversation.chat.in_progress\ndata:{\"id\":\"chat_1\",\"conversation_id\":\"conv_1\",\"status\":\"in_progress\"}\n\n",
+		"event:conversation.message.delta\ndata:{\"chat_id\":\"chat_1\",\"role\":\"assistant\",\"type\":\"answer\",\"content\":\"Hello\",\"content_type\":\"text\"}\n\n",
+		"event:conversation.message.delta\ndata:{\"chat_id\":\"chat_1\",\"role\":\"assistant\",\"type\":\"answer\",\"content\":\" there\",\"content_type\":\"text\"}\n\n",
+		"event:conversation.message.completed\ndata:{\"chat_id\":\"chat_1\",\"role\":\"assistant\",\"type\":\"answer\",\"content\":\"Hello there\",\"content_type\":\"text\"}\n\n",
+		"event:conversation.chat.completed\ndata:{\"id\":\"chat_1\",\"conversation_id\":\"conv_1\",\"status\":\"completed\",\"usage\":{\"token_count\":7,\"output_count\":2,\"input_count\":5}}\n\n",
+		"event:done\ndata:\"[DONE]\"\n\n",
+	}
+	var gotReq CozeRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			fmt.Fprint(w, event)
+		}
+	}))
+	defer server.Close()
+
+	adapter, _ := NewCozeAdapter("test-token", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:         "7351",
+		Messages:      []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		StreamOptions: &models.StreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	if !gotReq.Stream || gotReq.UserID != cozeDefaultUserID {
+		t.Errorf("unexpected request: %+v", gotReq)
+	}
+
+	chunks, done := collectStreamChunks(t, stream)
+	if !done {
+		t.Error("expected stream to end with data: [DONE]")
+	}
+	if len(chunks) != 5 {
+		t.Fatalf("expected 5 chunks, got %d", len(chunks))
+	}
+	if chunks[0].ID != "chat_1" || chunks[0].Choices[0].Delta.Role != "assistant" {
+		t.Errorf("unexpected role chunk: %+v", chunks[0])
+	}
+	if chunks[1].Choices[0].Delta.Content != "Hello" || chunks[2].Choices[0].Delta.Content != " there" {
+		t.Errorf("unexpected deltas")
+	}
+	if chunks[3].Choices[0].FinishReason != "stop" {
+		t.Errorf("expected finish_reason 'stop', got %q", chunks[3].Choices[0].FinishReason)
+	}
+	if chunks[4].Usage == nil || chunks[4].Usage.TotalTokens != 7 {
+		t.Errorf("expected usage chunk with 7 total tokens, got %+v", chunks[4].Usage)
+	}
+}
+
+func TestCozeAdapter_ChatCompletionStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"code":4200,"msg":"bot not published"}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewCozeAdapter("test-token", server.URL)
+	_, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "7351",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "bot not published") {
+		t.Errorf("expected coze error, got %v", err)
+	}
+}
+
+func TestCozeAdapter_RejectsUnsupportedInput(t *testing.T) {
+	adapter, _ := NewCozeAdapter("test-token", "http://127.0.0.1:0")
+
+	_, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "coze/7351",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Weather?"}},
+		Tools:    []models.Tool{{Type: "function", Function: models.FunctionDefinition{Name: "get_weather"}}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "tool use not supported for provider coze") {
+		t.Errorf("expected tool use error, got %v", err)
+	}
+
+	_, err = adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model: "coze/7351",
+		Messages: []models.ChatMessage{
+			{Role: "user", Content: "Weather?"},
+			{Role: "tool", Content: `{"temp":20}`, ToolCallID: "call_1"},
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), `message role "tool" not supported`) {
+		t.Errorf("expected tool message error, got %v", err)
+	}
+}
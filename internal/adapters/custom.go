@@ -0,0 +1,101 @@
+package adapters
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CustomProviderConfig 通过配置声明的 OpenAI 兼容提供商，如 Perplexity、Fireworks、vLLM、LM Studio
+type CustomProviderConfig struct {
+	Name       string            // 提供商名称，注册到 Registry 的 key
+	BaseURL    string            // 默认 API 基础 URL
+	Endpoint   string            // 接口路径，默认为 /chat/completions
+	AuthHeader string            // 认证头名称，默认为 Authorization
+	AuthScheme string            // 认证方案，如 Bearer；AuthHeader 为空时默认为 Bearer，否则直接发送 API Key
+	Headers    map[string]string // 每个请求附带的额外静态请求头
+
+	SupportsTools         bool // 是否支持工具调用
+	SupportsVision        bool // 是否接受图片输入
+	SupportsStreamOptions bool // 是否转发 stream_options
+
+	SupportsReasoningEffort bool // 是否转发 reasoning_effort
+}
+
+// customProviders 已通过配置注册的提供商，允许重复注册以更新配置，与 Registry 一起由 registryMu 保护
+var customProviders = make(map[Provider]bool)
+
+// RegisterCustomProvider 将配置声明的提供商注册到 Registry，不能覆盖内置提供商，可在运行期间并发调用
+func RegisterCustomProvider(cfg CustomProviderConfig) error {
+	if cfg.Name == "" {
+		return fmt.Errorf("custom provider name is required")
+	}
+	if cfg.BaseURL == "" {
+		return fmt.Errorf("custom provider %s: base_url is required", cfg.Name)
+	}
+
+	provider := Provider(cfg.Name)
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := Registry[provider]; exists && !customProviders[provider] {
+		return fmt.Errorf("custom provider %s conflicts with a built-in provider", cfg.Name)
+	}
+
+	customProviders[provider] = true
+	Registry[provider] = func(apiKey, baseURL string) (Adapter, error) {
+		return NewCustomAdapter(cfg, apiKey, baseURL)
+	}
+	return nil
+}
+
+// CreateCustomAdapter 不经过注册表直接创建配置声明的提供商适配器
+func CreateCustomAdapter(cfg CustomProviderConfig, apiKey, baseURL string) (Adapter, error) {
+	adapter, err := NewCustomAdapter(cfg, apiKey, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	return withDocumentGuard(adapter), nil
+}
+
+// NewCustomAdapter 基于 OpenAI 兼容适配器创建配置声明的提供商适配器，baseURL 非空时覆盖配置中的地址
+func NewCustomAdapter(cfg CustomProviderConfig, apiKey, baseURL string) (Adapter, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("custom provider name is required")
+	}
+	if baseURL == "" {
+		baseURL = cfg.BaseURL
+	}
+	if baseURL == "" {
+		return nil, fmt.Errorf("custom provider %s: base_url is required", cfg.Name)
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = "/chat/completions"
+	} else if !strings.HasPrefix(endpoint, "/") {
+		endpoint = "/" + endpoint
+	}
+
+	authHeaderName, authScheme := cfg.AuthHeader, cfg.AuthScheme
+	if authHeaderName == "" {
+		authHeaderName = "Authorization"
+		if authScheme == "" {
+			authScheme = "Bearer"
+		}
+	}
+
+	headers := make(map[string]string, len(cfg.Headers))
+	for name, value := range cfg.Headers {
+		headers[name] = value
+	}
+
+	adapter := NewOpenAICompatibleAdapter(Provider(cfg.Name), apiKey, strings.TrimRight(baseURL, "/"), endpoint, authScheme)
+	// 构造函数把空方案视为 Bearer，这里保留空值以直接发送 API Key
+	adapter.authHeader = authScheme
+	adapter.authHeaderName = authHeaderName
+	adapter.headers = headers
+	adapter.supportsTools = cfg.SupportsTools
+	adapter.supportsVision = cfg.SupportsVision
+	adapter.supportsStreamOptions = cfg.SupportsStreamOptions
+	adapter.supportsReasoningEffort = cfg.SupportsReasoningEffort
+	return adapter, nil
+}
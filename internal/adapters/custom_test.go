@@ -0,0 +1,172 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestCustomAdapter_ChatCompletion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/chat" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("X-API-Key"); got != "Token test-key" {
+			t.Errorf("unexpected auth header %q", got)
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("expected no Authorization header, got %q", r.Header.Get("Authorization"))
+		}
+		if got := r.Header.Get("X-Org"); got != "acme" {
+			t.Errorf("expected static header X-Org, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"cmpl-1","object":"chat.completion","model":"sonar","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}],"usage":{"prompt_tokens":2,"completion_tokens":1,"total_tokens":3}}`)
+	}))
+	defer server.Close()
+
+	adapter, err := NewCustomAdapter(CustomProviderConfig{
+		Name:       "perplexity",
+		BaseURL:    "https://api.perplexity.ai",
+		Endpoint:   "api/v1/chat",
+		AuthHeader: "X-API-Key",
+		AuthScheme: "Token",
+		Headers:    map[string]string{"X-Org": "acme"},
+	}, "test-key", server.URL+"/")
+	if err != nil {
+		t.Fatalf("NewCustomAdapter() error = %v", err)
+	}
+	if adapter.GetProvider() != "perplexity" {
+		t.Errorf("unexpected provider %s", adapter.GetProvider())
+	}
+
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "sonar",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.Choices[0].Message.Content != "Hi" || resp.Usage.TotalTokens != 3 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestCustomAdapter_Capabilities(t *testing.T) {
+	var gotReq map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer local" {
+			t.Errorf("expected default bearer auth, got %q", got)
+		}
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"ok\"}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	adapter, err := NewCustomAdapter(CustomProviderConfig{Name: "vllm", BaseURL: server.URL}, "local", "")
+	if err != nil {
+		t.Fatalf("NewCustomAdapter() error = %v", err)
+	}
+
+	tools := []models.Tool{{Type: "function", Function: models.FunctionDefinition{Name: "f"}}}
+	_, err = adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "llama",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		Tools:    tools,
+	})
+	if err == nil || !strings.Contains(err.Error(), "tool use not supported") {
+		t.Errorf("expected tool use error, got %v", err)
+	}
+
+	_, err = adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "llama",
+		Messages: []models.ChatMessage{{Role: "user", Content: []interface{}{
+			map[string]interface{}{"type": "image_url", "image_url": map[string]interface{}{"url": "https://example.com/cat.png"}},
+		}}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "image input not supported") {
+		t.Errorf("expected image input error, got %v", err)
+	}
+
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:         "llama",
+		Messages:      []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		StreamOptions: &models.StreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	io.ReadAll(stream)
+	stream.Close()
+	if _, ok := gotReq["stream_options"]; ok {
+		t.Errorf("expected stream_options to be dropped, got %v", gotReq["stream_options"])
+	}
+}
+
+func TestRegisterCustomProvider(t *testing.T) {
+	Register("groq", NewGroqAdapter)
+	defer unregisterProvider("groq")
+	if err := RegisterCustomProvider(CustomProviderConfig{Name: "groq", BaseURL: "https://example.com"}); err == nil {
+		t.Error("expected error when overriding a built-in provider")
+	}
+	if err := RegisterCustomProvider(CustomProviderConfig{Name: "fireworks"}); err == nil {
+		t.Error("expected error for missing base_url")
+	}
+
+	cfg := CustomProviderConfig{Name: "fireworks", BaseURL: "https://api.fireworks.ai/inference/v1", SupportsTools: true}
+	if err := RegisterCustomProvider(cfg); err != nil {
+		t.Fatalf("RegisterCustomProvider() error = %v", err)
+	}
+	defer unregisterProvider("fireworks")
+	// 重复注册同名自定义提供商会更新配置
+	cfg.BaseURL = "https://fireworks.example.com/v1"
+	if err := RegisterCustomProvider(cfg); err != nil {
+		t.Fatalf("re-register error = %v", err)
+	}
+
+	adapter, err := CreateAdapter("fireworks", "key", "")
+	if err != nil {
+		t.Fatalf("CreateAdapter() error = %v", err)
+	}
+	compatible := adapter.(*documentGuard).Adapter.(*openAICompatibleAdapter)
+	if compatible.baseURL != "https://fireworks.example.com/v1" || !compatible.supportsTools {
+		t.Errorf("unexpected adapter: %+v", compatible)
+	}
+}
+
+func TestRegisterCustomProvider_Concurrent(t *testing.T) {
+	cfg := CustomProviderConfig{Name: "cerebras", BaseURL: "https://api.cerebras.ai/v1"}
+	if err := RegisterCustomProvider(cfg); err != nil {
+		t.Fatalf("RegisterCustomProvider() error = %v", err)
+	}
+	defer unregisterProvider("cerebras")
+
+	// 运行期间注册与创建适配器并发进行，配合 -race 检查
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := RegisterCustomProvider(cfg); err != nil {
+				t.Errorf("RegisterCustomProvider() error = %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := CreateAdapter("cerebras", "key", ""); err != nil {
+				t.Errorf("CreateAdapter() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
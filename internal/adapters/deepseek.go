@@ -76,6 +76,9 @@ func (a *DeepSeekAdapter) ChatCompletion(ctx context.Context, req *models.ChatCo
 func (a *DeepSeekAdapter) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
 	deepseekReq := a.convertToOpenAIFormat(req)
 	deepseekReq["stream"] = true
+	if req.StreamOptions != nil {
+		deepseekReq["stream_options"] = req.StreamOptions
+	}
 
 	reqBody, err := json.Marshal(deepseekReq)
 	if err != nil {
@@ -100,20 +103,20 @@ func (a *DeepSeekAdapter) ChatCompletionStream(ctx context.Context, req *models.
 		return nil, fmt.Errorf("deepseek stream error: status %d", resp.StatusCode)
 	}
 
-	return resp.Body, nil
+	return newOpenAICompatibleStream(resp.Body, req), nil
 }
 
 func (a *DeepSeekAdapter) convertToOpenAIFormat(req *models.ChatCompletionRequest) map[string]interface{} {
 	messages := make([]map[string]interface{}, 0, len(req.Messages))
 	for _, msg := range req.Messages {
 		msgMap := map[string]interface{}{
-			"role": msg.Role,
+			"role": nativeRole(msg.Role),
 		}
 
 		switch v := msg.Content.(type) {
 		case string:
 			msgMap["content"] = v
-		case []interface{}:
+		case []interface{}, []models.ContentPart:
 			msgMap["content"] = v
 		default:
 			msgMap["content"] = fmt.Sprintf("%v", v)
@@ -133,8 +136,8 @@ func (a *DeepSeekAdapter) convertToOpenAIFormat(req *models.ChatCompletionReques
 	if req.TopP != nil {
 		result["top_p"] = *req.TopP
 	}
-	if req.MaxTokens != nil {
-		result["max_tokens"] = *req.MaxTokens
+	if maxTokens := maxOutputTokens(req); maxTokens != nil {
+		result["max_tokens"] = *maxTokens
 	}
 	if req.Stop != nil && len(req.Stop) > 0 {
 		result["stop"] = req.Stop
@@ -1,24 +1,86 @@
 package adapters
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gotoailab/llmhub/internal/models"
 )
 
+// access_token 提前刷新的时间，避免请求过程中过期
+const ernieTokenRefreshMargin = 5 * time.Minute
+
+// 千帆返回的 access_token 无效或过期的错误码
+const (
+	ernieErrInvalidToken = 110
+	ernieErrTokenExpired = 111
+)
+
 // ErnieAdapter 文心一言（百度）适配器
+// 使用千帆 wenxinworkshop 原生接口，apiKey 格式为 "API Key:Secret Key"
 type ErnieAdapter struct {
-	*openAICompatibleAdapter
+	apiKey    string
+	secretKey string
+	baseURL   string
+	client    *http.Client
+	tokens    *ernieTokenCache
+}
+
+// ernieTokenCache access_token 缓存，mu 保证并发时只有一个请求去刷新
+type ernieTokenCache struct {
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// 网关每个请求都会创建新的适配器，access_token 缓存按基础 URL 和密钥在包级别共享
+var (
+	ernieTokensMu sync.Mutex
+	ernieTokens   = make(map[string]*ernieTokenCache)
+)
+
+// sharedErnieTokenCache 返回同一组凭据共享的 access_token 缓存
+func sharedErnieTokenCache(baseURL, apiKey, secretKey string) *ernieTokenCache {
+	key := baseURL + "|" + apiKey + ":" + secretKey
+	ernieTokensMu.Lock()
+	defer ernieTokensMu.Unlock()
+	cache, ok := ernieTokens[key]
+	if !ok {
+		cache = &ernieTokenCache{}
+		ernieTokens[key] = cache
+	}
+	return cache
 }
 
 // NewErnieAdapter 创建文心一言（百度）适配器
 func NewErnieAdapter(apiKey, baseURL string) (Adapter, error) {
 	if baseURL == "" {
-		baseURL = "https://aip.baidubce.com/rpc/2.0/ai_custom/v1/wenxinworkshop/chat"
+		baseURL = "https://aip.baidubce.com"
 	}
-	adapter := NewOpenAICompatibleAdapter(Provider("ernie"), apiKey, baseURL, "/completions", "Bearer")
-	return &ErnieAdapter{adapter}, nil
+
+	key, secret, ok := strings.Cut(apiKey, ":")
+	if !ok || key == "" || secret == "" {
+		return nil, fmt.Errorf("ernie api key must be in the format \"API_KEY:SECRET_KEY\"")
+	}
+
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &ErnieAdapter{
+		apiKey:    key,
+		secretKey: secret,
+		baseURL:   baseURL,
+		client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+		tokens: sharedErnieTokenCache(baseURL, key, secret),
+	}, nil
 }
 
 func (a *ErnieAdapter) GetProvider() Provider {
@@ -26,9 +88,336 @@ func (a *ErnieAdapter) GetProvider() Provider {
 }
 
 func (a *ErnieAdapter) ChatCompletion(ctx context.Context, req *models.ChatCompletionRequest) (*models.ChatCompletionResponse, error) {
-	return a.openAICompatibleAdapter.ChatCompletion(ctx, req)
+	if len(req.Tools) > 0 || len(req.Functions) > 0 {
+		return nil, fmt.Errorf("tool use not supported for provider %s", a.GetProvider())
+	}
+
+	ernieReq, err := a.convertToErnieRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+
+	reqBody, err := json.Marshal(ernieReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// access_token 失效时刷新后重试一次
+	for attempt := 0; ; attempt++ {
+		resp, err := a.do(ctx, req.Model, reqBody)
+		if err != nil {
+			return nil, err
+		}
+
+		var ernieResp ErnieResponse
+		err = json.NewDecoder(resp.Body).Decode(&ernieResp)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		if ernieResp.ErrorCode != 0 {
+			if isErnieTokenError(ernieResp.ErrorCode) && attempt == 0 {
+				a.invalidateToken()
+				continue
+			}
+			return nil, fmt.Errorf("ernie api error: code %d, message: %s", ernieResp.ErrorCode, ernieResp.ErrorMsg)
+		}
+
+		return a.convertFromErnieResponse(&ernieResp, req.Model), nil
+	}
 }
 
 func (a *ErnieAdapter) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
-	return a.openAICompatibleAdapter.ChatCompletionStream(ctx, req)
+	if len(req.Tools) > 0 || len(req.Functions) > 0 {
+		return nil, fmt.Errorf("tool use not supported for provider %s", a.GetProvider())
+	}
+
+	ernieReq, err := a.convertToErnieRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+	ernieReq.Stream = true
+
+	reqBody, err := json.Marshal(ernieReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := a.do(ctx, req.Model, reqBody)
+		if err != nil {
+			return nil, err
+		}
+
+		// 出错时千帆直接返回 JSON 而不是 SSE
+		if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+			var ernieResp ErnieResponse
+			err := json.NewDecoder(resp.Body).Decode(&ernieResp)
+			resp.Body.Close()
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode response: %w", err)
+			}
+			if isErnieTokenError(ernieResp.ErrorCode) && attempt == 0 {
+				a.invalidateToken()
+				continue
+			}
+			return nil, fmt.Errorf("ernie stream error: code %d, message: %s", ernieResp.ErrorCode, ernieResp.ErrorMsg)
+		}
+
+		return newErnieStream(resp.Body, req), nil
+	}
+}
+
+// do 携带 access_token 发送对话请求
+func (a *ErnieAdapter) do(ctx context.Context, model string, body []byte) (*http.Response, error) {
+	token, err := a.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/rpc/2.0/ai_custom/v1/wenxinworkshop/chat/%s?access_token=%s",
+		a.baseURL, a.mapModelEndpoint(model), url.QueryEscape(token))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("ernie api error: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("ernie api error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+	return resp, nil
+}
+
+// accessToken 返回缓存的 access_token，过期或即将过期时重新获取
+func (a *ErnieAdapter) accessToken(ctx context.Context) (string, error) {
+	a.tokens.mu.Lock()
+	defer a.tokens.mu.Unlock()
+
+	if a.tokens.token != "" && time.Now().Before(a.tokens.expiresAt) {
+		return a.tokens.token, nil
+	}
+
+	query := url.Values{}
+	query.Set("grant_type", "client_credentials")
+	query.Set("client_id", a.apiKey)
+	query.Set("client_secret", a.secretKey)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", a.baseURL+"/oauth/2.0/token?"+query.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("ernie token error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tokenResp ErnieTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return "", fmt.Errorf("ernie token error: status %d, %s: %s", resp.StatusCode, tokenResp.Error, tokenResp.ErrorDescription)
+	}
+
+	expiresIn := time.Duration(tokenResp.ExpiresIn) * time.Second
+	if expiresIn > ernieTokenRefreshMargin {
+		expiresIn -= ernieTokenRefreshMargin
+	}
+	a.tokens.token = tokenResp.AccessToken
+	a.tokens.expiresAt = time.Now().Add(expiresIn)
+	return a.tokens.token, nil
+}
+
+// invalidateToken 丢弃缓存的 access_token，下次请求时重新获取
+func (a *ErnieAdapter) invalidateToken() {
+	a.tokens.mu.Lock()
+	defer a.tokens.mu.Unlock()
+	a.tokens.token = ""
+	a.tokens.expiresAt = time.Time{}
+}
+
+func isErnieTokenError(code int) bool {
+	return code == ernieErrInvalidToken || code == ernieErrTokenExpired
+}
+
+type ErnieTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error,omitempty"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+type ErnieRequest struct {
+	Messages        []ErnieMessage `json:"messages"`
+	System          string         `json:"system,omitempty"`
+	Temperature     *float64       `json:"temperature,omitempty"`
+	TopP            *float64       `json:"top_p,omitempty"`
+	PenaltyScore    *float64       `json:"penalty_score,omitempty"`
+	Stop            []string       `json:"stop,omitempty"`
+	MaxOutputTokens *int           `json:"max_output_tokens,omitempty"`
+	UserID          string         `json:"user_id,omitempty"`
+	Stream          bool           `json:"stream,omitempty"`
+}
+
+type ErnieMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ErnieResponse struct {
+	ID               string     `json:"id"`
+	Object           string     `json:"object"`
+	Created          int64      `json:"created"`
+	SentenceID       int        `json:"sentence_id,omitempty"`
+	IsEnd            bool       `json:"is_end,omitempty"`
+	IsTruncated      bool       `json:"is_truncated"`
+	Result           string     `json:"result"`
+	FinishReason     string     `json:"finish_reason,omitempty"`
+	NeedClearHistory bool       `json:"need_clear_history"`
+	Usage            ErnieUsage `json:"usage"`
+	ErrorCode        int        `json:"error_code,omitempty"`
+	ErrorMsg         string     `json:"error_msg,omitempty"`
+}
+
+type ErnieUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+func (a *ErnieAdapter) convertToErnieRequest(req *models.ChatCompletionRequest) (*ErnieRequest, error) {
+	// 消息内容按纯文本发送，图片会被丢弃
+	if hasContentPart(req.Messages, "image_url") {
+		return nil, fmt.Errorf("image input not supported for provider %s", a.GetProvider())
+	}
+
+	ernieReq := &ErnieRequest{
+		Messages:    make([]ErnieMessage, 0, len(req.Messages)),
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		Stop:        req.Stop,
+		UserID:      req.User,
+	}
+	if maxTokens := maxOutputTokens(req); maxTokens != nil {
+		ernieReq.MaxOutputTokens = maxTokens
+	}
+	// 千帆的 penalty_score 取值 [1.0, 2.0]，由 frequency_penalty 平移得到
+	if req.FrequencyPenalty != nil && *req.FrequencyPenalty > 0 {
+		penalty := 1 + *req.FrequencyPenalty/2
+		ernieReq.PenaltyScore = &penalty
+	}
+
+	var system []string
+	for _, msg := range req.Messages {
+		text := contentToText(msg.Content)
+		if nativeRole(msg.Role) == "system" {
+			system = append(system, text)
+			continue
+		}
+
+		role := "user"
+		if msg.Role == "assistant" {
+			role = "assistant"
+		}
+
+		// 千帆要求 user/assistant 交替出现，相邻的同角色消息合并
+		if n := len(ernieReq.Messages); n > 0 && ernieReq.Messages[n-1].Role == role {
+			ernieReq.Messages[n-1].Content += "\n\n" + text
+			continue
+		}
+		ernieReq.Messages = append(ernieReq.Messages, ErnieMessage{Role: role, Content: text})
+	}
+
+	if len(ernieReq.Messages) == 0 {
+		return nil, fmt.Errorf("at least one non-system message is required")
+	}
+	if ernieReq.Messages[0].Role != "user" {
+		return nil, fmt.Errorf("the first non-system message must be from the user")
+	}
+	ernieReq.System = strings.Join(system, "\n\n")
+
+	return ernieReq, nil
+}
+
+func (a *ErnieAdapter) convertFromErnieResponse(ernieResp *ErnieResponse, modelName string) *models.ChatCompletionResponse {
+	id := ernieResp.ID
+	if id == "" {
+		id = newResponseID()
+	}
+	created := ernieResp.Created
+	if created == 0 {
+		created = time.Now().Unix()
+	}
+
+	return &models.ChatCompletionResponse{
+		ID:      id,
+		Object:  "chat.completion",
+		Created: created,
+		Model:   modelName,
+		Choices: []models.ChatCompletionChoice{
+			{
+				Index: 0,
+				Message: models.ChatMessage{
+					Role:    "assistant",
+					Content: ernieResp.Result,
+				},
+				FinishReason: mapErnieFinishReason(ernieResp.FinishReason, ernieResp.IsTruncated),
+			},
+		},
+		Usage: models.Usage{
+			PromptTokens:     ernieResp.Usage.PromptTokens,
+			CompletionTokens: ernieResp.Usage.CompletionTokens,
+			TotalTokens:      ernieResp.Usage.TotalTokens,
+		},
+	}
+}
+
+// mapErnieFinishReason 将千帆的 finish_reason 映射为 OpenAI 的 finish_reason
+func mapErnieFinishReason(reason string, truncated bool) string {
+	if truncated {
+		return "length"
+	}
+	switch reason {
+	case "", "normal", "stop":
+		return "stop"
+	case "length":
+		return "length"
+	case "content_filter":
+		return "content_filter"
+	case "function_call":
+		return "tool_calls"
+	default:
+		return reason
+	}
+}
+
+// mapModelEndpoint 将模型名称映射为千帆的接口路径
+func (a *ErnieAdapter) mapModelEndpoint(modelName string) string {
+	mapping := map[string]string{
+		"ernie-4.0":       "completions_pro",
+		"ernie-4.0-turbo": "ernie-4.0-turbo-8k",
+		"ernie-3.5":       "completions",
+		"ernie-bot":       "completions",
+		"ernie-turbo":     "eb-instant",
+		"ernie-bot-turbo": "eb-instant",
+		"ernie-speed":     "ernie_speed",
+		"ernie-lite":      "ernie-lite-8k",
+	}
+
+	if endpoint, ok := mapping[strings.ToLower(modelName)]; ok {
+		return endpoint
+	}
+	// 未知模型直接作为接口路径，便于使用自定义部署的服务
+	return modelName
 }
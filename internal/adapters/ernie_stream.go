@@ -0,0 +1,94 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// ernieStreamTranslator 将千帆 SSE 转换为 OpenAI chat.completion.chunk
+// 千帆每个事件的 result 为增量文本，is_end 为 true 表示结束
+type ernieStreamTranslator struct {
+	reader  *SSEReader
+	id      string
+	model   string
+	created int64
+	started bool
+}
+
+func newErnieStream(body io.ReadCloser, req *models.ChatCompletionRequest) io.ReadCloser {
+	t := &ernieStreamTranslator{
+		reader:  NewSSEReader(body),
+		model:   req.Model,
+		created: time.Now().Unix(),
+	}
+	return newChunkStream(req, t.next, body)
+}
+
+func (t *ernieStreamTranslator) next() ([]*models.ChatCompletionStreamResponse, error) {
+	sse, err := t.reader.Next()
+	if err != nil {
+		return nil, err
+	}
+	if len(sse.Data) == 0 {
+		return nil, nil
+	}
+
+	var resp ErnieResponse
+	if err := json.Unmarshal(sse.Data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode ernie stream event: %w", err)
+	}
+	if resp.ErrorCode != 0 {
+		return nil, fmt.Errorf("ernie stream error: code %d, message: %s", resp.ErrorCode, resp.ErrorMsg)
+	}
+	if t.id == "" {
+		t.id = resp.ID
+		if t.id == "" {
+			t.id = newResponseID()
+		}
+	}
+
+	chunks := make([]*models.ChatCompletionStreamResponse, 0, 2)
+	if !t.started {
+		t.started = true
+		chunks = append(chunks, t.chunk(models.ChatMessageDelta{Role: "assistant"}, ""))
+	}
+
+	finishReason := ""
+	if resp.IsEnd {
+		finishReason = mapErnieFinishReason(resp.FinishReason, resp.IsTruncated)
+	}
+	if resp.Result != "" || finishReason != "" {
+		chunks = append(chunks, t.chunk(models.ChatMessageDelta{Content: resp.Result}, finishReason))
+	}
+
+	if resp.Usage.TotalTokens > 0 {
+		chunks[len(chunks)-1].Usage = &models.Usage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		}
+	}
+
+	if resp.IsEnd {
+		return chunks, io.EOF
+	}
+	return chunks, nil
+}
+
+func (t *ernieStreamTranslator) chunk(delta models.ChatMessageDelta, finishReason string) *models.ChatCompletionStreamResponse {
+	return &models.ChatCompletionStreamResponse{
+		ID:      t.id,
+		Object:  "chat.completion.chunk",
+		Created: t.created,
+		Model:   t.model,
+		Choices: []models.ChatCompletionStreamChoice{{
+			Index:        0,
+			Delta:        delta,
+			FinishReason: finishReason,
+		}},
+	}
+}
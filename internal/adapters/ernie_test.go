@@ -0,0 +1,247 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestNewErnieAdapter_InvalidKey(t *testing.T) {
+	if _, err := NewErnieAdapter("only-api-key", ""); err == nil {
+		t.Error("expected error for api key without secret key")
+	}
+}
+
+func TestErnieAdapter_ChatCompletion(t *testing.T) {
+	var tokenRequests int32
+	var gotReq ErnieRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/2.0/token":
+			atomic.AddInt32(&tokenRequests, 1)
+			q := r.URL.Query()
+			if q.Get("grant_type") != "client_credentials" || q.Get("client_id") != "ak" || q.Get("client_secret") != "sk" {
+				t.Errorf("unexpected token query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"access_token":"token-1","expires_in":2592000}`)
+		case "/rpc/2.0/ai_custom/v1/wenxinworkshop/chat/completions_pro":
+			if r.URL.Query().Get("access_token") != "token-1" {
+				t.Errorf("unexpected access_token %q", r.URL.Query().Get("access_token"))
+			}
+			json.NewDecoder(r.Body).Decode(&gotReq)
+			fmt.Fprint(w, `{"id":"as-1","object":"chat.completion","created":1700000000,"result":"你好！","is_truncated":false,"need_clear_history":false,"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	adapter, err := NewErnieAdapter("ak:sk", server.URL)
+	if err != nil {
+		t.Fatalf("NewErnieAdapter() error = %v", err)
+	}
+
+	req := &models.ChatCompletionRequest{
+		Model: "ernie-4.0",
+		Messages: []models.ChatMessage{
+			{Role: "system", Content: "你是一个助手"},
+			{Role: "user", Content: "你好"},
+		},
+	}
+
+	// 并发请求只获取一次 access_token
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := adapter.ChatCompletion(context.Background(), req); err != nil {
+				t.Errorf("ChatCompletion() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&tokenRequests); n != 1 {
+		t.Errorf("expected access_token to be fetched once, got %d", n)
+	}
+
+	resp, err := adapter.ChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if gotReq.System != "你是一个助手" || len(gotReq.Messages) != 1 {
+		t.Errorf("expected system message in system field, got %+v", gotReq)
+	}
+	if resp.ID != "as-1" || resp.Choices[0].Message.Content != "你好！" || resp.Choices[0].FinishReason != "stop" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if resp.Usage.TotalTokens != 5 {
+		t.Errorf("expected total tokens 5, got %d", resp.Usage.TotalTokens)
+	}
+}
+
+func TestErnieAdapter_RefreshesExpiredToken(t *testing.T) {
+	var tokenRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/2.0/token" {
+			n := atomic.AddInt32(&tokenRequests, 1)
+			fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":2592000}`, n)
+			return
+		}
+		if r.URL.Query().Get("access_token") == "token-1" {
+			fmt.Fprint(w, `{"error_code":111,"error_msg":"Access token expired"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"as-2","result":"ok","usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewErnieAdapter("ak:sk", server.URL)
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "ernie-3.5",
+		Messages: []models.ChatMessage{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if resp.Choices[0].Message.Content != "ok" {
+		t.Errorf("expected content 'ok', got %v", resp.Choices[0].Message.Content)
+	}
+	if n := atomic.LoadInt32(&tokenRequests); n != 2 {
+		t.Errorf("expected access_token to be refreshed once, got %d fetches", n)
+	}
+}
+
+func TestErnieAdapter_SharesTokenAcrossAdapters(t *testing.T) {
+	var tokenRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/2.0/token" {
+			atomic.AddInt32(&tokenRequests, 1)
+			fmt.Fprint(w, `{"access_token":"token-1","expires_in":2592000}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"as-1","result":"ok","usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`)
+	}))
+	defer server.Close()
+
+	// 网关每个请求创建新的适配器，同一组凭据只获取一次 access_token
+	for i := 0; i < 2; i++ {
+		adapter, _ := NewErnieAdapter("shared-ak:shared-sk", server.URL)
+		_, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+			Model:    "ernie-3.5",
+			Messages: []models.ChatMessage{{Role: "user", Content: "hi"}},
+		})
+		if err != nil {
+			t.Fatalf("ChatCompletion() error = %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&tokenRequests); n != 1 {
+		t.Errorf("expected one token fetch across adapters, got %d", n)
+	}
+}
+
+func TestErnieAdapter_ChatCompletionStream(t *testing.T) {
+	events := []string{
+		`{"id":"as-3","object":"chat.completion","sentence_id":0,"is_end":false,"result":"你好","usage":{"prompt_tokens":2,"completion_tokens":0,"total_tokens":2}}`,
+		`{"id":"as-3","object":"chat.completion","sentence_id":1,"is_end":true,"result":"，世界","usage":{"prompt_tokens":2,"completion_tokens":4,"total_tokens":6}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/2.0/token" {
+			fmt.Fprint(w, `{"access_token":"token","expires_in":2592000}`)
+			return
+		}
+		if !strings.HasSuffix(r.URL.Path, "/chat/eb-instant") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, e := range events {
+			fmt.Fprintf(w, "data: %s\n\n", e)
+		}
+	}))
+	defer server.Close()
+
+	adapter, _ := NewErnieAdapter("ak:sk", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:         "ernie-bot-turbo",
+		Messages:      []models.ChatMessage{{Role: "user", Content: "你好"}},
+		StreamOptions: &models.StreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, done := collectStreamChunks(t, stream)
+	if !done {
+		t.Error("expected stream to end with data: [DONE]")
+	}
+	if len(chunks) != 4 {
+		t.Fatalf("expected 4 chunks, got %d", len(chunks))
+	}
+	if chunks[1].Choices[0].Delta.Content != "你好" || chunks[2].Choices[0].Delta.Content != "，世界" {
+		t.Errorf("unexpected deltas: %+v, %+v", chunks[1].Choices[0].Delta, chunks[2].Choices[0].Delta)
+	}
+	if chunks[2].Choices[0].FinishReason != "stop" {
+		t.Errorf("expected finish_reason 'stop', got %q", chunks[2].Choices[0].FinishReason)
+	}
+	if chunks[3].Usage == nil || chunks[3].Usage.TotalTokens != 6 {
+		t.Errorf("expected usage chunk with 6 total tokens, got %+v", chunks[3].Usage)
+	}
+}
+
+func TestErnieAdapter_ChatCompletionStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/2.0/token" {
+			fmt.Fprint(w, `{"access_token":"token","expires_in":2592000}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"error_code":336003,"error_msg":"the first message must be from user"}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewErnieAdapter("ak:sk", server.URL)
+	_, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "ernie-3.5",
+		Messages: []models.ChatMessage{{Role: "user", Content: "hi"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "336003") {
+		t.Errorf("expected ernie error code in error, got %v", err)
+	}
+}
+
+// ernieToolsRequest 携带工具定义的请求，千帆原生接口不支持工具调用
+func ernieToolsRequest() *models.ChatCompletionRequest {
+	return &models.ChatCompletionRequest{
+		Model:    "ernie-4.0",
+		Messages: []models.ChatMessage{{Role: "user", Content: "北京天气怎么样？"}},
+		Tools:    []models.Tool{{Type: "function", Function: models.FunctionDefinition{Name: "get_weather"}}},
+	}
+}
+
+func TestErnieAdapter_ChatCompletionRejectsTools(t *testing.T) {
+	adapter, _ := NewErnieAdapter("ak:sk", "http://127.0.0.1:0")
+	_, err := adapter.ChatCompletion(context.Background(), ernieToolsRequest())
+	if err == nil || !strings.Contains(err.Error(), "tool use not supported for provider ernie") {
+		t.Errorf("expected tool use error, got %v", err)
+	}
+}
+
+func TestErnieAdapter_ChatCompletionStreamRejectsTools(t *testing.T) {
+	adapter, _ := NewErnieAdapter("ak:sk", "http://127.0.0.1:0")
+	req := ernieToolsRequest()
+	req.Tools = nil
+	req.Functions = []models.FunctionDefinition{{Name: "get_weather"}}
+	_, err := adapter.ChatCompletionStream(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), "tool use not supported for provider ernie") {
+		t.Errorf("expected tool use error, got %v", err)
+	}
+}
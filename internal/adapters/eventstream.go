@@ -0,0 +1,119 @@
+package adapters
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// eventStreamMessage AWS application/vnd.amazon.eventstream 的一条消息
+type eventStreamMessage struct {
+	Headers map[string]string // 只保留字符串类型的头，如 :event-type、:message-type
+	Payload []byte
+}
+
+// eventStreamReader 解析 AWS 二进制事件流
+// 每条消息的格式为：总长度(4) | 头长度(4) | prelude CRC(4) | 头 | 载荷 | 消息 CRC(4)，整数均为大端序
+type eventStreamReader struct {
+	reader *bufio.Reader
+}
+
+func newEventStreamReader(r io.Reader) *eventStreamReader {
+	return &eventStreamReader{reader: bufio.NewReader(r)}
+}
+
+// 单条消息的长度上限，防止异常数据导致分配过大的内存
+const eventStreamMaxMessageLen = 16 << 20
+
+// Next 读取下一条消息，流结束时返回 io.EOF
+func (r *eventStreamReader) Next() (*eventStreamMessage, error) {
+	prelude := make([]byte, 12)
+	if _, err := io.ReadFull(r.reader, prelude); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("eventstream: truncated prelude")
+		}
+		return nil, err
+	}
+	totalLen := binary.BigEndian.Uint32(prelude[0:4])
+	headersLen := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return nil, fmt.Errorf("eventstream: prelude checksum mismatch")
+	}
+	if totalLen < 16 || totalLen > eventStreamMaxMessageLen || headersLen > totalLen-16 {
+		return nil, fmt.Errorf("eventstream: invalid message length %d", totalLen)
+	}
+
+	message := make([]byte, totalLen)
+	copy(message, prelude)
+	if _, err := io.ReadFull(r.reader, message[12:]); err != nil {
+		return nil, fmt.Errorf("eventstream: truncated message: %w", err)
+	}
+	crcOffset := totalLen - 4
+	if crc32.ChecksumIEEE(message[:crcOffset]) != binary.BigEndian.Uint32(message[crcOffset:]) {
+		return nil, fmt.Errorf("eventstream: message checksum mismatch")
+	}
+
+	headers, err := parseEventStreamHeaders(message[12 : 12+headersLen])
+	if err != nil {
+		return nil, err
+	}
+	return &eventStreamMessage{
+		Headers: headers,
+		Payload: message[12+headersLen : crcOffset],
+	}, nil
+}
+
+// parseEventStreamHeaders 解析消息头：名称长度(1) | 名称 | 值类型(1) | 值
+func parseEventStreamHeaders(data []byte) (map[string]string, error) {
+	headers := make(map[string]string)
+	for len(data) > 0 {
+		nameLen := int(data[0])
+		if len(data) < 1+nameLen+1 {
+			return nil, fmt.Errorf("eventstream: truncated header")
+		}
+		name := string(data[1 : 1+nameLen])
+		valueType := data[1+nameLen]
+		data = data[2+nameLen:]
+
+		// 各类型值的长度，-1 表示带 2 字节长度前缀的变长值
+		var size int
+		switch valueType {
+		case 0, 1: // bool true / false
+			size = 0
+		case 2: // byte
+			size = 1
+		case 3: // short
+			size = 2
+		case 4: // int
+			size = 4
+		case 5, 8: // long、timestamp
+			size = 8
+		case 9: // uuid
+			size = 16
+		case 6, 7: // bytes、string
+			size = -1
+		default:
+			return nil, fmt.Errorf("eventstream: unknown header value type %d", valueType)
+		}
+
+		if size < 0 {
+			if len(data) < 2 {
+				return nil, fmt.Errorf("eventstream: truncated header")
+			}
+			size = int(binary.BigEndian.Uint16(data[:2]))
+			data = data[2:]
+			if len(data) < size {
+				return nil, fmt.Errorf("eventstream: truncated header")
+			}
+			if valueType == 7 {
+				headers[name] = string(data[:size])
+			}
+		} else if len(data) < size {
+			return nil, fmt.Errorf("eventstream: truncated header")
+		}
+		data = data[size:]
+	}
+	return headers, nil
+}
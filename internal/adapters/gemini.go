@@ -3,10 +3,12 @@ package adapters
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gotoailab/llmhub/internal/models"
@@ -21,7 +23,7 @@ type GeminiAdapter struct {
 // NewGeminiAdapter 创建 Google Gemini 适配器
 func NewGeminiAdapter(apiKey, baseURL string) (Adapter, error) {
 	if baseURL == "" {
-		baseURL = "https://generativelanguage.googleapis.com/v1"
+		baseURL = "https://generativelanguage.googleapis.com/v1beta"
 	}
 
 	return &GeminiAdapter{
@@ -33,13 +35,17 @@ func NewGeminiAdapter(apiKey, baseURL string) (Adapter, error) {
 	}, nil
 }
 
+func (a *GeminiAdapter) supportsDocuments() {}
+
 func (a *GeminiAdapter) GetProvider() Provider {
 	return Provider("gemini")
 }
 
 func (a *GeminiAdapter) ChatCompletion(ctx context.Context, req *models.ChatCompletionRequest) (*models.ChatCompletionResponse, error) {
-	// Gemini 使用 OpenAI 兼容的 API（通过 Vertex AI 或直接 API）
-	geminiReq := a.convertToOpenAIFormat(req)
+	geminiReq, err := a.convertToGeminiRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
 
 	reqBody, err := json.Marshal(geminiReq)
 	if err != nil {
@@ -48,9 +54,6 @@ func (a *GeminiAdapter) ChatCompletion(ctx context.Context, req *models.ChatComp
 
 	// Gemini API 路径
 	url := fmt.Sprintf("%s/models/%s:generateContent", a.baseURL, req.Model)
-	if a.apiKey != "" {
-		url += "?key=" + a.apiKey
-	}
 
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqBody))
 	if err != nil {
@@ -58,6 +61,7 @@ func (a *GeminiAdapter) ChatCompletion(ctx context.Context, req *models.ChatComp
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-goog-api-key", a.apiKey)
 
 	resp, err := a.client.Do(httpReq)
 	if err != nil {
@@ -70,17 +74,20 @@ func (a *GeminiAdapter) ChatCompletion(ctx context.Context, req *models.ChatComp
 		return nil, fmt.Errorf("gemini api error: status %d, body: %s", resp.StatusCode, string(body))
 	}
 
-	var openaiResp models.ChatCompletionResponse
-	if err := json.NewDecoder(resp.Body).Decode(&openaiResp); err != nil {
+	var geminiResp GeminiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&geminiResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return &openaiResp, nil
+	// 转换回 OpenAI 格式
+	return a.convertFromGeminiResponse(&geminiResp, req.Model), nil
 }
 
 func (a *GeminiAdapter) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
-	geminiReq := a.convertToOpenAIFormat(req)
-	geminiReq["stream"] = true
+	geminiReq, err := a.convertToGeminiRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
 
 	reqBody, err := json.Marshal(geminiReq)
 	if err != nil {
@@ -88,9 +95,6 @@ func (a *GeminiAdapter) ChatCompletionStream(ctx context.Context, req *models.Ch
 	}
 
 	url := fmt.Sprintf("%s/models/%s:streamGenerateContent", a.baseURL, req.Model)
-	if a.apiKey != "" {
-		url += "?key=" + a.apiKey
-	}
 
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqBody))
 	if err != nil {
@@ -98,6 +102,7 @@ func (a *GeminiAdapter) ChatCompletionStream(ctx context.Context, req *models.Ch
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-goog-api-key", a.apiKey)
 
 	resp, err := a.client.Do(httpReq)
 	if err != nil {
@@ -109,45 +114,490 @@ func (a *GeminiAdapter) ChatCompletionStream(ctx context.Context, req *models.Ch
 		return nil, fmt.Errorf("gemini stream error: status %d", resp.StatusCode)
 	}
 
-	return resp.Body, nil
+	// streamGenerateContent 返回 JSON 数组，逐个元素转换为 OpenAI 格式的 SSE
+	return newGeminiStream(resp.Body, req), nil
+}
+
+// Gemini 请求结构
+type GeminiRequest struct {
+	Contents          []GeminiContent         `json:"contents"`
+	SystemInstruction *GeminiContent          `json:"systemInstruction,omitempty"`
+	GenerationConfig  *GeminiGenerationConfig `json:"generationConfig,omitempty"`
+	Tools             []GeminiTool            `json:"tools,omitempty"`
+	ToolConfig        *GeminiToolConfig       `json:"toolConfig,omitempty"`
+}
+
+type GeminiTool struct {
+	FunctionDeclarations []GeminiFunctionDeclaration `json:"functionDeclarations"`
+}
+
+type GeminiFunctionDeclaration struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Parameters  interface{} `json:"parameters,omitempty"`
+}
+
+type GeminiToolConfig struct {
+	FunctionCallingConfig GeminiFunctionCallingConfig `json:"functionCallingConfig"`
 }
 
-func (a *GeminiAdapter) convertToOpenAIFormat(req *models.ChatCompletionRequest) map[string]interface{} {
-	messages := make([]map[string]interface{}, 0, len(req.Messages))
+type GeminiFunctionCallingConfig struct {
+	Mode                 string   `json:"mode"`
+	AllowedFunctionNames []string `json:"allowedFunctionNames,omitempty"`
+}
+
+type GeminiGenerationConfig struct {
+	Temperature      *float64 `json:"temperature,omitempty"`
+	TopP             *float64 `json:"topP,omitempty"`
+	MaxOutputTokens  *int     `json:"maxOutputTokens,omitempty"`
+	StopSequences    []string `json:"stopSequences,omitempty"`
+	PresencePenalty  *float64 `json:"presencePenalty,omitempty"`
+	FrequencyPenalty *float64 `json:"frequencyPenalty,omitempty"`
+	Seed             *int     `json:"seed,omitempty"`
+	ResponseMimeType string   `json:"responseMimeType,omitempty"`
+
+	ThinkingConfig *GeminiThinkingConfig `json:"thinkingConfig,omitempty"`
+}
+
+// GeminiThinkingConfig 思考预算配置，thinkingBudget 为 0 时关闭思考
+type GeminiThinkingConfig struct {
+	ThinkingBudget *int `json:"thinkingBudget,omitempty"`
+}
+
+func (a *GeminiAdapter) convertToGeminiRequest(req *models.ChatCompletionRequest) (*GeminiRequest, error) {
+	contents := make([]GeminiContent, 0, len(req.Messages))
+	var systemParts []GeminiPart
+
+	// 记录 tool_call_id 对应的函数名，functionResponse 需要函数名而不是 ID
+	toolNames := make(map[string]string)
+
 	for _, msg := range req.Messages {
-		msgMap := map[string]interface{}{
-			"role": msg.Role,
+		text := contentToText(msg.Content)
+
+		// system 消息合并为 systemInstruction
+		if nativeRole(msg.Role) == "system" {
+			if text != "" {
+				systemParts = append(systemParts, GeminiPart{Text: text})
+			}
+			continue
+		}
+
+		role := "user"
+		if msg.Role == "assistant" {
+			role = "model"
+		}
+
+		parts := make([]GeminiPart, 0, 1)
+		switch {
+		case msg.Role == "tool" || msg.Role == "function":
+			// 工具结果转换为 functionResponse
+			name := msg.Name
+			if name == "" {
+				name = toolNames[msg.ToolCallID]
+			}
+			if name == "" {
+				return nil, fmt.Errorf("cannot resolve function name for tool_call_id %q", msg.ToolCallID)
+			}
+			parts = append(parts, GeminiPart{
+				FunctionResponse: &GeminiFunctionResponse{
+					Name:     name,
+					Response: toGeminiFunctionResponse(text),
+				},
+			})
+		default:
+			userParts, err := toGeminiContentParts(msg.Content)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, userParts...)
+		}
+
+		// assistant 的工具调用转换为 functionCall
+		for _, tc := range msg.ToolCalls {
+			toolNames[tc.ID] = tc.Function.Name
+			parts = append(parts, GeminiPart{
+				FunctionCall: &GeminiFunctionCall{
+					Name: tc.Function.Name,
+					Args: parseToolArguments(tc.Function.Arguments),
+				},
+			})
+		}
+		if msg.FunctionCall != nil {
+			parts = append(parts, GeminiPart{
+				FunctionCall: &GeminiFunctionCall{
+					Name: msg.FunctionCall.Name,
+					Args: parseToolArguments(msg.FunctionCall.Arguments),
+				},
+			})
+		}
+
+		if len(parts) == 0 {
+			continue
+		}
+
+		// Gemini 要求 user/model 交替出现，相邻的同角色消息合并为一轮
+		if n := len(contents); n > 0 && contents[n-1].Role == role {
+			contents[n-1].Parts = append(contents[n-1].Parts, parts...)
+			continue
+		}
+		contents = append(contents, GeminiContent{Role: role, Parts: parts})
+	}
+
+	if len(contents) == 0 {
+		return nil, fmt.Errorf("at least one non-system message is required")
+	}
+
+	geminiReq := &GeminiRequest{
+		Contents: contents,
+		GenerationConfig: &GeminiGenerationConfig{
+			Temperature:      req.Temperature,
+			TopP:             req.TopP,
+			MaxOutputTokens:  maxOutputTokens(req),
+			StopSequences:    req.Stop,
+			PresencePenalty:  req.PresencePenalty,
+			FrequencyPenalty: req.FrequencyPenalty,
+			Seed:             req.Seed,
+		},
+	}
+	if len(systemParts) > 0 {
+		geminiReq.SystemInstruction = &GeminiContent{Parts: systemParts}
+	}
+	if req.ResponseFormat != nil && req.ResponseFormat.Type == "json_object" {
+		geminiReq.GenerationConfig.ResponseMimeType = "application/json"
+	}
+	if req.ReasoningEffort != "" {
+		budget, err := reasoningBudget(req.ReasoningEffort)
+		if err != nil {
+			return nil, err
+		}
+		// Gemini 2.5 Pro 等模型不能关闭思考，minimal 使用最小预算
+		if minBudget := geminiMinThinkingBudget(req.Model); budget < minBudget {
+			budget = minBudget
+		}
+		geminiReq.GenerationConfig.ThinkingConfig = &GeminiThinkingConfig{ThinkingBudget: &budget}
+	}
+
+	// 转换工具定义
+	declarations := make([]GeminiFunctionDeclaration, 0, len(req.Tools)+len(req.Functions))
+	for _, tool := range req.Tools {
+		declarations = append(declarations, toGeminiFunctionDeclaration(tool.Function))
+	}
+	// 兼容旧的 Functions 格式
+	for _, fn := range req.Functions {
+		declarations = append(declarations, toGeminiFunctionDeclaration(fn))
+	}
+	if len(declarations) > 0 {
+		geminiReq.Tools = []GeminiTool{{FunctionDeclarations: declarations}}
+
+		toolChoice := req.ToolChoice
+		if toolChoice == nil {
+			toolChoice = req.FunctionCall
+		}
+		toolConfig, err := toGeminiToolConfig(toolChoice)
+		if err != nil {
+			return nil, err
+		}
+		geminiReq.ToolConfig = toolConfig
+	}
+
+	return geminiReq, nil
+}
+
+// geminiMinThinkingBudget 返回模型允许的最小思考预算，可以关闭思考的模型返回 0
+func geminiMinThinkingBudget(model string) int {
+	if strings.Contains(model, "gemini-2.5-pro") {
+		return 128
+	}
+	return 0
+}
+
+func toGeminiFunctionDeclaration(fn models.FunctionDefinition) GeminiFunctionDeclaration {
+	return GeminiFunctionDeclaration{
+		Name:        fn.Name,
+		Description: fn.Description,
+		Parameters:  cleanGeminiSchema(fn.Parameters),
+	}
+}
+
+// toGeminiToolConfig 将 OpenAI 的 tool_choice 转换为 functionCallingConfig
+// 支持 "auto"、"none"、"required" 以及 {"type":"function","function":{"name":...}}
+func toGeminiToolConfig(toolChoice interface{}) (*GeminiToolConfig, error) {
+	config := func(mode string, names ...string) *GeminiToolConfig {
+		return &GeminiToolConfig{
+			FunctionCallingConfig: GeminiFunctionCallingConfig{
+				Mode:                 mode,
+				AllowedFunctionNames: names,
+			},
+		}
+	}
+
+	switch v := toolChoice.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		switch v {
+		case "auto":
+			return config("AUTO"), nil
+		case "none":
+			return config("NONE"), nil
+		case "required", "any":
+			return config("ANY"), nil
+		}
+		return nil, fmt.Errorf("unsupported tool_choice %q", v)
+	case map[string]interface{}:
+		// 旧格式 function_call: {"name": "..."}
+		if name, ok := v["name"].(string); ok && name != "" {
+			return config("ANY", name), nil
+		}
+		if fn, ok := v["function"].(map[string]interface{}); ok {
+			if name, ok := fn["name"].(string); ok && name != "" {
+				return config("ANY", name), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unsupported tool_choice %v", toolChoice)
+}
+
+// cleanGeminiSchema 移除 Gemini 不接受的 JSON Schema 字段
+func cleanGeminiSchema(schema interface{}) interface{} {
+	switch v := schema.(type) {
+	case map[string]interface{}:
+		cleaned := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if key == "$schema" || key == "additionalProperties" {
+				continue
+			}
+			cleaned[key] = cleanGeminiSchema(value)
+		}
+		return cleaned
+	case []interface{}:
+		cleaned := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			cleaned = append(cleaned, cleanGeminiSchema(item))
 		}
+		return cleaned
+	default:
+		return schema
+	}
+}
 
-		switch v := msg.Content.(type) {
-		case string:
-			msgMap["content"] = v
-		case []interface{}:
-			msgMap["content"] = v
+// toGeminiContentParts 将消息内容转换为 Gemini parts
+// data URI 图片和文档作为 inlineData，其他图片地址作为 fileData
+func toGeminiContentParts(content interface{}) ([]GeminiPart, error) {
+	parts, ok := contentParts(content)
+	if !ok {
+		return []GeminiPart{{Text: contentToText(content)}}, nil
+	}
+	result := make([]GeminiPart, 0, len(parts))
+	for _, part := range parts {
+		switch part.Type {
+		case "text":
+			if part.Text != "" {
+				result = append(result, GeminiPart{Text: part.Text})
+			}
+		case "image_url":
+			if part.ImageURL == nil {
+				return nil, fmt.Errorf("image_url content part requires a url")
+			}
+			if mimeType, data, ok := parseDataURI(part.ImageURL.URL); ok {
+				result = append(result, GeminiPart{InlineData: &GeminiBlob{MimeType: mimeType, Data: data}})
+				continue
+			}
+			result = append(result, GeminiPart{FileData: &GeminiFileData{
+				MimeType: mimeTypeFromURL(part.ImageURL.URL, "image/jpeg"),
+				FileURI:  part.ImageURL.URL,
+			}})
+		case "document":
+			if part.Document == nil {
+				return nil, fmt.Errorf("document content part requires a document")
+			}
+			blob, err := toGeminiDocumentBlob(part.Document)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, GeminiPart{InlineData: blob})
 		default:
-			msgMap["content"] = fmt.Sprintf("%v", v)
+			return nil, fmt.Errorf("unsupported content part type %q", part.Type)
 		}
+	}
+	return result, nil
+}
 
-		messages = append(messages, msgMap)
+// toGeminiDocumentBlob 将文档转换为 inlineData，纯文本需先进行 base64 编码
+func toGeminiDocumentBlob(doc *models.Document) (*GeminiBlob, error) {
+	switch doc.MediaType {
+	case "application/pdf":
+		return &GeminiBlob{MimeType: doc.MediaType, Data: doc.Data}, nil
+	case "text/plain":
+		return &GeminiBlob{MimeType: doc.MediaType, Data: base64.StdEncoding.EncodeToString([]byte(doc.Data))}, nil
 	}
+	return nil, fmt.Errorf("unsupported document media type %q", doc.MediaType)
+}
 
-	result := map[string]interface{}{
-		"model":    req.Model,
-		"messages": messages,
+// parseToolArguments 将 JSON 字符串形式的参数解析为对象
+func parseToolArguments(arguments string) map[string]interface{} {
+	args := make(map[string]interface{})
+	if arguments != "" {
+		json.Unmarshal([]byte(arguments), &args)
 	}
+	return args
+}
+
+// toGeminiFunctionResponse 工具结果为 JSON 对象时直接使用，否则包装为 {"content": ...}
+func toGeminiFunctionResponse(content string) map[string]interface{} {
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &response); err == nil && response != nil {
+		return response
+	}
+	return map[string]interface{}{"content": content}
+}
+
+// toolCallsFromGeminiParts 将 functionCall 转换为 OpenAI 的 tool_calls，Gemini 不返回调用 ID，这里自动生成
+func toolCallsFromGeminiParts(parts []GeminiPart) []models.ToolCall {
+	var toolCalls []models.ToolCall
+	for _, part := range parts {
+		if part.FunctionCall == nil {
+			continue
+		}
+		args, err := json.Marshal(part.FunctionCall.Args)
+		if err != nil || part.FunctionCall.Args == nil {
+			args = []byte("{}")
+		}
+		toolCalls = append(toolCalls, models.ToolCall{
+			ID:   newToolCallID(),
+			Type: "function",
+			Function: models.FunctionCall{
+				Name:      part.FunctionCall.Name,
+				Arguments: string(args),
+			},
+		})
+	}
+	return toolCalls
+}
 
-	if req.Temperature != nil {
-		result["temperature"] = *req.Temperature
+func (a *GeminiAdapter) convertFromGeminiResponse(geminiResp *GeminiResponse, modelName string) *models.ChatCompletionResponse {
+	choices := make([]models.ChatCompletionChoice, 0, len(geminiResp.Candidates))
+	for _, candidate := range geminiResp.Candidates {
+		var text strings.Builder
+		for _, part := range candidate.Content.Parts {
+			text.WriteString(part.Text)
+		}
+
+		message := models.ChatMessage{
+			Role:    "assistant",
+			Content: text.String(),
+		}
+		finishReason := mapGeminiFinishReason(candidate.FinishReason)
+		if toolCalls := toolCallsFromGeminiParts(candidate.Content.Parts); len(toolCalls) > 0 {
+			message.ToolCalls = toolCalls
+			if finishReason == "stop" {
+				finishReason = "tool_calls"
+			}
+		}
+
+		choices = append(choices, models.ChatCompletionChoice{
+			Index:        candidate.Index,
+			Message:      message,
+			FinishReason: finishReason,
+		})
 	}
-	if req.TopP != nil {
-		result["top_p"] = *req.TopP
+
+	id := geminiResp.ResponseID
+	if id == "" {
+		id = newResponseID()
 	}
-	if req.MaxTokens != nil {
-		result["max_tokens"] = *req.MaxTokens
+
+	resp := &models.ChatCompletionResponse{
+		ID:      id,
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   modelName,
+		Choices: choices,
 	}
-	if req.Stop != nil && len(req.Stop) > 0 {
-		result["stop"] = req.Stop
+	if geminiResp.UsageMetadata != nil {
+		resp.Usage = models.Usage{
+			PromptTokens:     geminiResp.UsageMetadata.PromptTokenCount,
+			CompletionTokens: geminiResp.UsageMetadata.CandidatesTokenCount,
+			TotalTokens:      geminiResp.UsageMetadata.TotalTokenCount,
+		}
 	}
+	return resp
+}
+
+// Gemini 响应结构
+type GeminiResponse struct {
+	Candidates    []GeminiCandidate    `json:"candidates"`
+	UsageMetadata *GeminiUsageMetadata `json:"usageMetadata,omitempty"`
+	ModelVersion  string               `json:"modelVersion,omitempty"`
+	ResponseID    string               `json:"responseId,omitempty"`
+	Error         *GeminiError         `json:"error,omitempty"`
+}
+
+type GeminiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
+
+type GeminiCandidate struct {
+	Content      GeminiContent `json:"content"`
+	FinishReason string        `json:"finishReason,omitempty"`
+	Index        int           `json:"index"`
+}
 
-	return result
+type GeminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []GeminiPart `json:"parts"`
+}
+
+type GeminiPart struct {
+	Text             string                  `json:"text,omitempty"`
+	InlineData       *GeminiBlob             `json:"inlineData,omitempty"`
+	FileData         *GeminiFileData         `json:"fileData,omitempty"`
+	FunctionCall     *GeminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *GeminiFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+// GeminiBlob 内嵌的 base64 数据，如图片
+type GeminiBlob struct {
+	MimeType string `json:"mimeType"`
+	Data     string `json:"data"`
+}
+
+// GeminiFileData 通过 URI 引用的文件，如 gs:// 或 https:// 地址
+type GeminiFileData struct {
+	MimeType string `json:"mimeType,omitempty"`
+	FileURI  string `json:"fileUri"`
+}
+
+type GeminiFunctionCall struct {
+	Name string                 `json:"name"`
+	Args map[string]interface{} `json:"args"`
+}
+
+type GeminiFunctionResponse struct {
+	Name     string                 `json:"name"`
+	Response map[string]interface{} `json:"response"`
+}
+
+type GeminiUsageMetadata struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+	TotalTokenCount      int `json:"totalTokenCount"`
+}
+
+// mapGeminiFinishReason 将 Gemini 的 finishReason 映射为 OpenAI 的 finish_reason
+func mapGeminiFinishReason(reason string) string {
+	switch reason {
+	case "":
+		return ""
+	case "STOP":
+		return "stop"
+	case "MAX_TOKENS":
+		return "length"
+	case "SAFETY", "RECITATION", "BLOCKLIST", "PROHIBITED_CONTENT", "SPII":
+		return "content_filter"
+	default:
+		return strings.ToLower(reason)
+	}
 }
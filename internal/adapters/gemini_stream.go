@@ -0,0 +1,128 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// geminiStreamTranslator 将 streamGenerateContent 返回的 JSON 数组转换为 OpenAI chat.completion.chunk
+type geminiStreamTranslator struct {
+	decoder *json.Decoder
+	id      string
+	model   string
+	created int64
+	started bool
+	opened  bool
+
+	// 每个候选已发送的工具调用数量，用于生成 tool_calls 的 index
+	toolCounts map[int]int
+}
+
+func newGeminiStream(body io.ReadCloser, req *models.ChatCompletionRequest) io.ReadCloser {
+	t := &geminiStreamTranslator{
+		decoder:    json.NewDecoder(body),
+		id:         newResponseID(),
+		model:      req.Model,
+		created:    time.Now().Unix(),
+		toolCounts: make(map[int]int),
+	}
+	return newChunkStream(req, t.next, body)
+}
+
+func (t *geminiStreamTranslator) next() ([]*models.ChatCompletionStreamResponse, error) {
+	// 读取数组起始符
+	if !t.opened {
+		tok, err := t.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+			return nil, fmt.Errorf("gemini stream error: unexpected token %v", tok)
+		}
+		t.opened = true
+	}
+
+	if !t.decoder.More() {
+		return nil, io.EOF
+	}
+
+	var resp GeminiResponse
+	if err := t.decoder.Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode gemini stream chunk: %w", err)
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("gemini stream error: %s: %s", resp.Error.Status, resp.Error.Message)
+	}
+
+	chunks := make([]*models.ChatCompletionStreamResponse, 0, 1)
+	if !t.started {
+		t.started = true
+		chunks = append(chunks, t.chunk(0, models.ChatMessageDelta{Role: "assistant"}, ""))
+	}
+
+	for _, candidate := range resp.Candidates {
+		var text strings.Builder
+		for _, part := range candidate.Content.Parts {
+			text.WriteString(part.Text)
+		}
+
+		// Gemini 的 functionCall 总是完整返回，一次性发送名称和参数
+		toolCalls := toolCallsFromGeminiParts(candidate.Content.Parts)
+		for i := range toolCalls {
+			index := t.toolCounts[candidate.Index]
+			t.toolCounts[candidate.Index]++
+			toolCalls[i].Index = &index
+		}
+
+		finishReason := mapGeminiFinishReason(candidate.FinishReason)
+		if finishReason == "stop" && t.toolCounts[candidate.Index] > 0 {
+			finishReason = "tool_calls"
+		}
+		if text.Len() == 0 && len(toolCalls) == 0 && finishReason == "" {
+			continue
+		}
+		chunks = append(chunks, t.chunk(candidate.Index, models.ChatMessageDelta{
+			Content:   text.String(),
+			ToolCalls: toolCalls,
+		}, finishReason))
+	}
+
+	// usageMetadata 为累计值，附在最后一个块上交给 chunkStream 统一处理
+	// 没有候选增量时单独下发不含 choices 的用量块
+	if resp.UsageMetadata != nil {
+		if len(chunks) == 0 {
+			chunks = append(chunks, &models.ChatCompletionStreamResponse{
+				ID:      t.id,
+				Object:  "chat.completion.chunk",
+				Created: t.created,
+				Model:   t.model,
+			})
+		}
+		chunks[len(chunks)-1].Usage = &models.Usage{
+			PromptTokens:     resp.UsageMetadata.PromptTokenCount,
+			CompletionTokens: resp.UsageMetadata.CandidatesTokenCount,
+			TotalTokens:      resp.UsageMetadata.TotalTokenCount,
+		}
+	}
+
+	return chunks, nil
+}
+
+func (t *geminiStreamTranslator) chunk(index int, delta models.ChatMessageDelta, finishReason string) *models.ChatCompletionStreamResponse {
+	return &models.ChatCompletionStreamResponse{
+		ID:      t.id,
+		Object:  "chat.completion.chunk",
+		Created: t.created,
+		Model:   t.model,
+		Choices: []models.ChatCompletionStreamChoice{{
+			Index:        index,
+			Delta:        delta,
+			FinishReason: finishReason,
+		}},
+	}
+}
@@ -0,0 +1,131 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestGeminiAdapter_ChatCompletionStream(t *testing.T) {
+	body := `[{
+  "candidates": [{"content": {"parts": [{"text": "Hello"}], "role": "model"}, "index": 0}]
+}
+,
+{
+  "candidates": [{"content": {"parts": [{"text": ", world"}], "role": "model"}, "index": 0}]
+}
+,
+{
+  "candidates": [{"content": {"parts": [{"text": "!"}], "role": "model"}, "finishReason": "STOP", "index": 0}],
+  "usageMetadata": {"promptTokenCount": 3, "candidatesTokenCount": 4, "totalTokenCount": 7}
+}
+]`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/models/gemini-1.5-flash:streamGenerateContent") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewGeminiAdapter("test-key", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "gemini-1.5-flash",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, done := collectStreamChunks(t, stream)
+	if !done {
+		t.Error("expected stream to end with data: [DONE]")
+	}
+	if len(chunks) != 4 {
+		t.Fatalf("expected 4 chunks, got %d", len(chunks))
+	}
+	if chunks[0].Choices[0].Delta.Role != "assistant" {
+		t.Errorf("expected role 'assistant', got %q", chunks[0].Choices[0].Delta.Role)
+	}
+
+	var content strings.Builder
+	for _, chunk := range chunks {
+		if chunk.ID == "" || chunk.ID != chunks[0].ID {
+			t.Errorf("expected a stable chunk id, got %q", chunk.ID)
+		}
+		content.WriteString(chunk.Choices[0].Delta.Content)
+	}
+	if content.String() != "Hello, world!" {
+		t.Errorf("expected content 'Hello, world!', got %q", content.String())
+	}
+	if chunks[3].Choices[0].FinishReason != "stop" {
+		t.Errorf("expected finish_reason 'stop', got %q", chunks[3].Choices[0].FinishReason)
+	}
+}
+
+func TestGeminiAdapter_ChatCompletionStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"error": {"code": 429, "message": "Resource has been exhausted", "status": "RESOURCE_EXHAUSTED"}}]`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewGeminiAdapter("test-key", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "gemini-1.5-flash",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	_, err = NewSSEReader(stream).Next()
+	if err == nil || !strings.Contains(err.Error(), "RESOURCE_EXHAUSTED") {
+		t.Errorf("expected upstream error, got %v", err)
+	}
+}
+
+func TestGeminiAdapter_ChatCompletionStreamToolCalls(t *testing.T) {
+	body := `[{
+  "candidates": [{"content": {"parts": [{"functionCall": {"name": "get_weather", "args": {"location": "Paris"}}}], "role": "model"}, "finishReason": "STOP", "index": 0}]
+}]`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewGeminiAdapter("test-key", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "gemini-1.5-flash",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Weather in Paris?"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, _ := collectStreamChunks(t, stream)
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	choice := chunks[1].Choices[0]
+	if choice.FinishReason != "tool_calls" {
+		t.Errorf("expected finish_reason 'tool_calls', got %q", choice.FinishReason)
+	}
+	if len(choice.Delta.ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call delta, got %d", len(choice.Delta.ToolCalls))
+	}
+	tc := choice.Delta.ToolCalls[0]
+	if tc.Index == nil || *tc.Index != 0 || tc.ID == "" || tc.Function.Name != "get_weather" || tc.Function.Arguments != `{"location":"Paris"}` {
+		t.Errorf("unexpected tool call delta: %+v", tc)
+	}
+}
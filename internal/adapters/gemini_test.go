@@ -0,0 +1,325 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestGeminiAdapter_ChatCompletion(t *testing.T) {
+	var gotReq GeminiRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/gemini-1.5-pro:generateContent" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("key") != "" {
+			t.Error("api key must not be sent in the query string")
+		}
+		if r.Header.Get("x-goog-api-key") != "test-key" {
+			t.Errorf("expected x-goog-api-key header, got %q", r.Header.Get("x-goog-api-key"))
+		}
+		json.NewDecoder(r.Body).Decode(&gotReq)
+
+		fmt.Fprint(w, `{
+  "candidates": [{
+    "content": {"parts": [{"text": "Paris is "}, {"text": "the capital."}], "role": "model"},
+    "finishReason": "MAX_TOKENS",
+    "index": 0
+  }],
+  "usageMetadata": {"promptTokenCount": 12, "candidatesTokenCount": 5, "totalTokenCount": 17},
+  "modelVersion": "gemini-1.5-pro-002"
+}`)
+	}))
+	defer server.Close()
+
+	temperature, topP, maxTokens := 0.3, 0.9, 64
+	adapter, _ := NewGeminiAdapter("test-key", server.URL)
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "gemini-1.5-pro",
+		Messages: []models.ChatMessage{
+			{Role: "system", Content: "Be brief."},
+			{Role: "system", Content: "Answer in English."},
+			{Role: "user", Content: "Hi"},
+			{Role: "assistant", Content: "Hello!"},
+			{Role: "user", Content: "What is the capital of France?"},
+		},
+		Temperature: &temperature,
+		TopP:        &topP,
+		MaxTokens:   &maxTokens,
+		Stop:        []string{"\n\n"},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	// 请求转换
+	if gotReq.SystemInstruction == nil || len(gotReq.SystemInstruction.Parts) != 2 {
+		t.Errorf("expected both system messages in systemInstruction, got %+v", gotReq.SystemInstruction)
+	}
+	if len(gotReq.Contents) != 3 {
+		t.Fatalf("expected 3 contents, got %d", len(gotReq.Contents))
+	}
+	wantRoles := []string{"user", "model", "user"}
+	for i, content := range gotReq.Contents {
+		if content.Role != wantRoles[i] {
+			t.Errorf("content %d: expected role %q, got %q", i, wantRoles[i], content.Role)
+		}
+	}
+	config := gotReq.GenerationConfig
+	if config == nil || *config.Temperature != 0.3 || *config.TopP != 0.9 || *config.MaxOutputTokens != 64 || len(config.StopSequences) != 1 {
+		t.Errorf("unexpected generationConfig: %+v", config)
+	}
+
+	// 响应转换
+	if resp.Object != "chat.completion" || resp.Model != "gemini-1.5-pro" || resp.ID == "" {
+		t.Errorf("unexpected envelope: %+v", resp)
+	}
+	if len(resp.Choices) != 1 {
+		t.Fatalf("expected 1 choice, got %d", len(resp.Choices))
+	}
+	if resp.Choices[0].Message.Role != "assistant" || resp.Choices[0].Message.Content != "Paris is the capital." {
+		t.Errorf("unexpected message: %+v", resp.Choices[0].Message)
+	}
+	if resp.Choices[0].FinishReason != "length" {
+		t.Errorf("expected finish_reason 'length', got %q", resp.Choices[0].FinishReason)
+	}
+	if resp.Usage.PromptTokens != 12 || resp.Usage.CompletionTokens != 5 || resp.Usage.TotalTokens != 17 {
+		t.Errorf("unexpected usage: %+v", resp.Usage)
+	}
+}
+
+func TestGeminiAdapter_MergesConsecutiveRoles(t *testing.T) {
+	adapter := &GeminiAdapter{}
+	geminiReq, err := adapter.convertToGeminiRequest(&models.ChatCompletionRequest{
+		Model: "gemini-1.5-flash",
+		Messages: []models.ChatMessage{
+			{Role: "user", Content: "First"},
+			{Role: "user", Content: "Second"},
+		},
+		ResponseFormat: &models.ResponseFormat{Type: "json_object"},
+	})
+	if err != nil {
+		t.Fatalf("convertToGeminiRequest() error = %v", err)
+	}
+	if len(geminiReq.Contents) != 1 || len(geminiReq.Contents[0].Parts) != 2 {
+		t.Errorf("expected consecutive user messages to merge, got %+v", geminiReq.Contents)
+	}
+	if geminiReq.GenerationConfig.ResponseMimeType != "application/json" {
+		t.Errorf("expected JSON response mime type, got %q", geminiReq.GenerationConfig.ResponseMimeType)
+	}
+}
+
+func TestGeminiAdapter_ReasoningEffort(t *testing.T) {
+	adapter := &GeminiAdapter{}
+	maxTokens := 1000
+	geminiReq, err := adapter.convertToGeminiRequest(&models.ChatCompletionRequest{
+		Model: "gemini-2.5-flash",
+		Messages: []models.ChatMessage{
+			{Role: "developer", Content: "Be brief."},
+			{Role: "user", Content: "Hi"},
+		},
+		MaxCompletionTokens: &maxTokens,
+		ReasoningEffort:     "minimal",
+	})
+	if err != nil {
+		t.Fatalf("convertToGeminiRequest() error = %v", err)
+	}
+	if geminiReq.SystemInstruction == nil || len(geminiReq.Contents) != 1 {
+		t.Errorf("expected developer message as system instruction, got %+v", geminiReq)
+	}
+	config := geminiReq.GenerationConfig
+	if config.MaxOutputTokens == nil || *config.MaxOutputTokens != 1000 {
+		t.Errorf("expected maxOutputTokens 1000, got %v", config.MaxOutputTokens)
+	}
+	// minimal 对应预算 0，需要显式发送以关闭思考
+	if config.ThinkingConfig == nil || config.ThinkingConfig.ThinkingBudget == nil || *config.ThinkingConfig.ThinkingBudget != 0 {
+		t.Errorf("expected thinkingBudget 0, got %+v", config.ThinkingConfig)
+	}
+
+	// Gemini 2.5 Pro 不能关闭思考，minimal 使用最小预算
+	geminiReq, err = adapter.convertToGeminiRequest(&models.ChatCompletionRequest{
+		Model:           "gemini-2.5-pro",
+		Messages:        []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		ReasoningEffort: "minimal",
+	})
+	if err != nil {
+		t.Fatalf("convertToGeminiRequest() error = %v", err)
+	}
+	if config := geminiReq.GenerationConfig.ThinkingConfig; config == nil || config.ThinkingBudget == nil || *config.ThinkingBudget != 128 {
+		t.Errorf("expected thinkingBudget 128 for gemini-2.5-pro, got %+v", config)
+	}
+}
+
+func TestGeminiAdapter_ImageContent(t *testing.T) {
+	adapter := &GeminiAdapter{}
+	geminiReq, err := adapter.convertToGeminiRequest(&models.ChatCompletionRequest{
+		Model: "gemini-1.5-flash",
+		Messages: []models.ChatMessage{{Role: "user", Content: []models.ContentPart{
+			{Type: "text", Text: "Compare these."},
+			{Type: "image_url", ImageURL: &models.ImageURL{URL: "data:image/webp;base64,UklGR"}},
+			{Type: "image_url", ImageURL: &models.ImageURL{URL: "gs://bucket/cat.png"}},
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("convertToGeminiRequest() error = %v", err)
+	}
+
+	parts := geminiReq.Contents[0].Parts
+	if len(parts) != 3 || parts[0].Text != "Compare these." {
+		t.Fatalf("unexpected parts: %+v", parts)
+	}
+	if inline := parts[1].InlineData; inline == nil || inline.MimeType != "image/webp" || inline.Data != "UklGR" {
+		t.Errorf("unexpected inline data: %+v", parts[1].InlineData)
+	}
+	if file := parts[2].FileData; file == nil || file.MimeType != "image/png" || file.FileURI != "gs://bucket/cat.png" {
+		t.Errorf("unexpected file data: %+v", parts[2].FileData)
+	}
+}
+
+func TestGeminiAdapter_Document(t *testing.T) {
+	adapter := &GeminiAdapter{}
+	geminiReq, err := adapter.convertToGeminiRequest(&models.ChatCompletionRequest{
+		Model: "gemini-1.5-pro",
+		Messages: []models.ChatMessage{{Role: "user", Content: []models.ContentPart{
+			{Type: "document", Document: &models.Document{MediaType: "application/pdf", Data: "JVBERi0x"}},
+			{Type: "document", Document: &models.Document{MediaType: "text/plain", Data: "hi"}},
+			{Type: "text", Text: "Summarize."},
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("convertToGeminiRequest() error = %v", err)
+	}
+
+	parts := geminiReq.Contents[0].Parts
+	if pdf := parts[0].InlineData; pdf == nil || pdf.MimeType != "application/pdf" || pdf.Data != "JVBERi0x" {
+		t.Errorf("unexpected pdf part: %+v", parts[0])
+	}
+	if text := parts[1].InlineData; text == nil || text.MimeType != "text/plain" || text.Data != "aGk=" {
+		t.Errorf("expected base64 encoded text document, got %+v", parts[1])
+	}
+}
+
+func TestGeminiAdapter_FunctionCalling(t *testing.T) {
+	var gotReq GeminiRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		fmt.Fprint(w, `{
+  "candidates": [{
+    "content": {"parts": [{"functionCall": {"name": "get_weather", "args": {"location": "Paris"}}}], "role": "model"},
+    "finishReason": "STOP",
+    "index": 0
+  }]
+}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewGeminiAdapter("test-key", server.URL)
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "gemini-1.5-pro",
+		Messages: []models.ChatMessage{
+			{Role: "user", Content: "What's the weather in Berlin?"},
+			{Role: "assistant", ToolCalls: []models.ToolCall{{
+				ID:       "call_1",
+				Type:     "function",
+				Function: models.FunctionCall{Name: "get_weather", Arguments: `{"location":"Berlin"}`},
+			}}},
+			{Role: "tool", ToolCallID: "call_1", Content: `{"temperature":20}`},
+			{Role: "user", Content: "And Paris?"},
+		},
+		Tools: []models.Tool{{
+			Type: "function",
+			Function: models.FunctionDefinition{
+				Name:        "get_weather",
+				Description: "Get the weather",
+				Parameters: map[string]interface{}{
+					"$schema":              "http://json-schema.org/draft-07/schema#",
+					"type":                 "object",
+					"additionalProperties": false,
+					"properties": map[string]interface{}{
+						"location": map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		}},
+		ToolChoice: map[string]interface{}{
+			"type":     "function",
+			"function": map[string]interface{}{"name": "get_weather"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	// 请求转换
+	if len(gotReq.Tools) != 1 || len(gotReq.Tools[0].FunctionDeclarations) != 1 {
+		t.Fatalf("expected one function declaration, got %+v", gotReq.Tools)
+	}
+	params := gotReq.Tools[0].FunctionDeclarations[0].Parameters.(map[string]interface{})
+	if _, ok := params["$schema"]; ok {
+		t.Error("expected $schema to be removed from parameters")
+	}
+	if _, ok := params["additionalProperties"]; ok {
+		t.Error("expected additionalProperties to be removed from parameters")
+	}
+	if gotReq.ToolConfig == nil || gotReq.ToolConfig.FunctionCallingConfig.Mode != "ANY" {
+		t.Fatalf("expected function calling mode ANY, got %+v", gotReq.ToolConfig)
+	}
+	if names := gotReq.ToolConfig.FunctionCallingConfig.AllowedFunctionNames; len(names) != 1 || names[0] != "get_weather" {
+		t.Errorf("expected allowed function names [get_weather], got %v", names)
+	}
+	// 工具结果与随后的用户消息合并为同一个 user 轮次
+	if len(gotReq.Contents) != 3 || len(gotReq.Contents[2].Parts) != 2 {
+		t.Fatalf("expected 3 contents with a merged user turn, got %+v", gotReq.Contents)
+	}
+	call := gotReq.Contents[1].Parts[0].FunctionCall
+	if gotReq.Contents[1].Role != "model" || call == nil || call.Args["location"] != "Berlin" {
+		t.Errorf("expected model functionCall part, got %+v", gotReq.Contents[1])
+	}
+	result := gotReq.Contents[2].Parts[0].FunctionResponse
+	if gotReq.Contents[2].Role != "user" || result == nil || result.Name != "get_weather" || result.Response["temperature"] != float64(20) {
+		t.Errorf("expected user functionResponse part, got %+v", gotReq.Contents[2])
+	}
+
+	// 响应转换
+	choice := resp.Choices[0]
+	if choice.FinishReason != "tool_calls" {
+		t.Errorf("expected finish_reason 'tool_calls', got %q", choice.FinishReason)
+	}
+	if len(choice.Message.ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(choice.Message.ToolCalls))
+	}
+	tc := choice.Message.ToolCalls[0]
+	if !strings.HasPrefix(tc.ID, "call_") || tc.Function.Name != "get_weather" || tc.Function.Arguments != `{"location":"Paris"}` {
+		t.Errorf("unexpected tool call: %+v", tc)
+	}
+}
+
+func TestGeminiToolConfig(t *testing.T) {
+	tests := []struct {
+		choice interface{}
+		mode   string
+	}{
+		{"auto", "AUTO"},
+		{"none", "NONE"},
+		{"required", "ANY"},
+	}
+	for _, tt := range tests {
+		config, err := toGeminiToolConfig(tt.choice)
+		if err != nil {
+			t.Fatalf("toGeminiToolConfig(%v) error = %v", tt.choice, err)
+		}
+		if config.FunctionCallingConfig.Mode != tt.mode {
+			t.Errorf("toGeminiToolConfig(%v) mode = %q, want %q", tt.choice, config.FunctionCallingConfig.Mode, tt.mode)
+		}
+	}
+
+	if _, err := toGeminiToolConfig("sometimes"); err == nil {
+		t.Error("expected error for unsupported tool_choice")
+	}
+}
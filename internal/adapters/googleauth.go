@@ -0,0 +1,222 @@
+package adapters
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	googleDefaultTokenURI = "https://oauth2.googleapis.com/token"
+	googleCloudScope      = "https://www.googleapis.com/auth/cloud-platform"
+
+	// access_token 提前刷新的时间，避免请求过程中过期
+	googleTokenRefreshMargin = 5 * time.Minute
+)
+
+// googleServiceAccount 服务账号 JSON 密钥中用到的字段
+type googleServiceAccount struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// parseGoogleServiceAccount 解析服务账号密钥，key 为 JSON 内容或 JSON 文件路径
+func parseGoogleServiceAccount(key string) (*googleServiceAccount, *rsa.PrivateKey, error) {
+	data := []byte(key)
+	if !strings.HasPrefix(strings.TrimSpace(key), "{") {
+		var err error
+		if data, err = os.ReadFile(key); err != nil {
+			return nil, nil, fmt.Errorf("failed to read service account key: %w", err)
+		}
+	}
+
+	var account googleServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse service account key: %w", err)
+	}
+	if account.Type != "service_account" || account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, nil, fmt.Errorf("invalid service account key: type must be \"service_account\" with client_email and private_key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = googleDefaultTokenURI
+	}
+
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return nil, nil, fmt.Errorf("invalid service account private key: no PEM block found")
+	}
+	// 服务账号密钥为 PKCS#8 格式，兼容 PKCS#1
+	var privateKey *rsa.PrivateKey
+	if parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid service account private key: not an RSA key")
+		}
+		privateKey = rsaKey
+	} else if privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+		return nil, nil, fmt.Errorf("invalid service account private key: %w", err)
+	}
+
+	return &account, privateKey, nil
+}
+
+// 网关每个请求都会创建新的适配器，令牌源在包级别共享
+// googleKeySources 以密钥参数（JSON 内容或文件路径）为 key，避免每次请求重新读取和解析密钥
+// googleTokenSources 以服务账号、私钥 ID、令牌地址和 scope 为 key，同一账号共享 access_token 缓存
+var (
+	googleTokenSourcesMu sync.Mutex
+	googleKeySources     = make(map[string]*googleTokenSource)
+	googleTokenSources   = make(map[string]*googleTokenSource)
+)
+
+// sharedGoogleTokenSource 返回服务账号密钥对应的共享令牌源
+func sharedGoogleTokenSource(key string) (*googleTokenSource, error) {
+	googleTokenSourcesMu.Lock()
+	defer googleTokenSourcesMu.Unlock()
+
+	if source, ok := googleKeySources[key]; ok {
+		return source, nil
+	}
+
+	account, privateKey, err := parseGoogleServiceAccount(key)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceKey := strings.Join([]string{account.ClientEmail, account.PrivateKeyID, account.TokenURI, googleCloudScope}, "|")
+	source, ok := googleTokenSources[sourceKey]
+	if !ok {
+		source = &googleTokenSource{
+			account:    account,
+			privateKey: privateKey,
+			client: &http.Client{
+				Timeout: 30 * time.Second,
+			},
+		}
+		googleTokenSources[sourceKey] = source
+	}
+	googleKeySources[key] = source
+	return source, nil
+}
+
+// googleTokenSource 使用服务账号自签名的 JWT 换取 OAuth2 access_token 并缓存
+type googleTokenSource struct {
+	account    *googleServiceAccount
+	privateKey *rsa.PrivateKey
+	client     *http.Client
+
+	// access_token 缓存，mu 保证并发时只有一个请求去刷新
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// accessToken 返回缓存的 access_token，过期或即将过期时重新获取
+func (s *googleTokenSource) accessToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expiresAt) {
+		return s.token, nil
+	}
+
+	assertion, err := s.signJWT(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("google token error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tokenResp GoogleTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return "", fmt.Errorf("google token error: status %d, %s: %s", resp.StatusCode, tokenResp.Error, tokenResp.ErrorDescription)
+	}
+
+	expiresIn := time.Duration(tokenResp.ExpiresIn) * time.Second
+	if expiresIn > googleTokenRefreshMargin {
+		expiresIn -= googleTokenRefreshMargin
+	}
+	s.token = tokenResp.AccessToken
+	s.expiresAt = time.Now().Add(expiresIn)
+	return s.token, nil
+}
+
+// invalidateToken 丢弃缓存的 access_token，下次请求时重新获取
+func (s *googleTokenSource) invalidateToken() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+	s.expiresAt = time.Time{}
+}
+
+// signJWT 生成 RS256 签名的 JWT 授权断言，有效期一小时
+func (s *googleTokenSource) signJWT(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+		"kid": s.account.PrivateKeyID,
+	})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   s.account.ClientEmail,
+		"scope": googleCloudScope,
+		"aud":   s.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign jwt: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+type GoogleTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	TokenType        string `json:"token_type,omitempty"`
+	Error            string `json:"error,omitempty"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
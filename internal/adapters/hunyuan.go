@@ -1,15 +1,29 @@
 package adapters
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/gotoailab/llmhub/internal/models"
 )
 
+// 混元 API 版本
+const hunyuanAPIVersion = "2023-09-01"
+
 // HunyuanAdapter 腾讯混元适配器
+// 使用腾讯云 API 3.0，apiKey 格式为 "SecretId:SecretKey"，可追加地域 "SecretId:SecretKey:Region"
 type HunyuanAdapter struct {
-	*openAICompatibleAdapter
+	baseURL string
+	region  string
+	signer  *tencentCloudSigner
+	client  *http.Client
 }
 
 // NewHunyuanAdapter 创建腾讯混元适配器
@@ -17,8 +31,38 @@ func NewHunyuanAdapter(apiKey, baseURL string) (Adapter, error) {
 	if baseURL == "" {
 		baseURL = "https://hunyuan.tencentcloudapi.com"
 	}
-	adapter := NewOpenAICompatibleAdapter(Provider("hunyuan"), apiKey, baseURL, "/chat/completions", "Bearer")
-	return &HunyuanAdapter{adapter}, nil
+
+	parts := strings.SplitN(apiKey, ":", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("hunyuan api key must be in the format \"SecretId:SecretKey\"")
+	}
+	region := "ap-guangzhou"
+	if len(parts) == 3 && parts[2] != "" {
+		region = parts[2]
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hunyuan base url: %w", err)
+	}
+	// 签名的服务名取自域名的第一段，如 hunyuan.tencentcloudapi.com 为 hunyuan
+	service := "hunyuan"
+	if strings.HasSuffix(u.Hostname(), ".tencentcloudapi.com") {
+		service = strings.SplitN(u.Hostname(), ".", 2)[0]
+	}
+
+	return &HunyuanAdapter{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		region:  region,
+		signer: &tencentCloudSigner{
+			secretID:  parts[0],
+			secretKey: parts[1],
+			service:   service,
+		},
+		client: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}, nil
 }
 
 func (a *HunyuanAdapter) GetProvider() Provider {
@@ -26,9 +70,233 @@ func (a *HunyuanAdapter) GetProvider() Provider {
 }
 
 func (a *HunyuanAdapter) ChatCompletion(ctx context.Context, req *models.ChatCompletionRequest) (*models.ChatCompletionResponse, error) {
-	return a.openAICompatibleAdapter.ChatCompletion(ctx, req)
+	if len(req.Tools) > 0 || len(req.Functions) > 0 {
+		return nil, fmt.Errorf("tool use not supported for provider %s", a.GetProvider())
+	}
+
+	hunyuanReq, err := a.convertToHunyuanRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+
+	resp, err := a.do(ctx, hunyuanReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var envelope HunyuanEnvelope
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if envelope.Response.Error != nil {
+		return nil, envelope.Response.Error.toError("hunyuan api error", envelope.Response.RequestID)
+	}
+
+	return a.convertFromHunyuanResponse(&envelope.Response, req.Model), nil
 }
 
 func (a *HunyuanAdapter) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
-	return a.openAICompatibleAdapter.ChatCompletionStream(ctx, req)
+	if len(req.Tools) > 0 || len(req.Functions) > 0 {
+		return nil, fmt.Errorf("tool use not supported for provider %s", a.GetProvider())
+	}
+
+	hunyuanReq, err := a.convertToHunyuanRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+	hunyuanReq.Stream = true
+
+	resp, err := a.do(ctx, hunyuanReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// 请求出错时返回的是 JSON 而不是 SSE
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		defer resp.Body.Close()
+		var envelope HunyuanEnvelope
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		if envelope.Response.Error != nil {
+			return nil, envelope.Response.Error.toError("hunyuan stream error", envelope.Response.RequestID)
+		}
+		return nil, fmt.Errorf("hunyuan stream error: unexpected non-stream response")
+	}
+
+	return newHunyuanStream(resp.Body, req), nil
+}
+
+// do 签名并发送 ChatCompletions 请求
+func (a *HunyuanAdapter) do(ctx context.Context, hunyuanReq *HunyuanRequest) (*http.Response, error) {
+	reqBody, err := json.Marshal(hunyuanReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", a.baseURL+"/", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json; charset=utf-8")
+	httpReq.Header.Set("X-TC-Action", "ChatCompletions")
+	httpReq.Header.Set("X-TC-Version", hunyuanAPIVersion)
+	httpReq.Header.Set("X-TC-Region", a.region)
+	a.signer.sign(httpReq, reqBody, time.Now())
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("hunyuan api error: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("hunyuan api error: status %d, body: %s", resp.StatusCode, string(body))
+	}
+	return resp, nil
+}
+
+type HunyuanRequest struct {
+	Model       string           `json:"Model"`
+	Messages    []HunyuanMessage `json:"Messages"`
+	Stream      bool             `json:"Stream"`
+	Temperature *float64         `json:"Temperature,omitempty"`
+	TopP        *float64         `json:"TopP,omitempty"`
+	Seed        *int             `json:"Seed,omitempty"`
+	Stop        []string         `json:"Stop,omitempty"`
+}
+
+type HunyuanMessage struct {
+	Role    string `json:"Role"`
+	Content string `json:"Content"`
+}
+
+// HunyuanEnvelope 腾讯云 API 3.0 的响应外层结构
+type HunyuanEnvelope struct {
+	Response HunyuanResponse `json:"Response"`
+}
+
+type HunyuanResponse struct {
+	ID        string          `json:"Id"`
+	Note      string          `json:"Note,omitempty"`
+	Created   int64           `json:"Created"`
+	Choices   []HunyuanChoice `json:"Choices"`
+	Usage     HunyuanUsage    `json:"Usage"`
+	RequestID string          `json:"RequestId,omitempty"`
+	Error     *HunyuanError   `json:"Error,omitempty"`
+}
+
+type HunyuanChoice struct {
+	FinishReason string          `json:"FinishReason"`
+	Message      *HunyuanMessage `json:"Message,omitempty"`
+	Delta        *HunyuanMessage `json:"Delta,omitempty"`
+}
+
+type HunyuanUsage struct {
+	PromptTokens     int `json:"PromptTokens"`
+	CompletionTokens int `json:"CompletionTokens"`
+	TotalTokens      int `json:"TotalTokens"`
+}
+
+type HunyuanError struct {
+	Code    string `json:"Code"`
+	Message string `json:"Message"`
+}
+
+func (e *HunyuanError) toError(prefix, requestID string) error {
+	return fmt.Errorf("%s: %s: %s (request id: %s)", prefix, e.Code, e.Message, requestID)
+}
+
+func (a *HunyuanAdapter) convertToHunyuanRequest(req *models.ChatCompletionRequest) (*HunyuanRequest, error) {
+	// 消息内容按纯文本发送，图片会被丢弃
+	if hasContentPart(req.Messages, "image_url") {
+		return nil, fmt.Errorf("image input not supported for provider %s", a.GetProvider())
+	}
+
+	hunyuanReq := &HunyuanRequest{
+		Model:       req.Model,
+		Messages:    make([]HunyuanMessage, 0, len(req.Messages)),
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		Seed:        req.Seed,
+		Stop:        req.Stop,
+	}
+
+	for _, msg := range req.Messages {
+		role := nativeRole(msg.Role)
+		switch role {
+		case "system", "user", "assistant":
+		default:
+			// 工具结果等消息无法表达，不能改写为 user 发送
+			return nil, fmt.Errorf("message role %q not supported for provider %s", msg.Role, a.GetProvider())
+		}
+		text := contentToText(msg.Content)
+
+		// 混元要求 user/assistant 交替出现，相邻的同角色消息合并
+		if n := len(hunyuanReq.Messages); n > 0 && hunyuanReq.Messages[n-1].Role == role {
+			hunyuanReq.Messages[n-1].Content += "\n\n" + text
+			continue
+		}
+		hunyuanReq.Messages = append(hunyuanReq.Messages, HunyuanMessage{Role: role, Content: text})
+	}
+	if len(hunyuanReq.Messages) == 0 {
+		return nil, fmt.Errorf("at least one message is required")
+	}
+
+	return hunyuanReq, nil
+}
+
+func (a *HunyuanAdapter) convertFromHunyuanResponse(hunyuanResp *HunyuanResponse, modelName string) *models.ChatCompletionResponse {
+	choices := make([]models.ChatCompletionChoice, 0, len(hunyuanResp.Choices))
+	for i, choice := range hunyuanResp.Choices {
+		var content string
+		if choice.Message != nil {
+			content = choice.Message.Content
+		}
+		choices = append(choices, models.ChatCompletionChoice{
+			Index: i,
+			Message: models.ChatMessage{
+				Role:    "assistant",
+				Content: content,
+			},
+			FinishReason: mapHunyuanFinishReason(choice.FinishReason),
+		})
+	}
+
+	id := hunyuanResp.ID
+	if id == "" {
+		id = newResponseID()
+	}
+	created := hunyuanResp.Created
+	if created == 0 {
+		created = time.Now().Unix()
+	}
+
+	return &models.ChatCompletionResponse{
+		ID:      id,
+		Object:  "chat.completion",
+		Created: created,
+		Model:   modelName,
+		Choices: choices,
+		Usage: models.Usage{
+			PromptTokens:     hunyuanResp.Usage.PromptTokens,
+			CompletionTokens: hunyuanResp.Usage.CompletionTokens,
+			TotalTokens:      hunyuanResp.Usage.TotalTokens,
+		},
+	}
+}
+
+// mapHunyuanFinishReason 混元的 sensitive 表示触发内容审核
+func mapHunyuanFinishReason(reason string) string {
+	switch reason {
+	case "sensitive":
+		return "content_filter"
+	case "tool_calls":
+		return "tool_calls"
+	default:
+		return reason
+	}
 }
@@ -0,0 +1,78 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// hunyuanStreamTranslator 将混元 SSE 转换为 OpenAI chat.completion.chunk
+// 流式事件不带 Response 外层，Choices[].Delta 为增量内容
+type hunyuanStreamTranslator struct {
+	reader  *SSEReader
+	model   string
+	created int64
+}
+
+func newHunyuanStream(body io.ReadCloser, req *models.ChatCompletionRequest) io.ReadCloser {
+	t := &hunyuanStreamTranslator{
+		reader:  NewSSEReader(body),
+		model:   req.Model,
+		created: time.Now().Unix(),
+	}
+	return newChunkStream(req, t.next, body)
+}
+
+func (t *hunyuanStreamTranslator) next() ([]*models.ChatCompletionStreamResponse, error) {
+	sse, err := t.reader.Next()
+	if err != nil {
+		return nil, err
+	}
+	if len(sse.Data) == 0 {
+		return nil, nil
+	}
+
+	var resp HunyuanResponse
+	if err := json.Unmarshal(sse.Data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode hunyuan stream event: %w", err)
+	}
+	if resp.Error != nil {
+		return nil, resp.Error.toError("hunyuan stream error", resp.RequestID)
+	}
+
+	created := resp.Created
+	if created == 0 {
+		created = t.created
+	}
+	chunk := &models.ChatCompletionStreamResponse{
+		ID:      resp.ID,
+		Object:  "chat.completion.chunk",
+		Created: created,
+		Model:   t.model,
+		Choices: make([]models.ChatCompletionStreamChoice, 0, len(resp.Choices)),
+	}
+	for i, choice := range resp.Choices {
+		var delta models.ChatMessageDelta
+		if choice.Delta != nil {
+			delta.Role = choice.Delta.Role
+			delta.Content = choice.Delta.Content
+		}
+		chunk.Choices = append(chunk.Choices, models.ChatCompletionStreamChoice{
+			Index:        i,
+			Delta:        delta,
+			FinishReason: mapHunyuanFinishReason(choice.FinishReason),
+		})
+	}
+	if resp.Usage.TotalTokens > 0 {
+		chunk.Usage = &models.Usage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		}
+	}
+
+	return []*models.ChatCompletionStreamResponse{chunk}, nil
+}
@@ -0,0 +1,226 @@
+package adapters
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// verifyTC3Signature 按腾讯云文档的步骤独立计算签名并与请求中的签名比较
+func verifyTC3Signature(t *testing.T, r *http.Request, body []byte, secretID, secretKey, service string) {
+	t.Helper()
+
+	timestamp, err := strconv.ParseInt(r.Header.Get("X-TC-Timestamp"), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid X-TC-Timestamp %q", r.Header.Get("X-TC-Timestamp"))
+	}
+	date := time.Unix(timestamp, 0).UTC().Format("2006-01-02")
+
+	hash := func(s []byte) string {
+		sum := sha256.Sum256(s)
+		return hex.EncodeToString(sum[:])
+	}
+	mac := func(key []byte, s string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(s))
+		return h.Sum(nil)
+	}
+
+	canonicalRequest := "POST\n/\n\n" +
+		"content-type:" + r.Header.Get("Content-Type") + "\n" +
+		"host:" + r.Host + "\n" +
+		"x-tc-action:" + strings.ToLower(r.Header.Get("X-TC-Action")) + "\n\n" +
+		"content-type;host;x-tc-action\n" + hash(body)
+	scope := date + "/" + service + "/tc3_request"
+	stringToSign := "TC3-HMAC-SHA256\n" + strconv.FormatInt(timestamp, 10) + "\n" + scope + "\n" + hash([]byte(canonicalRequest))
+	key := mac(mac(mac([]byte("TC3"+secretKey), date), service), "tc3_request")
+	signature := hex.EncodeToString(mac(key, stringToSign))
+
+	want := fmt.Sprintf("TC3-HMAC-SHA256 Credential=%s/%s, SignedHeaders=content-type;host;x-tc-action, Signature=%s", secretID, scope, signature)
+	if got := r.Header.Get("Authorization"); got != want {
+		t.Errorf("unexpected Authorization\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestNewHunyuanAdapter_InvalidKey(t *testing.T) {
+	if _, err := NewHunyuanAdapter("secret-id-only", ""); err == nil {
+		t.Error("expected error for api key without secret key")
+	}
+}
+
+func TestHunyuanAdapter_ChatCompletion(t *testing.T) {
+	var gotReq HunyuanRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verifyTC3Signature(t, r, body, "AKIDtest", "secret", "hunyuan")
+
+		if r.Header.Get("X-TC-Action") != "ChatCompletions" || r.Header.Get("X-TC-Version") != "2023-09-01" {
+			t.Errorf("unexpected action/version headers: %v", r.Header)
+		}
+		if r.Header.Get("X-TC-Region") != "ap-beijing" {
+			t.Errorf("expected region ap-beijing, got %q", r.Header.Get("X-TC-Region"))
+		}
+		json.Unmarshal(body, &gotReq)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"Response":{"Id":"hy-1","Created":1700000000,"Note":"以上内容为AI生成","Choices":[{"FinishReason":"stop","Message":{"Role":"assistant","Content":"你好！"}}],"Usage":{"PromptTokens":3,"CompletionTokens":2,"TotalTokens":5},"RequestId":"req-1"}}`)
+	}))
+	defer server.Close()
+
+	adapter, err := NewHunyuanAdapter("AKIDtest:secret:ap-beijing", server.URL)
+	if err != nil {
+		t.Fatalf("NewHunyuanAdapter() error = %v", err)
+	}
+
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "hunyuan-pro",
+		Messages: []models.ChatMessage{
+			{Role: "system", Content: "你是一个助手"},
+			{Role: "user", Content: "你好"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	if gotReq.Model != "hunyuan-pro" || len(gotReq.Messages) != 2 || gotReq.Messages[1].Content != "你好" {
+		t.Errorf("unexpected request: %+v", gotReq)
+	}
+	if resp.ID != "hy-1" || resp.Choices[0].Message.Content != "你好！" || resp.Choices[0].FinishReason != "stop" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if resp.Usage.TotalTokens != 5 {
+		t.Errorf("expected total tokens 5, got %d", resp.Usage.TotalTokens)
+	}
+}
+
+func TestHunyuanAdapter_ErrorEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"Response":{"Error":{"Code":"AuthFailure.SignatureFailure","Message":"The provided credentials could not be validated."},"RequestId":"req-2"}}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewHunyuanAdapter("AKIDtest:secret", server.URL)
+	req := &models.ChatCompletionRequest{
+		Model:    "hunyuan-lite",
+		Messages: []models.ChatMessage{{Role: "user", Content: "hi"}},
+	}
+
+	if _, err := adapter.ChatCompletion(context.Background(), req); err == nil || !strings.Contains(err.Error(), "AuthFailure.SignatureFailure") {
+		t.Errorf("expected error code in error, got %v", err)
+	}
+	if _, err := adapter.ChatCompletionStream(context.Background(), req); err == nil || !strings.Contains(err.Error(), "req-2") {
+		t.Errorf("expected request id in stream error, got %v", err)
+	}
+}
+
+func TestHunyuanAdapter_ChatCompletionStream(t *testing.T) {
+	events := []string{
+		`{"Id":"hy-3","Created":1700000000,"Choices":[{"FinishReason":"","Delta":{"Role":"assistant","Content":"你好"}}],"Usage":{"PromptTokens":3,"CompletionTokens":1,"TotalTokens":4}}`,
+		`{"Id":"hy-3","Created":1700000000,"Choices":[{"FinishReason":"stop","Delta":{"Role":"assistant","Content":"！"}}],"Usage":{"PromptTokens":3,"CompletionTokens":2,"TotalTokens":5}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req HunyuanRequest
+		json.Unmarshal(body, &req)
+		if !req.Stream {
+			t.Error("expected Stream to be true")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, e := range events {
+			fmt.Fprintf(w, "data: %s\n\n", e)
+		}
+	}))
+	defer server.Close()
+
+	adapter, _ := NewHunyuanAdapter("AKIDtest:secret", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:         "hunyuan-lite",
+		Messages:      []models.ChatMessage{{Role: "user", Content: "你好"}},
+		StreamOptions: &models.StreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, done := collectStreamChunks(t, stream)
+	if !done {
+		t.Error("expected stream to end with data: [DONE]")
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+	if chunks[0].ID != "hy-3" || chunks[0].Choices[0].Delta.Content != "你好" {
+		t.Errorf("unexpected first chunk: %+v", chunks[0])
+	}
+	if chunks[1].Choices[0].FinishReason != "stop" {
+		t.Errorf("expected finish_reason 'stop', got %q", chunks[1].Choices[0].FinishReason)
+	}
+	if chunks[2].Usage == nil || chunks[2].Usage.TotalTokens != 5 {
+		t.Errorf("expected usage chunk with 5 total tokens, got %+v", chunks[2].Usage)
+	}
+}
+
+func TestHunyuanAdapter_RejectsTools(t *testing.T) {
+	adapter, _ := NewHunyuanAdapter("secret-id:secret-key", "http://127.0.0.1:0")
+	req := &models.ChatCompletionRequest{
+		Model:    "hunyuan-pro",
+		Messages: []models.ChatMessage{{Role: "user", Content: "北京天气怎么样？"}},
+		Tools:    []models.Tool{{Type: "function", Function: models.FunctionDefinition{Name: "get_weather"}}},
+	}
+	if _, err := adapter.ChatCompletion(context.Background(), req); err == nil || !strings.Contains(err.Error(), "tool use not supported for provider hunyuan") {
+		t.Errorf("expected tool use error, got %v", err)
+	}
+
+	req.Tools = nil
+	req.Functions = []models.FunctionDefinition{{Name: "get_weather"}}
+	if _, err := adapter.ChatCompletionStream(context.Background(), req); err == nil || !strings.Contains(err.Error(), "tool use not supported for provider hunyuan") {
+		t.Errorf("expected tool use error, got %v", err)
+	}
+}
+
+func TestHunyuanAdapter_ConvertMessages(t *testing.T) {
+	adapter := &HunyuanAdapter{}
+	hunyuanReq, err := adapter.convertToHunyuanRequest(&models.ChatCompletionRequest{
+		Model: "hunyuan-pro",
+		Messages: []models.ChatMessage{
+			{Role: "system", Content: "简洁回答"},
+			{Role: "user", Content: "你好"},
+			{Role: "user", Content: "介绍一下自己"},
+			{Role: "assistant", Content: "我是混元。"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("convertToHunyuanRequest() error = %v", err)
+	}
+	// 相邻的同角色消息合并
+	if len(hunyuanReq.Messages) != 3 || hunyuanReq.Messages[1].Content != "你好\n\n介绍一下自己" {
+		t.Errorf("expected adjacent user messages merged, got %+v", hunyuanReq.Messages)
+	}
+
+	_, err = adapter.convertToHunyuanRequest(&models.ChatCompletionRequest{
+		Model: "hunyuan-pro",
+		Messages: []models.ChatMessage{
+			{Role: "user", Content: "北京天气怎么样？"},
+			{Role: "tool", Content: "晴", ToolCallID: "call_1"},
+			{Role: "user", Content: "那上海呢？"},
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), `message role "tool" not supported for provider hunyuan`) {
+		t.Errorf("expected tool role error, got %v", err)
+	}
+}
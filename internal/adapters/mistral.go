@@ -101,5 +101,5 @@ func (a *MistralAdapter) ChatCompletionStream(ctx context.Context, req *models.C
 		return nil, fmt.Errorf("mistral stream error: status %d", resp.StatusCode)
 	}
 
-	return resp.Body, nil
+	return newOpenAICompatibleStream(resp.Body, req), nil
 }
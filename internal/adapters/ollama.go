@@ -1,15 +1,24 @@
 package adapters
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gotoailab/llmhub/internal/models"
 )
 
 // OllamaAdapter Ollama适配器
+// 使用 Ollama 原生的 /api/chat 接口，流式响应为 NDJSON
 type OllamaAdapter struct {
-	*openAICompatibleAdapter
+	apiKey  string
+	baseURL string
+	client  *http.Client
 }
 
 // NewOllamaAdapter 创建Ollama适配器
@@ -17,8 +26,15 @@ func NewOllamaAdapter(apiKey, baseURL string) (Adapter, error) {
 	if baseURL == "" {
 		baseURL = "http://localhost:11434"
 	}
-	adapter := NewOpenAICompatibleAdapter(Provider("ollama"), apiKey, baseURL, "/api/chat", "")
-	return &OllamaAdapter{adapter}, nil
+
+	return &OllamaAdapter{
+		apiKey:  apiKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client: &http.Client{
+			// 本地模型首次加载较慢
+			Timeout: 300 * time.Second,
+		},
+	}, nil
 }
 
 func (a *OllamaAdapter) GetProvider() Provider {
@@ -26,9 +42,304 @@ func (a *OllamaAdapter) GetProvider() Provider {
 }
 
 func (a *OllamaAdapter) ChatCompletion(ctx context.Context, req *models.ChatCompletionRequest) (*models.ChatCompletionResponse, error) {
-	return a.openAICompatibleAdapter.ChatCompletion(ctx, req)
+	ollamaReq, err := a.convertToOllamaRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+	ollamaReq.Stream = false
+
+	resp, err := a.do(ctx, ollamaReq)
+	if err != nil {
+		return nil, fmt.Errorf("ollama api error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var ollamaResp OllamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if ollamaResp.Error != "" {
+		return nil, fmt.Errorf("ollama api error: %s", ollamaResp.Error)
+	}
+
+	return a.convertFromOllamaResponse(&ollamaResp, req.Model), nil
 }
 
 func (a *OllamaAdapter) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
-	return a.openAICompatibleAdapter.ChatCompletionStream(ctx, req)
+	ollamaReq, err := a.convertToOllamaRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+	ollamaReq.Stream = true
+
+	resp, err := a.do(ctx, ollamaReq)
+	if err != nil {
+		return nil, fmt.Errorf("ollama stream error: %w", err)
+	}
+
+	return newOllamaStream(resp.Body, req), nil
+}
+
+func (a *OllamaAdapter) do(ctx context.Context, ollamaReq *OllamaRequest) (*http.Response, error) {
+	reqBody, err := json.Marshal(ollamaReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", a.baseURL+"/api/chat", bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	// 本地部署通常无需鉴权，经反向代理访问时可配置 API Key
+	if a.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+a.apiKey)
+	}
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("status %d, body: %s", resp.StatusCode, string(body))
+	}
+	return resp, nil
+}
+
+type OllamaRequest struct {
+	Model     string          `json:"model"`
+	Messages  []OllamaMessage `json:"messages"`
+	Tools     []models.Tool   `json:"tools,omitempty"`
+	Format    interface{}     `json:"format,omitempty"` // "json" 或 JSON Schema
+	Options   *OllamaOptions  `json:"options,omitempty"`
+	Stream    bool            `json:"stream"`
+	KeepAlive string          `json:"keep_alive,omitempty"`
+	Think     *bool           `json:"think,omitempty"` // 是否开启思考，思考过程在 message.thinking 中返回
+}
+
+type OllamaOptions struct {
+	Temperature      *float64 `json:"temperature,omitempty"`
+	TopP             *float64 `json:"top_p,omitempty"`
+	NumPredict       *int     `json:"num_predict,omitempty"`
+	Seed             *int     `json:"seed,omitempty"`
+	Stop             []string `json:"stop,omitempty"`
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"`
+}
+
+type OllamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	Thinking  string           `json:"thinking,omitempty"` // 开启 think 时模型返回的思考过程
+	Images    []string         `json:"images,omitempty"`   // base64 编码的图片，用于 llava 等视觉模型
+	ToolCalls []OllamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"`
+}
+
+type OllamaToolCall struct {
+	Function OllamaFunctionCall `json:"function"`
+}
+
+type OllamaFunctionCall struct {
+	Index     *int                   `json:"index,omitempty"`
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+type OllamaResponse struct {
+	Model           string        `json:"model"`
+	CreatedAt       time.Time     `json:"created_at"`
+	Message         OllamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	DoneReason      string        `json:"done_reason,omitempty"`
+	PromptEvalCount int           `json:"prompt_eval_count,omitempty"`
+	EvalCount       int           `json:"eval_count,omitempty"`
+	Error           string        `json:"error,omitempty"`
+}
+
+func (a *OllamaAdapter) convertToOllamaRequest(req *models.ChatCompletionRequest) (*OllamaRequest, error) {
+	ollamaReq := &OllamaRequest{
+		Model:     req.Model,
+		Messages:  make([]OllamaMessage, 0, len(req.Messages)),
+		KeepAlive: req.KeepAlive,
+	}
+
+	// 记录 tool_call_id 对应的函数名，Ollama 通过 tool_name 关联工具结果
+	toolNames := make(map[string]string)
+	for _, msg := range req.Messages {
+		ollamaMsg := OllamaMessage{
+			Role:    nativeRole(msg.Role),
+			Content: contentToText(msg.Content),
+		}
+		images, err := ollamaImages(msg.Content)
+		if err != nil {
+			return nil, err
+		}
+		ollamaMsg.Images = images
+
+		for _, tc := range msg.ToolCalls {
+			toolNames[tc.ID] = tc.Function.Name
+			ollamaMsg.ToolCalls = append(ollamaMsg.ToolCalls, OllamaToolCall{
+				Function: OllamaFunctionCall{
+					Name:      tc.Function.Name,
+					Arguments: parseToolArguments(tc.Function.Arguments),
+				},
+			})
+		}
+		if msg.FunctionCall != nil {
+			ollamaMsg.ToolCalls = append(ollamaMsg.ToolCalls, OllamaToolCall{
+				Function: OllamaFunctionCall{
+					Name:      msg.FunctionCall.Name,
+					Arguments: parseToolArguments(msg.FunctionCall.Arguments),
+				},
+			})
+		}
+
+		if msg.Role == "tool" || msg.Role == "function" {
+			ollamaMsg.Role = "tool"
+			ollamaMsg.ToolName = msg.Name
+			if ollamaMsg.ToolName == "" {
+				ollamaMsg.ToolName = toolNames[msg.ToolCallID]
+			}
+		}
+
+		ollamaReq.Messages = append(ollamaReq.Messages, ollamaMsg)
+	}
+
+	// 工具定义与 OpenAI 格式一致
+	ollamaReq.Tools = append(ollamaReq.Tools, req.Tools...)
+	for _, fn := range req.Functions {
+		ollamaReq.Tools = append(ollamaReq.Tools, models.Tool{Type: "function", Function: fn})
+	}
+
+	if req.ResponseFormat != nil {
+		switch req.ResponseFormat.Type {
+		case "json_object":
+			ollamaReq.Format = "json"
+		case "json_schema":
+			if req.ResponseFormat.JSONSchema == nil || req.ResponseFormat.JSONSchema.Schema == nil {
+				return nil, fmt.Errorf("response_format json_schema requires a schema")
+			}
+			ollamaReq.Format = req.ResponseFormat.JSONSchema.Schema
+		}
+	}
+
+	if req.ReasoningEffort != "" {
+		budget, err := reasoningBudget(req.ReasoningEffort)
+		if err != nil {
+			return nil, err
+		}
+		think := budget > 0
+		ollamaReq.Think = &think
+	}
+
+	options := &OllamaOptions{
+		Temperature:      req.Temperature,
+		TopP:             req.TopP,
+		NumPredict:       maxOutputTokens(req),
+		Seed:             req.Seed,
+		Stop:             req.Stop,
+		PresencePenalty:  req.PresencePenalty,
+		FrequencyPenalty: req.FrequencyPenalty,
+	}
+	if options.Temperature != nil || options.TopP != nil || options.NumPredict != nil || options.Seed != nil ||
+		len(options.Stop) > 0 || options.PresencePenalty != nil || options.FrequencyPenalty != nil {
+		ollamaReq.Options = options
+	}
+
+	return ollamaReq, nil
+}
+
+// ollamaImages 提取消息中的图片，Ollama 只接受 base64 编码的图片数据
+func ollamaImages(content interface{}) ([]string, error) {
+	if _, isString := content.(string); isString {
+		return nil, nil
+	}
+	parts, _ := contentParts(content)
+	var images []string
+	for _, part := range parts {
+		if part.Type != "image_url" || part.ImageURL == nil {
+			continue
+		}
+		_, data, ok := parseDataURI(part.ImageURL.URL)
+		if !ok {
+			return nil, fmt.Errorf("ollama only supports base64 data URI images, got %q", part.ImageURL.URL)
+		}
+		images = append(images, data)
+	}
+	return images, nil
+}
+
+func (a *OllamaAdapter) convertFromOllamaResponse(ollamaResp *OllamaResponse, modelName string) *models.ChatCompletionResponse {
+	message := models.ChatMessage{
+		Role:             "assistant",
+		Content:          ollamaResp.Message.Content,
+		ReasoningContent: ollamaResp.Message.Thinking,
+	}
+	// 未开启 think 时，R1 类模型的思考过程以 <think> 标签出现在正文中
+	if message.ReasoningContent == "" {
+		message.ReasoningContent, message.Content = splitThinkTags(ollamaResp.Message.Content)
+	}
+	message.ToolCalls = toolCallsFromOllama(ollamaResp.Message.ToolCalls)
+
+	created := ollamaResp.CreatedAt.Unix()
+	if ollamaResp.CreatedAt.IsZero() {
+		created = time.Now().Unix()
+	}
+
+	return &models.ChatCompletionResponse{
+		ID:      newResponseID(),
+		Object:  "chat.completion",
+		Created: created,
+		Model:   modelName,
+		Choices: []models.ChatCompletionChoice{
+			{
+				Index:        0,
+				Message:      message,
+				FinishReason: mapOllamaDoneReason(ollamaResp.DoneReason, len(message.ToolCalls) > 0),
+			},
+		},
+		Usage: models.Usage{
+			PromptTokens:     ollamaResp.PromptEvalCount,
+			CompletionTokens: ollamaResp.EvalCount,
+			TotalTokens:      ollamaResp.PromptEvalCount + ollamaResp.EvalCount,
+		},
+	}
+}
+
+// toolCallsFromOllama 将 Ollama 的工具调用转换为 OpenAI 格式，Ollama 不返回调用 ID，这里自动生成
+func toolCallsFromOllama(toolCalls []OllamaToolCall) []models.ToolCall {
+	var result []models.ToolCall
+	for _, tc := range toolCalls {
+		args, err := json.Marshal(tc.Function.Arguments)
+		if err != nil || tc.Function.Arguments == nil {
+			args = []byte("{}")
+		}
+		result = append(result, models.ToolCall{
+			ID:   newToolCallID(),
+			Type: "function",
+			Function: models.FunctionCall{
+				Name:      tc.Function.Name,
+				Arguments: string(args),
+			},
+		})
+	}
+	return result
+}
+
+// mapOllamaDoneReason 将 Ollama 的 done_reason 映射为 OpenAI 的 finish_reason
+func mapOllamaDoneReason(reason string, hasToolCalls bool) string {
+	if hasToolCalls {
+		return "tool_calls"
+	}
+	switch reason {
+	case "", "stop", "unload":
+		return "stop"
+	default:
+		return reason
+	}
 }
@@ -0,0 +1,94 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// ollamaStreamTranslator 将 Ollama 的 NDJSON 流转换为 OpenAI chat.completion.chunk
+// 每行是一个完整的 JSON 对象，done 为 true 的最后一行携带用量
+type ollamaStreamTranslator struct {
+	decoder   *json.Decoder
+	id        string
+	model     string
+	created   int64
+	started   bool
+	toolCount int
+}
+
+func newOllamaStream(body io.ReadCloser, req *models.ChatCompletionRequest) io.ReadCloser {
+	t := &ollamaStreamTranslator{
+		decoder: json.NewDecoder(body),
+		id:      newResponseID(),
+		model:   req.Model,
+		created: time.Now().Unix(),
+	}
+	return newChunkStream(req, withThinkTags(t.next), body)
+}
+
+func (t *ollamaStreamTranslator) next() ([]*models.ChatCompletionStreamResponse, error) {
+	var resp OllamaResponse
+	if err := t.decoder.Decode(&resp); err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("failed to decode ollama stream chunk: %w", err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("ollama stream error: %s", resp.Error)
+	}
+
+	chunks := make([]*models.ChatCompletionStreamResponse, 0, 2)
+	if !t.started {
+		t.started = true
+		chunks = append(chunks, t.chunk(models.ChatMessageDelta{Role: "assistant"}, ""))
+	}
+
+	// Ollama 的工具调用总是完整返回，一次性发送名称和参数
+	toolCalls := toolCallsFromOllama(resp.Message.ToolCalls)
+	for i := range toolCalls {
+		index := t.toolCount
+		t.toolCount++
+		toolCalls[i].Index = &index
+	}
+
+	finishReason := ""
+	if resp.Done {
+		finishReason = mapOllamaDoneReason(resp.DoneReason, t.toolCount > 0)
+	}
+	if resp.Message.Content != "" || resp.Message.Thinking != "" || len(toolCalls) > 0 || finishReason != "" {
+		chunks = append(chunks, t.chunk(models.ChatMessageDelta{
+			Content:          resp.Message.Content,
+			ReasoningContent: resp.Message.Thinking,
+			ToolCalls:        toolCalls,
+		}, finishReason))
+	}
+
+	if resp.Done {
+		chunks[len(chunks)-1].Usage = &models.Usage{
+			PromptTokens:     resp.PromptEvalCount,
+			CompletionTokens: resp.EvalCount,
+			TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
+		}
+		return chunks, io.EOF
+	}
+	return chunks, nil
+}
+
+func (t *ollamaStreamTranslator) chunk(delta models.ChatMessageDelta, finishReason string) *models.ChatCompletionStreamResponse {
+	return &models.ChatCompletionStreamResponse{
+		ID:      t.id,
+		Object:  "chat.completion.chunk",
+		Created: t.created,
+		Model:   t.model,
+		Choices: []models.ChatCompletionStreamChoice{{
+			Index:        0,
+			Delta:        delta,
+			FinishReason: finishReason,
+		}},
+	}
+}
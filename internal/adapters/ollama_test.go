@@ -0,0 +1,216 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestOllamaAdapter_ChatCompletion(t *testing.T) {
+	var gotReq map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		fmt.Fprint(w, `{"model":"llama3.1","created_at":"2024-07-25T10:00:00Z","message":{"role":"assistant","content":"{\"answer\":42}"},"done":true,"done_reason":"stop","prompt_eval_count":26,"eval_count":8}`)
+	}))
+	defer server.Close()
+
+	temperature, maxTokens, seed := 0.2, 128, 7
+	adapter, _ := NewOllamaAdapter("", server.URL)
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:       "llama3.1",
+		Messages:    []models.ChatMessage{{Role: "user", Content: "What is the answer?"}},
+		Temperature: &temperature,
+		MaxTokens:   &maxTokens,
+		Seed:        &seed,
+		Stop:        []string{"\n\n"},
+		KeepAlive:   "10m",
+		ResponseFormat: &models.ResponseFormat{
+			Type: "json_schema",
+			JSONSchema: &models.JSONSchema{
+				Name: "answer",
+				Schema: map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"answer": map[string]interface{}{"type": "integer"}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	// 请求转换
+	if gotReq["stream"] != false {
+		t.Errorf("expected stream false, got %v", gotReq["stream"])
+	}
+	if gotReq["keep_alive"] != "10m" {
+		t.Errorf("expected keep_alive '10m', got %v", gotReq["keep_alive"])
+	}
+	if format, ok := gotReq["format"].(map[string]interface{}); !ok || format["type"] != "object" {
+		t.Errorf("expected JSON schema format, got %v", gotReq["format"])
+	}
+	options := gotReq["options"].(map[string]interface{})
+	if options["temperature"] != 0.2 || options["num_predict"] != float64(128) || options["seed"] != float64(7) {
+		t.Errorf("unexpected options: %v", options)
+	}
+	if stop, ok := options["stop"].([]interface{}); !ok || len(stop) != 1 {
+		t.Errorf("expected stop in options, got %v", options["stop"])
+	}
+
+	// 响应转换
+	if resp.Choices[0].Message.Content != `{"answer":42}` || resp.Choices[0].FinishReason != "stop" {
+		t.Errorf("unexpected choice: %+v", resp.Choices[0])
+	}
+	if resp.Usage.PromptTokens != 26 || resp.Usage.CompletionTokens != 8 || resp.Usage.TotalTokens != 34 {
+		t.Errorf("unexpected usage: %+v", resp.Usage)
+	}
+}
+
+func TestOllamaAdapter_ToolCalls(t *testing.T) {
+	var gotReq OllamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		fmt.Fprint(w, `{"model":"llama3.1","message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"get_weather","arguments":{"city":"Paris"}}}]},"done":true,"done_reason":"stop","prompt_eval_count":40,"eval_count":12}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewOllamaAdapter("", server.URL)
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "llama3.1",
+		Messages: []models.ChatMessage{
+			{Role: "user", Content: "Weather in Berlin and Paris?"},
+			{Role: "assistant", ToolCalls: []models.ToolCall{{
+				ID:       "call_1",
+				Type:     "function",
+				Function: models.FunctionCall{Name: "get_weather", Arguments: `{"city":"Berlin"}`},
+			}}},
+			{Role: "tool", ToolCallID: "call_1", Content: "20 degrees"},
+		},
+		Tools: []models.Tool{{
+			Type:     "function",
+			Function: models.FunctionDefinition{Name: "get_weather"},
+		}},
+		ResponseFormat: &models.ResponseFormat{Type: "json_object"},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	if len(gotReq.Tools) != 1 || gotReq.Format != "json" {
+		t.Errorf("unexpected tools/format: %+v", gotReq)
+	}
+	if call := gotReq.Messages[1].ToolCalls; len(call) != 1 || call[0].Function.Arguments["city"] != "Berlin" {
+		t.Errorf("expected assistant tool call with object arguments, got %+v", gotReq.Messages[1])
+	}
+	if gotReq.Messages[2].ToolName != "get_weather" {
+		t.Errorf("expected tool_name resolved from tool_call_id, got %q", gotReq.Messages[2].ToolName)
+	}
+
+	choice := resp.Choices[0]
+	if choice.FinishReason != "tool_calls" || len(choice.Message.ToolCalls) != 1 {
+		t.Fatalf("expected one tool call, got %+v", choice)
+	}
+	tc := choice.Message.ToolCalls[0]
+	if !strings.HasPrefix(tc.ID, "call_") || tc.Function.Name != "get_weather" || tc.Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected tool call: %+v", tc)
+	}
+}
+
+func TestOllamaAdapter_ChatCompletionStream(t *testing.T) {
+	lines := []string{
+		`{"model":"llama3.1","created_at":"2024-07-25T10:00:00Z","message":{"role":"assistant","content":"Hello"},"done":false}`,
+		`{"model":"llama3.1","created_at":"2024-07-25T10:00:00Z","message":{"role":"assistant","content":" there"},"done":false}`,
+		`{"model":"llama3.1","created_at":"2024-07-25T10:00:01Z","message":{"role":"assistant","content":""},"done":true,"done_reason":"length","prompt_eval_count":5,"eval_count":2}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, line := range lines {
+			fmt.Fprintln(w, line)
+		}
+	}))
+	defer server.Close()
+
+	adapter, _ := NewOllamaAdapter("", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:         "llama3.1",
+		Messages:      []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		StreamOptions: &models.StreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, done := collectStreamChunks(t, stream)
+	if !done {
+		t.Error("expected stream to end with data: [DONE]")
+	}
+	if len(chunks) != 5 {
+		t.Fatalf("expected 5 chunks, got %d", len(chunks))
+	}
+	if chunks[1].Choices[0].Delta.Content != "Hello" || chunks[2].Choices[0].Delta.Content != " there" {
+		t.Errorf("unexpected deltas")
+	}
+	if chunks[3].Choices[0].FinishReason != "length" {
+		t.Errorf("expected finish_reason 'length', got %q", chunks[3].Choices[0].FinishReason)
+	}
+	if chunks[4].Usage == nil || chunks[4].Usage.TotalTokens != 7 {
+		t.Errorf("expected usage chunk with 7 total tokens, got %+v", chunks[4].Usage)
+	}
+}
+
+func TestOllamaAdapter_ChatCompletionStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"error":"model 'llama9' not found"}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewOllamaAdapter("", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "llama9",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	buf := make([]byte, 1024)
+	if _, err := stream.Read(buf); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected ollama error, got %v", err)
+	}
+}
+
+func TestOllamaAdapter_Reasoning(t *testing.T) {
+	responses := []string{
+		`{"model":"qwen3","message":{"role":"assistant","content":"42","thinking":"Compute."},"done":true,"done_reason":"stop"}`,
+		`{"model":"deepseek-r1","message":{"role":"assistant","content":"<think>Compute.</think>\n\n42"},"done":true,"done_reason":"stop"}`,
+	}
+	for _, body := range responses {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		}))
+
+		adapter, _ := NewOllamaAdapter("", server.URL)
+		resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+			Model:    "deepseek-r1",
+			Messages: []models.ChatMessage{{Role: "user", Content: "What is the answer?"}},
+		})
+		server.Close()
+		if err != nil {
+			t.Fatalf("ChatCompletion() error = %v", err)
+		}
+		if msg := resp.Choices[0].Message; msg.ReasoningContent != "Compute." || msg.Content != "42" {
+			t.Errorf("unexpected message for %s: %+v", body, msg)
+		}
+	}
+}
@@ -2,8 +2,12 @@ package adapters
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 
 	"github.com/gotoailab/llmhub/internal/models"
 
@@ -11,7 +15,8 @@ import (
 )
 
 type OpenAIAdapter struct {
-	client *openai.Client
+	provider Provider
+	client   *openai.Client
 }
 
 // NewOpenAIAdapter 创建 OpenAI 适配器（导出以供注册）
@@ -23,122 +28,23 @@ func NewOpenAIAdapter(apiKey, baseURL string) (Adapter, error) {
 	client := openai.NewClientWithConfig(config)
 
 	return &OpenAIAdapter{
-		client: client,
+		provider: Provider("openai"),
+		client:   client,
 	}, nil
 }
 
 func (a *OpenAIAdapter) GetProvider() Provider {
-	return Provider("openai")
+	return a.provider
 }
 
 func (a *OpenAIAdapter) ChatCompletion(ctx context.Context, req *models.ChatCompletionRequest) (*models.ChatCompletionResponse, error) {
-	// 转换消息格式
-	messages := make([]openai.ChatCompletionMessage, 0, len(req.Messages))
-	for _, msg := range req.Messages {
-		openaiMsg := openai.ChatCompletionMessage{
-			Role: msg.Role,
-			Name: msg.Name,
-		}
-
-		// 处理消息内容
-		switch v := msg.Content.(type) {
-		case string:
-			openaiMsg.Content = v
-		case []interface{}:
-			// 处理多模态内容
-			openaiMsg.Content = fmt.Sprintf("%v", v)
-		default:
-			openaiMsg.Content = fmt.Sprintf("%v", v)
-		}
-
-		// 处理工具调用
-		if len(msg.ToolCalls) > 0 {
-			toolCalls := make([]openai.ToolCall, 0, len(msg.ToolCalls))
-			for _, tc := range msg.ToolCalls {
-				toolCalls = append(toolCalls, openai.ToolCall{
-					ID:   tc.ID,
-					Type: openai.ToolType(tc.Type),
-					Function: openai.FunctionCall{
-						Name:      tc.Function.Name,
-						Arguments: tc.Function.Arguments,
-					},
-				})
-			}
-			openaiMsg.ToolCalls = toolCalls
-		}
-
-		// 处理函数调用（旧格式兼容）
-		if msg.FunctionCall != nil {
-			openaiMsg.FunctionCall = &openai.FunctionCall{
-				Name:      msg.FunctionCall.Name,
-				Arguments: msg.FunctionCall.Arguments,
-			}
-		}
-
-		// 处理工具调用 ID
-		if msg.ToolCallID != "" {
-			openaiMsg.ToolCallID = msg.ToolCallID
-		}
-
-		messages = append(messages, openaiMsg)
-	}
-
-	// 构建请求
-	openaiReq := openai.ChatCompletionRequest{
-		Model:       req.Model,
-		Messages:    messages,
-		Temperature: float32(getFloatValue(req.Temperature)),
-		TopP:        float32(getFloatValue(req.TopP)),
-		MaxTokens:   getIntValue(req.MaxTokens),
-		Stream:      false,
-	}
-
-	if req.Stop != nil && len(req.Stop) > 0 {
-		openaiReq.Stop = req.Stop
-	}
-
-	// 添加工具支持
-	if len(req.Tools) > 0 {
-		tools := make([]openai.Tool, 0, len(req.Tools))
-		for _, tool := range req.Tools {
-			tools = append(tools, openai.Tool{
-				Type: openai.ToolType(tool.Type),
-				Function: &openai.FunctionDefinition{
-					Name:        tool.Function.Name,
-					Description: tool.Function.Description,
-					Parameters:  tool.Function.Parameters,
-				},
-			})
-		}
-		openaiReq.Tools = tools
-
-		// 处理工具选择策略
-		if req.ToolChoice != nil {
-			openaiReq.ToolChoice = req.ToolChoice
-		}
-	}
-
-	// 添加函数支持（旧格式兼容）
-	if len(req.Functions) > 0 {
-		functions := make([]openai.FunctionDefinition, 0, len(req.Functions))
-		for _, fn := range req.Functions {
-			functions = append(functions, openai.FunctionDefinition{
-				Name:        fn.Name,
-				Description: fn.Description,
-				Parameters:  fn.Parameters,
-			})
-		}
-		openaiReq.Functions = functions
-
-		if req.FunctionCall != nil {
-			openaiReq.FunctionCall = req.FunctionCall
-		}
-	}
+	openaiReq := a.convertToOpenAIRequest(req)
+	openaiReq.Stream = false
 
 	// 调用 OpenAI API
 	resp, err := a.client.CreateChatCompletion(ctx, openaiReq)
 	if err != nil {
-		return nil, fmt.Errorf("openai api error: %w", err)
+		return nil, fmt.Errorf("%s api error: %w", a.provider, contentFilterError(err))
 	}
 
 	// 转换响应格式
@@ -178,6 +84,8 @@ func (a *OpenAIAdapter) ChatCompletion(ctx context.Context, req *models.ChatComp
 			Index:        choice.Index,
 			Message:      message,
 			FinishReason: string(choice.FinishReason),
+
+			ContentFilterResults: fromOpenAIContentFilterResults(choice.ContentFilterResults),
 		})
 	}
 
@@ -196,6 +104,59 @@ func (a *OpenAIAdapter) ChatCompletion(ctx context.Context, req *models.ChatComp
 }
 
 func (a *OpenAIAdapter) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
+	openaiReq := a.convertToOpenAIRequest(req)
+	openaiReq.Stream = true
+	if req.StreamOptions != nil && req.StreamOptions.IncludeUsage {
+		openaiReq.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+	}
+
+	stream, err := a.client.CreateChatCompletionStream(ctx, openaiReq)
+	if err != nil {
+		return nil, fmt.Errorf("%s stream error: %w", a.provider, contentFilterError(err))
+	}
+
+	return newOpenAIStreamReader(stream, req), nil
+}
+
+// toOpenAIResponseFormat 转换响应格式，JSON Schema 需要实现 json.Marshaler
+func toOpenAIResponseFormat(rf *models.ResponseFormat) *openai.ChatCompletionResponseFormat {
+	format := &openai.ChatCompletionResponseFormat{
+		Type: openai.ChatCompletionResponseFormatType(rf.Type),
+	}
+	if rf.JSONSchema != nil {
+		schema, _ := json.Marshal(rf.JSONSchema.Schema)
+		format.JSONSchema = &openai.ChatCompletionResponseFormatJSONSchema{
+			Name:        rf.JSONSchema.Name,
+			Description: rf.JSONSchema.Description,
+			Schema:      json.RawMessage(schema),
+			Strict:      rf.JSONSchema.Strict,
+		}
+	}
+	return format
+}
+
+// toOpenAIMessageParts 将多模态内容片段转换为 go-openai 格式
+func toOpenAIMessageParts(parts []models.ContentPart) []openai.ChatMessagePart {
+	result := make([]openai.ChatMessagePart, 0, len(parts))
+	for _, part := range parts {
+		p := openai.ChatMessagePart{
+			Type: openai.ChatMessagePartType(part.Type),
+			Text: part.Text,
+		}
+		if part.ImageURL != nil {
+			p.ImageURL = &openai.ChatMessageImageURL{
+				URL:    part.ImageURL.URL,
+				Detail: openai.ImageURLDetail(part.ImageURL.Detail),
+			}
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// convertToOpenAIRequest 将通用请求转换为 go-openai 请求
+func (a *OpenAIAdapter) convertToOpenAIRequest(req *models.ChatCompletionRequest) openai.ChatCompletionRequest {
+	// 转换消息格式
 	messages := make([]openai.ChatCompletionMessage, 0, len(req.Messages))
 	for _, msg := range req.Messages {
 		openaiMsg := openai.ChatCompletionMessage{
@@ -203,14 +164,21 @@ func (a *OpenAIAdapter) ChatCompletionStream(ctx context.Context, req *models.Ch
 			Name: msg.Name,
 		}
 
+		// 处理消息内容
 		switch v := msg.Content.(type) {
 		case string:
 			openaiMsg.Content = v
+		case nil:
 		default:
-			openaiMsg.Content = fmt.Sprintf("%v", v)
+			// 多模态内容按片段发送
+			if parts, ok := contentParts(v); ok {
+				openaiMsg.MultiContent = toOpenAIMessageParts(parts)
+			} else {
+				openaiMsg.Content = fmt.Sprintf("%v", v)
+			}
 		}
 
-		// 处理工具调用和函数调用（与上面类似的逻辑）
+		// 处理工具调用
 		if len(msg.ToolCalls) > 0 {
 			toolCalls := make([]openai.ToolCall, 0, len(msg.ToolCalls))
 			for _, tc := range msg.ToolCalls {
@@ -226,6 +194,7 @@ func (a *OpenAIAdapter) ChatCompletionStream(ctx context.Context, req *models.Ch
 			openaiMsg.ToolCalls = toolCalls
 		}
 
+		// 处理函数调用（旧格式兼容）
 		if msg.FunctionCall != nil {
 			openaiMsg.FunctionCall = &openai.FunctionCall{
 				Name:      msg.FunctionCall.Name,
@@ -233,6 +202,7 @@ func (a *OpenAIAdapter) ChatCompletionStream(ctx context.Context, req *models.Ch
 			}
 		}
 
+		// 处理工具调用 ID
 		if msg.ToolCallID != "" {
 			openaiMsg.ToolCallID = msg.ToolCallID
 		}
@@ -240,16 +210,33 @@ func (a *OpenAIAdapter) ChatCompletionStream(ctx context.Context, req *models.Ch
 		messages = append(messages, openaiMsg)
 	}
 
+	// 构建请求
 	openaiReq := openai.ChatCompletionRequest{
 		Model:       req.Model,
 		Messages:    messages,
 		Temperature: float32(getFloatValue(req.Temperature)),
 		TopP:        float32(getFloatValue(req.TopP)),
 		MaxTokens:   getIntValue(req.MaxTokens),
-		Stream:      true,
 	}
 
-	// 添加工具和函数支持（与上面相同的逻辑）
+	// 推理模型不接受 max_tokens，统一改用 max_completion_tokens
+	if req.MaxCompletionTokens != nil || isOpenAIReasoningModel(req.Model) {
+		openaiReq.MaxTokens = 0
+		if maxTokens := maxOutputTokens(req); maxTokens != nil {
+			openaiReq.MaxCompletionTokens = *maxTokens
+		}
+	}
+	openaiReq.ReasoningEffort = req.ReasoningEffort
+
+	if len(req.Stop) > 0 {
+		openaiReq.Stop = req.Stop
+	}
+
+	if req.ResponseFormat != nil {
+		openaiReq.ResponseFormat = toOpenAIResponseFormat(req.ResponseFormat)
+	}
+
+	// 添加工具支持
 	if len(req.Tools) > 0 {
 		tools := make([]openai.Tool, 0, len(req.Tools))
 		for _, tool := range req.Tools {
@@ -263,11 +250,14 @@ func (a *OpenAIAdapter) ChatCompletionStream(ctx context.Context, req *models.Ch
 			})
 		}
 		openaiReq.Tools = tools
+
+		// 处理工具选择策略
 		if req.ToolChoice != nil {
 			openaiReq.ToolChoice = req.ToolChoice
 		}
 	}
 
+	// 添加函数支持（旧格式兼容）
 	if len(req.Functions) > 0 {
 		functions := make([]openai.FunctionDefinition, 0, len(req.Functions))
 		for _, fn := range req.Functions {
@@ -278,31 +268,140 @@ func (a *OpenAIAdapter) ChatCompletionStream(ctx context.Context, req *models.Ch
 			})
 		}
 		openaiReq.Functions = functions
+
 		if req.FunctionCall != nil {
 			openaiReq.FunctionCall = req.FunctionCall
 		}
 	}
 
-	stream, err := a.client.CreateChatCompletionStream(ctx, openaiReq)
+	return openaiReq
+}
+
+// OpenAIStreamReader 将 go-openai 的流式响应重新编码为 OpenAI SSE 帧
+type OpenAIStreamReader struct {
+	*chunkStream
+	stream *openai.ChatCompletionStream
+}
+
+func newOpenAIStreamReader(stream *openai.ChatCompletionStream, req *models.ChatCompletionRequest) *OpenAIStreamReader {
+	r := &OpenAIStreamReader{stream: stream}
+	r.chunkStream = newChunkStream(req, r.next, stream)
+	return r
+}
+
+func (r *OpenAIStreamReader) next() ([]*models.ChatCompletionStreamResponse, error) {
+	resp, err := r.stream.Recv()
 	if err != nil {
-		return nil, fmt.Errorf("openai stream error: %w", err)
+		return nil, err
 	}
+	// Azure 会先发送只含 prompt_filter_results 的空块
+	if len(resp.Choices) == 0 && resp.Usage == nil {
+		return nil, nil
+	}
+
+	choices := make([]models.ChatCompletionStreamChoice, 0, len(resp.Choices))
+	for _, choice := range resp.Choices {
+		delta := models.ChatMessageDelta{
+			Role:    choice.Delta.Role,
+			Content: choice.Delta.Content,
+		}
+
+		// 工具调用增量，保留 index 以便客户端拼接参数片段
+		for _, tc := range choice.Delta.ToolCalls {
+			delta.ToolCalls = append(delta.ToolCalls, models.ToolCall{
+				Index: tc.Index,
+				ID:    tc.ID,
+				Type:  string(tc.Type),
+				Function: models.FunctionCall{
+					Name:      tc.Function.Name,
+					Arguments: tc.Function.Arguments,
+				},
+			})
+		}
+
+		if choice.Delta.FunctionCall != nil {
+			delta.FunctionCall = &models.FunctionCall{
+				Name:      choice.Delta.FunctionCall.Name,
+				Arguments: choice.Delta.FunctionCall.Arguments,
+			}
+		}
+
+		choices = append(choices, models.ChatCompletionStreamChoice{
+			Index:        choice.Index,
+			Delta:        delta,
+			FinishReason: string(choice.FinishReason),
 
-	return &OpenAIStreamReader{stream: stream}, nil
+			ContentFilterResults: fromOpenAIContentFilterResults(choice.ContentFilterResults),
+		})
+	}
+
+	chunk := &models.ChatCompletionStreamResponse{
+		ID:                resp.ID,
+		Object:            resp.Object,
+		Created:           resp.Created,
+		Model:             resp.Model,
+		Choices:           choices,
+		SystemFingerprint: resp.SystemFingerprint,
+	}
+	if resp.Usage != nil {
+		chunk.Usage = &models.Usage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		}
+	}
+
+	return []*models.ChatCompletionStreamResponse{chunk}, nil
 }
 
-type OpenAIStreamReader struct {
-	stream *openai.ChatCompletionStream
+// fromOpenAIContentFilterResults 转换 Azure 的内容过滤结果，只保留实际返回了结果的类别
+func fromOpenAIContentFilterResults(r openai.ContentFilterResults) map[string]models.ContentFilterResult {
+	results := map[string]models.ContentFilterResult{
+		"hate":      {Filtered: r.Hate.Filtered, Severity: r.Hate.Severity},
+		"self_harm": {Filtered: r.SelfHarm.Filtered, Severity: r.SelfHarm.Severity},
+		"sexual":    {Filtered: r.Sexual.Filtered, Severity: r.Sexual.Severity},
+		"violence":  {Filtered: r.Violence.Filtered, Severity: r.Violence.Severity},
+		"jailbreak": {Filtered: r.JailBreak.Filtered, Detected: r.JailBreak.Detected},
+		"profanity": {Filtered: r.Profanity.Filtered, Detected: r.Profanity.Detected},
+	}
+	for category, result := range results {
+		if !result.Filtered && result.Severity == "" && !result.Detected {
+			delete(results, category)
+		}
+	}
+	if len(results) == 0 {
+		return nil
+	}
+	return results
 }
 
-func (r *OpenAIStreamReader) Read(p []byte) (n int, err error) {
-	// 这里需要将流式响应转换为 SSE 格式
-	// 简化实现，实际应该处理 SSE 格式
-	return 0, fmt.Errorf("stream reading not fully implemented")
+// contentFilterError 为 Azure 因内容过滤拒绝的请求补充被过滤的类别，其他错误原样返回
+func contentFilterError(err error) error {
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.InnerError == nil {
+		return err
+	}
+	var filtered []string
+	for category, result := range fromOpenAIContentFilterResults(apiErr.InnerError.ContentFilterResults) {
+		if result.Filtered {
+			filtered = append(filtered, category)
+		}
+	}
+	if len(filtered) == 0 {
+		return err
+	}
+	sort.Strings(filtered)
+	return fmt.Errorf("prompt blocked by content filter (%s): %w", strings.Join(filtered, ", "), err)
 }
 
-func (r *OpenAIStreamReader) Close() error {
-	return r.stream.Close()
+// isOpenAIReasoningModel 判断是否为 o 系列、gpt-5 等推理模型
+func isOpenAIReasoningModel(model string) bool {
+	for _, prefix := range []string{"o1", "o3", "o4", "gpt-5"} {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
 }
 
 // 辅助函数
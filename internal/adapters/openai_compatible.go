@@ -21,6 +21,14 @@ type openAICompatibleAdapter struct {
 	endpoint      string // API 端点路径，默认为 /chat/completions
 	authHeader    string // 认证头格式，默认为 "Bearer"
 	supportsTools bool   // 是否支持工具调用
+
+	authHeaderName        string            // 认证头名称，默认为 Authorization
+	headers               map[string]string // 每个请求附带的额外请求头
+	supportsVision        bool              // 是否接受图片内容片段
+	supportsStreamOptions bool              // 是否转发 stream_options
+
+	// 是否转发 reasoning_effort，严格校验参数的接口收到未知字段会报错
+	supportsReasoningEffort bool
 }
 
 // NewOpenAICompatibleAdapter 创建通用的 OpenAI 兼容适配器
@@ -48,6 +56,10 @@ func NewOpenAICompatibleAdapter(provider Provider, apiKey, baseURL, endpoint, au
 		client: &http.Client{
 			Timeout: 60 * time.Second,
 		},
+		authHeaderName:          "Authorization",
+		supportsVision:          true,
+		supportsStreamOptions:   isProviderSupportsStreamOptions(provider),
+		supportsReasoningEffort: isProviderSupportsReasoningEffort(provider),
 	}
 }
 
@@ -56,12 +68,11 @@ func (a *openAICompatibleAdapter) GetProvider() Provider {
 }
 
 func (a *openAICompatibleAdapter) ChatCompletion(ctx context.Context, req *models.ChatCompletionRequest) (*models.ChatCompletionResponse, error) {
-	// 检查工具调用支持
-	if (len(req.Tools) > 0 || len(req.Functions) > 0) && !a.supportsTools {
-		return nil, fmt.Errorf("tool use not supported for provider %s", a.provider)
+	if err := a.checkCapabilities(req); err != nil {
+		return nil, err
 	}
 
-	openaiReq := convertToOpenAIFormatGeneric(req)
+	openaiReq := a.convertRequest(req)
 
 	reqBody, err := json.Marshal(openaiReq)
 	if err != nil {
@@ -73,14 +84,7 @@ func (a *openAICompatibleAdapter) ChatCompletion(ctx context.Context, req *model
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	if a.apiKey != "" {
-		if a.authHeader == "Bearer" {
-			httpReq.Header.Set("Authorization", "Bearer "+a.apiKey)
-		} else {
-			httpReq.Header.Set("Authorization", a.apiKey)
-		}
-	}
+	a.setHeaders(httpReq)
 
 	resp, err := a.client.Do(httpReq)
 	if err != nil {
@@ -102,13 +106,15 @@ func (a *openAICompatibleAdapter) ChatCompletion(ctx context.Context, req *model
 }
 
 func (a *openAICompatibleAdapter) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
-	// 检查工具调用支持
-	if (len(req.Tools) > 0 || len(req.Functions) > 0) && !a.supportsTools {
-		return nil, fmt.Errorf("tool use not supported for provider %s", a.provider)
+	if err := a.checkCapabilities(req); err != nil {
+		return nil, err
 	}
 
-	openaiReq := convertToOpenAIFormatGeneric(req)
+	openaiReq := a.convertRequest(req)
 	openaiReq["stream"] = true
+	if req.StreamOptions != nil && a.supportsStreamOptions {
+		openaiReq["stream_options"] = req.StreamOptions
+	}
 
 	reqBody, err := json.Marshal(openaiReq)
 	if err != nil {
@@ -120,14 +126,7 @@ func (a *openAICompatibleAdapter) ChatCompletionStream(ctx context.Context, req
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	if a.apiKey != "" {
-		if a.authHeader == "Bearer" {
-			httpReq.Header.Set("Authorization", "Bearer "+a.apiKey)
-		} else {
-			httpReq.Header.Set("Authorization", a.apiKey)
-		}
-	}
+	a.setHeaders(httpReq)
 
 	resp, err := a.client.Do(httpReq)
 	if err != nil {
@@ -139,35 +138,128 @@ func (a *openAICompatibleAdapter) ChatCompletionStream(ctx context.Context, req
 		return nil, fmt.Errorf("%s stream error: status %d", a.provider, resp.StatusCode)
 	}
 
-	return resp.Body, nil
+	return newOpenAICompatibleStream(resp.Body, req), nil
+}
+
+// convertRequest 转换为 OpenAI 格式，只转发提供商支持的可选参数
+func (a *openAICompatibleAdapter) convertRequest(req *models.ChatCompletionRequest) map[string]interface{} {
+	openaiReq := convertToOpenAIFormatGeneric(req)
+	if req.ReasoningEffort != "" && a.supportsReasoningEffort {
+		openaiReq["reasoning_effort"] = req.ReasoningEffort
+	}
+	return openaiReq
+}
+
+// checkCapabilities 检查请求是否用到了提供商不支持的能力
+func (a *openAICompatibleAdapter) checkCapabilities(req *models.ChatCompletionRequest) error {
+	if (len(req.Tools) > 0 || len(req.Functions) > 0) && !a.supportsTools {
+		return fmt.Errorf("tool use not supported for provider %s", a.provider)
+	}
+	if !a.supportsVision && hasContentPart(req.Messages, "image_url") {
+		return fmt.Errorf("image input not supported for provider %s", a.provider)
+	}
+	return nil
+}
+
+// setHeaders 设置内容类型、认证头和额外请求头
+func (a *openAICompatibleAdapter) setHeaders(httpReq *http.Request) {
+	httpReq.Header.Set("Content-Type", "application/json")
+	for name, value := range a.headers {
+		httpReq.Header.Set(name, value)
+	}
+	if a.apiKey != "" {
+		if a.authHeader == "Bearer" {
+			httpReq.Header.Set(a.authHeaderName, "Bearer "+a.apiKey)
+		} else if a.authHeader != "" {
+			httpReq.Header.Set(a.authHeaderName, a.authHeader+" "+a.apiKey)
+		} else {
+			httpReq.Header.Set(a.authHeaderName, a.apiKey)
+		}
+	}
+}
+
+// newOpenAICompatibleStream 解析 OpenAI 兼容的上游 SSE，统一经 chunkStream 输出，
+// 以便不支持 stream_options 的提供商也能得到（估算的）用量
+func newOpenAICompatibleStream(body io.ReadCloser, req *models.ChatCompletionRequest) io.ReadCloser {
+	return newChunkStream(req, openAICompatibleStreamNext(body), body)
+}
+
+// openAICompatibleStreamNext 逐个解析上游 SSE 中的 chat.completion.chunk
+func openAICompatibleStreamNext(body io.Reader) func() ([]*models.ChatCompletionStreamResponse, error) {
+	reader := NewSSEReader(body)
+	return func() ([]*models.ChatCompletionStreamResponse, error) {
+		event, err := reader.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		data := bytes.TrimSpace(event.Data)
+		if len(data) == 0 {
+			return nil, nil
+		}
+		if string(data) == SSEDone {
+			return nil, io.EOF
+		}
+
+		var chunk models.ChatCompletionStreamResponse
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			return nil, fmt.Errorf("failed to decode stream chunk: %w", err)
+		}
+		if chunk.Error != nil {
+			return nil, fmt.Errorf("stream error: %s", chunk.Error.Message)
+		}
+		return []*models.ChatCompletionStreamResponse{&chunk}, nil
+	}
+}
+
+// 判断提供商的兼容接口是否接受 stream_options 参数
+// 不转发时由 chunkStream 估算用量，因此只对明确支持的提供商开启
+func isProviderSupportsStreamOptions(provider Provider) bool {
+	switch provider {
+	case "groq", "xai":
+		return true
+	}
+	return false
+}
+
+// 判断提供商的兼容接口是否接受 reasoning_effort 参数
+func isProviderSupportsReasoningEffort(provider Provider) bool {
+	switch provider {
+	case "groq", "xai":
+		return true
+	}
+	return false
 }
 
 // 判断提供商是否支持工具调用
 func isProviderSupportsTools(provider Provider) bool {
 	supportedProviders := map[Provider]bool{
-		"openrouter":   true,
-		"groq":         true,
-		"together":     true,
-		"deepseek":     true,
-		"siliconflow":  true,
-		"moonshot":     true,
-		"stepfun":      true,
-		"mistral":      true,
-		"cohere":       true,
-		"qwen":         false, // 通义千问暂不支持标准工具调用
-		"baichuan":     false,
-		"chatglm":      false,
-		"ernie":        false,
-		"spark":        false,
-		"hunyuan":      false,
-		"360":          false,
-		"minimax":      false,
-		"yi":           false,
-		"doubao":       false,
-		"novita":       true,
-		"xai":          true,
-		"ollama":       false, // Ollama 需要特殊处理
-		"coze":         false,
+		"openai":      true,
+		"claude":      true,
+		"gemini":      true,
+		"openrouter":  true,
+		"groq":        true,
+		"together":    true,
+		"deepseek":    true,
+		"siliconflow": true,
+		"moonshot":    true,
+		"stepfun":     true,
+		"mistral":     true,
+		"cohere":      true,
+		"qwen":        true, // 通义千问通过 DashScope result_format=message 支持
+		"baichuan":    false,
+		"chatglm":     false,
+		"ernie":       false,
+		"spark":       false,
+		"hunyuan":     false,
+		"360":         false,
+		"minimax":     false,
+		"yi":          false,
+		"doubao":      false,
+		"novita":      true,
+		"xai":         true,
+		"ollama":      true, // 使用原生 /api/chat 接口
+		"coze":        false,
 	}
 
 	supported, exists := supportedProviders[provider]
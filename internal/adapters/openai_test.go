@@ -0,0 +1,196 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestOpenAIAdapter_ChatCompletionStream(t *testing.T) {
+	upstream := []string{
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"gpt-4","choices":[{"index":0,"delta":{"role":"assistant","content":""},"finish_reason":null}]}`,
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"gpt-4","choices":[{"index":0,"delta":{"content":"Hello"},"finish_reason":null}]}`,
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"gpt-4","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}]},"finish_reason":null}]}`,
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"gpt-4","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":\"Paris\"}"}}]},"finish_reason":null}]}`,
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"gpt-4","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+	}
+
+	var gotBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&gotBody)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range upstream {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	adapter, err := NewOpenAIAdapter("test-key", server.URL)
+	if err != nil {
+		t.Fatalf("NewOpenAIAdapter() error = %v", err)
+	}
+
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "gpt-4",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Weather in Paris?"}},
+		Stop:     []string{"END"},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	if gotBody["stream"] != true {
+		t.Errorf("expected stream=true in upstream request, got %v", gotBody["stream"])
+	}
+	if _, ok := gotBody["stop"]; !ok {
+		t.Error("expected stop in upstream request")
+	}
+
+	chunks, done := collectStreamChunks(t, stream)
+	if !done {
+		t.Error("expected stream to end with data: [DONE]")
+	}
+	if len(chunks) != len(upstream) {
+		t.Fatalf("expected %d chunks, got %d", len(upstream), len(chunks))
+	}
+	if chunks[0].Choices[0].Delta.Role != "assistant" {
+		t.Errorf("expected role 'assistant', got %q", chunks[0].Choices[0].Delta.Role)
+	}
+	if chunks[1].Choices[0].Delta.Content != "Hello" {
+		t.Errorf("expected content 'Hello', got %q", chunks[1].Choices[0].Delta.Content)
+	}
+
+	first := chunks[2].Choices[0].Delta.ToolCalls
+	if len(first) != 1 || first[0].ID != "call_1" || first[0].Function.Name != "get_weather" {
+		t.Errorf("unexpected first tool call delta: %+v", first)
+	}
+	second := chunks[3].Choices[0].Delta.ToolCalls
+	if len(second) != 1 || second[0].Index == nil || *second[0].Index != 0 || second[0].Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("unexpected second tool call delta: %+v", second)
+	}
+	if chunks[4].Choices[0].FinishReason != "tool_calls" {
+		t.Errorf("expected finish_reason 'tool_calls', got %q", chunks[4].Choices[0].FinishReason)
+	}
+}
+
+func TestOpenAIAdapter_ChatCompletionStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"message":"invalid api key","type":"invalid_request_error"}}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewOpenAIAdapter("bad-key", server.URL)
+	_, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "gpt-4",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid api key") {
+		t.Errorf("expected upstream error, got %v", err)
+	}
+}
+
+func TestOpenAIAdapter_ReasoningModel(t *testing.T) {
+	var gotBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		fmt.Fprint(w, `{"id":"chatcmpl-2","object":"chat.completion","model":"o3-mini","choices":[{"index":0,"message":{"role":"assistant","content":"42"},"finish_reason":"stop"}]}`)
+	}))
+	defer server.Close()
+
+	maxTokens := 2000
+	adapter, _ := NewOpenAIAdapter("test-key", server.URL)
+	_, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "o3-mini",
+		Messages: []models.ChatMessage{
+			{Role: "developer", Content: "Answer with a number."},
+			{Role: "user", Content: "6 * 7?"},
+		},
+		MaxTokens:       &maxTokens,
+		ReasoningEffort: "high",
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	// 推理模型的 max_tokens 改为 max_completion_tokens 发送
+	if _, ok := gotBody["max_tokens"]; ok {
+		t.Errorf("expected no max_tokens for reasoning model, got %v", gotBody["max_tokens"])
+	}
+	if gotBody["max_completion_tokens"] != float64(2000) || gotBody["reasoning_effort"] != "high" {
+		t.Errorf("unexpected request: %v", gotBody)
+	}
+	if msg := gotBody["messages"].([]interface{})[0].(map[string]interface{}); msg["role"] != "developer" {
+		t.Errorf("expected developer role passed through, got %v", msg["role"])
+	}
+}
+
+func TestOpenAIAdapter_ImageContent(t *testing.T) {
+	var gotBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		fmt.Fprint(w, `{"id":"chatcmpl-3","object":"chat.completion","model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"A cat."},"finish_reason":"stop"}]}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewOpenAIAdapter("test-key", server.URL)
+	_, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "gpt-4o",
+		Messages: []models.ChatMessage{{Role: "user", Content: []models.ContentPart{
+			{Type: "text", Text: "What is this?"},
+			{Type: "image_url", ImageURL: &models.ImageURL{URL: "https://example.com/cat.jpg", Detail: "high"}},
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	content, ok := gotBody["messages"].([]interface{})[0].(map[string]interface{})["content"].([]interface{})
+	if !ok || len(content) != 2 {
+		t.Fatalf("expected content parts, got %v", gotBody["messages"])
+	}
+	image := content[1].(map[string]interface{})["image_url"].(map[string]interface{})
+	if image["url"] != "https://example.com/cat.jpg" || image["detail"] != "high" {
+		t.Errorf("unexpected image part: %v", image)
+	}
+}
+
+// collectStreamChunks 读取 OpenAI SSE 流中的所有响应块，并返回是否以 [DONE] 结束
+func collectStreamChunks(t *testing.T, r io.Reader) ([]models.ChatCompletionStreamResponse, bool) {
+	t.Helper()
+
+	var chunks []models.ChatCompletionStreamResponse
+	var done bool
+	reader := NewSSEReader(r)
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			return chunks, done
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		if string(event.Data) == SSEDone {
+			done = true
+			continue
+		}
+		var chunk models.ChatCompletionStreamResponse
+		if err := json.Unmarshal(event.Data, &chunk); err != nil {
+			t.Fatalf("invalid chunk %q: %v", event.Data, err)
+		}
+		chunks = append(chunks, chunk)
+	}
+}
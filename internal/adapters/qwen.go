@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gotoailab/llmhub/internal/models"
@@ -38,20 +39,18 @@ func (a *QwenAdapter) GetProvider() Provider {
 }
 
 func (a *QwenAdapter) ChatCompletion(ctx context.Context, req *models.ChatCompletionRequest) (*models.ChatCompletionResponse, error) {
-	// 检查工具调用 - Qwen 暂不支持标准的 OpenAI 工具调用格式
-	if len(req.Tools) > 0 || len(req.Functions) > 0 {
-		return nil, fmt.Errorf("tool use not supported for Qwen models")
+	// 转换为 DashScope 原生格式，使用工具时以 message 格式返回
+	qwenReq, err := a.convertToOpenAIFormat(req, false)
+	if err != nil {
+		return nil, err
 	}
 
-	// Qwen 使用 OpenAI 兼容的 API
-	qwenReq := a.convertToOpenAIFormat(req)
-
 	reqBody, err := json.Marshal(qwenReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", a.baseURL+"/services/aigc/text-generation/generation", bytes.NewBuffer(reqBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", a.baseURL+qwenEndpoint(req), bytes.NewBuffer(reqBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -81,12 +80,10 @@ func (a *QwenAdapter) ChatCompletion(ctx context.Context, req *models.ChatComple
 }
 
 func (a *QwenAdapter) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
-	// 检查工具调用 - Qwen 暂不支持标准的 OpenAI 工具调用格式
-	if len(req.Tools) > 0 || len(req.Functions) > 0 {
-		return nil, fmt.Errorf("tool use not supported for Qwen models")
+	qwenReq, err := a.convertToOpenAIFormat(req, true)
+	if err != nil {
+		return nil, err
 	}
-
-	qwenReq := a.convertToOpenAIFormat(req)
 	qwenReq["stream"] = true
 
 	reqBody, err := json.Marshal(qwenReq)
@@ -94,7 +91,7 @@ func (a *QwenAdapter) ChatCompletionStream(ctx context.Context, req *models.Chat
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", a.baseURL+"/services/aigc/text-generation/generation", bytes.NewBuffer(reqBody))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", a.baseURL+qwenEndpoint(req), bytes.NewBuffer(reqBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -113,18 +110,56 @@ func (a *QwenAdapter) ChatCompletionStream(ctx context.Context, req *models.Chat
 		return nil, fmt.Errorf("qwen stream error: status %d", resp.StatusCode)
 	}
 
-	return resp.Body, nil
+	return newQwenStream(resp.Body, req), nil
 }
 
 type QwenResponse struct {
 	RequestID string     `json:"request_id"`
 	Output    QwenOutput `json:"output"`
 	Usage     QwenUsage  `json:"usage"`
+	Code      string     `json:"code,omitempty"`
+	Message   string     `json:"message,omitempty"`
 }
 
 type QwenOutput struct {
-	Text         string `json:"text"`
-	FinishReason string `json:"finish_reason"`
+	Text         string       `json:"text"`
+	FinishReason string       `json:"finish_reason"`
+	Choices      []QwenChoice `json:"choices,omitempty"` // result_format 为 message 时返回
+}
+
+type QwenChoice struct {
+	FinishReason string      `json:"finish_reason"`
+	Message      QwenMessage `json:"message"`
+}
+
+type QwenMessage struct {
+	Role             string            `json:"role"`
+	Content          QwenContent       `json:"content"`
+	ReasoningContent string            `json:"reasoning_content,omitempty"`
+	ToolCalls        []models.ToolCall `json:"tool_calls,omitempty"`
+}
+
+// QwenContent 消息内容，多模态接口返回 [{"text": "..."}] 形式的数组，解码时合并为文本
+type QwenContent string
+
+func (c *QwenContent) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*c = QwenContent(text)
+		return nil
+	}
+	var parts []struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return fmt.Errorf("failed to decode qwen message content: %w", err)
+	}
+	var b strings.Builder
+	for _, part := range parts {
+		b.WriteString(part.Text)
+	}
+	*c = QwenContent(b.String())
+	return nil
 }
 
 type QwenUsage struct {
@@ -133,20 +168,45 @@ type QwenUsage struct {
 	TotalTokens  int `json:"total_tokens"`
 }
 
-func (a *QwenAdapter) convertToOpenAIFormat(req *models.ChatCompletionRequest) map[string]interface{} {
+// convertToOpenAIFormat 转换为 DashScope 原生请求，stream 表示是否为流式调用
+func (a *QwenAdapter) convertToOpenAIFormat(req *models.ChatCompletionRequest, stream bool) (map[string]interface{}, error) {
+	multimodal := hasContentPart(req.Messages, "image_url")
 	messages := make([]map[string]interface{}, 0, len(req.Messages))
 	for _, msg := range req.Messages {
 		msgMap := map[string]interface{}{
-			"role": msg.Role,
+			"role": nativeRole(msg.Role),
 		}
 
-		switch v := msg.Content.(type) {
-		case string:
-			msgMap["content"] = v
-		case []interface{}:
-			msgMap["content"] = v
-		default:
-			msgMap["content"] = fmt.Sprintf("%v", v)
+		if multimodal {
+			content, err := toDashScopeMultimodalContent(msg.Content)
+			if err != nil {
+				return nil, err
+			}
+			msgMap["content"] = content
+		} else {
+			msgMap["content"] = contentToText(msg.Content)
+		}
+
+		// 工具调用及其结果按 OpenAI 格式原样传递
+		if len(msg.ToolCalls) > 0 {
+			msgMap["tool_calls"] = msg.ToolCalls
+		}
+		if msg.FunctionCall != nil {
+			msgMap["tool_calls"] = []models.ToolCall{{
+				ID:       newToolCallID(),
+				Type:     "function",
+				Function: *msg.FunctionCall,
+			}}
+		}
+		if msg.ToolCallID != "" {
+			msgMap["tool_call_id"] = msg.ToolCallID
+		}
+		if msg.Name != "" {
+			msgMap["name"] = msg.Name
+		}
+		// 旧版 function 角色的消息转换为 tool 角色
+		if msg.Role == "function" {
+			msgMap["role"] = "tool"
 		}
 
 		messages = append(messages, msgMap)
@@ -159,40 +219,140 @@ func (a *QwenAdapter) convertToOpenAIFormat(req *models.ChatCompletionRequest) m
 		},
 	}
 
+	params := make(map[string]interface{})
 	if req.Temperature != nil {
-		result["parameters"] = map[string]interface{}{
-			"temperature": *req.Temperature,
-		}
+		params["temperature"] = *req.Temperature
 	}
 	if req.TopP != nil {
-		if params, ok := result["parameters"].(map[string]interface{}); ok {
-			params["top_p"] = *req.TopP
-		} else {
-			result["parameters"] = map[string]interface{}{
-				"top_p": *req.TopP,
-			}
+		params["top_p"] = *req.TopP
+	}
+	if maxTokens := maxOutputTokens(req); maxTokens != nil {
+		params["max_tokens"] = *maxTokens
+	}
+	// Qwen3 等混合思考模型通过 enable_thinking 开关思考，thinking_budget 限制思考长度
+	// DashScope 只允许流式调用开启思考，非流式调用时关闭
+	thinking := false
+	if req.ReasoningEffort != "" {
+		budget, err := reasoningBudget(req.ReasoningEffort)
+		if err != nil {
+			return nil, err
+		}
+		thinking = stream && budget > 0
+		params["enable_thinking"] = thinking
+		if thinking {
+			params["thinking_budget"] = budget
 		}
 	}
-	if req.MaxTokens != nil {
-		if params, ok := result["parameters"].(map[string]interface{}); ok {
-			params["max_tokens"] = *req.MaxTokens
-		} else {
-			result["parameters"] = map[string]interface{}{
-				"max_tokens": *req.MaxTokens,
+
+	// 工具调用和思考内容（reasoning_content）都需要使用 message 格式的返回结果
+	tools := make([]models.Tool, 0, len(req.Tools)+len(req.Functions))
+	tools = append(tools, req.Tools...)
+	// 兼容旧的 Functions 格式
+	for _, fn := range req.Functions {
+		tools = append(tools, models.Tool{Type: "function", Function: fn})
+	}
+	if len(tools) > 0 || thinking || qwenHasToolMessages(req.Messages) {
+		params["result_format"] = "message"
+	}
+	if len(tools) > 0 {
+		params["tools"] = tools
+
+		if req.ToolChoice != nil {
+			params["tool_choice"] = req.ToolChoice
+		} else if req.FunctionCall != nil {
+			params["tool_choice"] = toQwenToolChoice(req.FunctionCall)
+		}
+	}
+
+	// 流式调用要求每个事件只返回新增内容
+	if stream {
+		params["incremental_output"] = true
+	}
+
+	if len(params) > 0 {
+		result["parameters"] = params
+	}
+
+	return result, nil
+}
+
+// qwenEndpoint 返回请求路径，包含图片时使用多模态接口（qwen-vl 等视觉模型）
+func qwenEndpoint(req *models.ChatCompletionRequest) string {
+	if hasContentPart(req.Messages, "image_url") {
+		return "/services/aigc/multimodal-generation/generation"
+	}
+	return "/services/aigc/text-generation/generation"
+}
+
+// toDashScopeMultimodalContent 将消息内容转换为多模态接口的 [{"text": ...}, {"image": ...}] 格式
+// 图片支持 http(s) 地址和 base64 data URI
+func toDashScopeMultimodalContent(content interface{}) ([]map[string]string, error) {
+	parts, ok := contentParts(content)
+	if !ok {
+		return []map[string]string{{"text": contentToText(content)}}, nil
+	}
+	result := make([]map[string]string, 0, len(parts))
+	for _, part := range parts {
+		switch part.Type {
+		case "text":
+			result = append(result, map[string]string{"text": part.Text})
+		case "image_url":
+			if part.ImageURL == nil {
+				return nil, fmt.Errorf("image_url content part requires a url")
 			}
+			result = append(result, map[string]string{"image": part.ImageURL.URL})
+		default:
+			return nil, fmt.Errorf("unsupported content part type %q", part.Type)
 		}
 	}
+	return result, nil
+}
 
-	return result
+// qwenHasToolMessages 判断对话中是否包含工具调用或工具结果
+func qwenHasToolMessages(messages []models.ChatMessage) bool {
+	for _, msg := range messages {
+		if len(msg.ToolCalls) > 0 || msg.FunctionCall != nil || msg.Role == "tool" || msg.Role == "function" {
+			return true
+		}
+	}
+	return false
+}
+
+// toQwenToolChoice 将旧版 function_call 转换为 tool_choice
+func toQwenToolChoice(functionCall interface{}) interface{} {
+	if v, ok := functionCall.(map[string]interface{}); ok {
+		if name, ok := v["name"].(string); ok {
+			return map[string]interface{}{
+				"type":     "function",
+				"function": map[string]interface{}{"name": name},
+			}
+		}
+	}
+	return functionCall
 }
 
 func (a *QwenAdapter) convertFromQwenResponse(qwenResp *QwenResponse, modelName string) *models.ChatCompletionResponse {
-	return &models.ChatCompletionResponse{
-		ID:      qwenResp.RequestID,
-		Object:  "chat.completion",
-		Created: time.Now().Unix(),
-		Model:   modelName,
-		Choices: []models.ChatCompletionChoice{
+	var choices []models.ChatCompletionChoice
+	if len(qwenResp.Output.Choices) > 0 {
+		// result_format 为 message
+		for i, choice := range qwenResp.Output.Choices {
+			finishReason := mapQwenFinishReason(choice.FinishReason)
+			if len(choice.Message.ToolCalls) > 0 && (finishReason == "" || finishReason == "stop") {
+				finishReason = "tool_calls"
+			}
+			choices = append(choices, models.ChatCompletionChoice{
+				Index: i,
+				Message: models.ChatMessage{
+					Role:             "assistant",
+					Content:          string(choice.Message.Content),
+					ReasoningContent: choice.Message.ReasoningContent,
+					ToolCalls:        choice.Message.ToolCalls,
+				},
+				FinishReason: finishReason,
+			})
+		}
+	} else {
+		choices = []models.ChatCompletionChoice{
 			{
 				Index: 0,
 				Message: models.ChatMessage{
@@ -201,7 +361,15 @@ func (a *QwenAdapter) convertFromQwenResponse(qwenResp *QwenResponse, modelName
 				},
 				FinishReason: qwenResp.Output.FinishReason,
 			},
-		},
+		}
+	}
+
+	return &models.ChatCompletionResponse{
+		ID:      qwenResp.RequestID,
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   modelName,
+		Choices: choices,
 		Usage: models.Usage{
 			PromptTokens:     qwenResp.Usage.InputTokens,
 			CompletionTokens: qwenResp.Usage.OutputTokens,
@@ -0,0 +1,139 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// qwenStreamTranslator 将 DashScope SSE 转换为 OpenAI chat.completion.chunk
+// 请求开启了 incremental_output，每个事件只携带新增的内容
+type qwenStreamTranslator struct {
+	reader  *SSEReader
+	id      string
+	model   string
+	created int64
+	started bool
+
+	// 已开始输出的工具调用，按 index 记录
+	toolStarted map[int]bool
+}
+
+func newQwenStream(body io.ReadCloser, req *models.ChatCompletionRequest) io.ReadCloser {
+	t := &qwenStreamTranslator{
+		reader:      NewSSEReader(body),
+		model:       req.Model,
+		created:     time.Now().Unix(),
+		toolStarted: make(map[int]bool),
+	}
+	return newChunkStream(req, t.next, body)
+}
+
+func (t *qwenStreamTranslator) next() ([]*models.ChatCompletionStreamResponse, error) {
+	sse, err := t.reader.Next()
+	if err != nil {
+		return nil, err
+	}
+	if len(sse.Data) == 0 {
+		return nil, nil
+	}
+
+	var resp QwenResponse
+	if err := json.Unmarshal(sse.Data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode qwen stream event: %w", err)
+	}
+	if sse.Event == "error" || resp.Code != "" {
+		return nil, fmt.Errorf("qwen stream error: %s: %s", resp.Code, resp.Message)
+	}
+	if t.id == "" {
+		t.id = resp.RequestID
+	}
+
+	chunks := make([]*models.ChatCompletionStreamResponse, 0, 2)
+	if !t.started {
+		t.started = true
+		chunks = append(chunks, t.chunk(models.ChatMessageDelta{Role: "assistant"}, ""))
+	}
+
+	delta, finishReason := resp.Output.Text, resp.Output.FinishReason
+	var reasoningDelta string
+	var toolCalls []models.ToolCall
+	if len(resp.Output.Choices) > 0 {
+		// result_format 为 message
+		choice := resp.Output.Choices[0]
+		delta, finishReason = string(choice.Message.Content), choice.FinishReason
+		reasoningDelta = choice.Message.ReasoningContent
+		toolCalls = t.toolDeltas(choice.Message.ToolCalls)
+	}
+
+	finishReason = mapQwenFinishReason(finishReason)
+	if delta != "" || reasoningDelta != "" || len(toolCalls) > 0 || finishReason != "" {
+		chunks = append(chunks, t.chunk(models.ChatMessageDelta{
+			Content:          delta,
+			ReasoningContent: reasoningDelta,
+			ToolCalls:        toolCalls,
+		}, finishReason))
+	}
+
+	// DashScope 每个事件都携带截至当前的累计用量
+	if resp.Usage.TotalTokens > 0 || resp.Usage.InputTokens > 0 {
+		chunks[len(chunks)-1].Usage = &models.Usage{
+			PromptTokens:     resp.Usage.InputTokens,
+			CompletionTokens: resp.Usage.OutputTokens,
+			TotalTokens:      resp.Usage.TotalTokens,
+		}
+	}
+
+	return chunks, nil
+}
+
+// toolDeltas 为工具调用片段补充 index，ID 和函数名只在第一个片段中发送
+func (t *qwenStreamTranslator) toolDeltas(toolCalls []models.ToolCall) []models.ToolCall {
+	deltas := make([]models.ToolCall, 0, len(toolCalls))
+	for i, tc := range toolCalls {
+		index := i
+		if tc.Index != nil {
+			index = *tc.Index
+		}
+
+		delta := models.ToolCall{
+			Index:    &index,
+			Function: models.FunctionCall{Arguments: tc.Function.Arguments},
+		}
+		if !t.toolStarted[index] {
+			t.toolStarted[index] = true
+			delta.ID = tc.ID
+			delta.Type = "function"
+			delta.Function.Name = tc.Function.Name
+		} else if delta.Function.Arguments == "" {
+			continue
+		}
+		deltas = append(deltas, delta)
+	}
+	return deltas
+}
+
+func (t *qwenStreamTranslator) chunk(delta models.ChatMessageDelta, finishReason string) *models.ChatCompletionStreamResponse {
+	return &models.ChatCompletionStreamResponse{
+		ID:      t.id,
+		Object:  "chat.completion.chunk",
+		Created: t.created,
+		Model:   t.model,
+		Choices: []models.ChatCompletionStreamChoice{{
+			Index:        0,
+			Delta:        delta,
+			FinishReason: finishReason,
+		}},
+	}
+}
+
+// mapQwenFinishReason DashScope 未结束时 finish_reason 为字符串 "null"
+func mapQwenFinishReason(reason string) string {
+	if reason == "null" {
+		return ""
+	}
+	return reason
+}
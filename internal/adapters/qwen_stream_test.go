@@ -0,0 +1,191 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestQwenAdapter_ChatCompletionStream(t *testing.T) {
+	events := []string{
+		`{"output":{"finish_reason":"null","text":"你好"},"usage":{"total_tokens":12,"input_tokens":10,"output_tokens":2},"request_id":"req-1"}`,
+		`{"output":{"finish_reason":"null","text":"，我是"},"usage":{"total_tokens":14,"input_tokens":10,"output_tokens":4},"request_id":"req-1"}`,
+		`{"output":{"finish_reason":"stop","text":"通义千问。"},"usage":{"total_tokens":17,"input_tokens":10,"output_tokens":7},"request_id":"req-1"}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-DashScope-SSE") != "enable" {
+			t.Errorf("expected X-DashScope-SSE: enable, got %q", r.Header.Get("X-DashScope-SSE"))
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if params, _ := body["parameters"].(map[string]interface{}); params["incremental_output"] != true {
+			t.Errorf("expected incremental_output to be enabled, got %v", body["parameters"])
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i, e := range events {
+			fmt.Fprintf(w, "id:%d\nevent:result\n:HTTP_STATUS/200\ndata:%s\n\n", i+1, e)
+		}
+	}))
+	defer server.Close()
+
+	adapter, _ := NewQwenAdapter("test-key", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "qwen-turbo",
+		Messages: []models.ChatMessage{{Role: "user", Content: "你好"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, done := collectStreamChunks(t, stream)
+	if !done {
+		t.Error("expected stream to end with data: [DONE]")
+	}
+	if len(chunks) != 4 {
+		t.Fatalf("expected 4 chunks, got %d", len(chunks))
+	}
+
+	wantDeltas := []string{"", "你好", "，我是", "通义千问。"}
+	for i, chunk := range chunks {
+		if chunk.ID != "req-1" {
+			t.Errorf("expected id 'req-1', got %q", chunk.ID)
+		}
+		if got := chunk.Choices[0].Delta.Content; got != wantDeltas[i] {
+			t.Errorf("chunk %d: expected delta %q, got %q", i, wantDeltas[i], got)
+		}
+	}
+	if chunks[0].Choices[0].Delta.Role != "assistant" {
+		t.Errorf("expected role 'assistant', got %q", chunks[0].Choices[0].Delta.Role)
+	}
+	if chunks[1].Choices[0].FinishReason != "" {
+		t.Errorf("expected empty finish_reason for 'null', got %q", chunks[1].Choices[0].FinishReason)
+	}
+	if chunks[3].Choices[0].FinishReason != "stop" {
+		t.Errorf("expected finish_reason 'stop', got %q", chunks[3].Choices[0].FinishReason)
+	}
+}
+
+func TestQwenAdapter_ChatCompletionStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "id:1\nevent:error\n:HTTP_STATUS/400\ndata:{\"code\":\"InvalidParameter\",\"message\":\"Input data may contain inappropriate content.\",\"request_id\":\"req-2\"}\n\n")
+	}))
+	defer server.Close()
+
+	adapter, _ := NewQwenAdapter("test-key", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "qwen-turbo",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	_, err = NewSSEReader(stream).Next()
+	if err == nil || !strings.Contains(err.Error(), "InvalidParameter") {
+		t.Errorf("expected upstream error, got %v", err)
+	}
+}
+
+func TestQwenAdapter_ChatCompletionStreamToolCalls(t *testing.T) {
+	events := []string{
+		`{"output":{"choices":[{"finish_reason":"null","message":{"role":"assistant","content":"","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":"}}]}}]},"request_id":"req-2"}`,
+		`{"output":{"choices":[{"finish_reason":"null","message":{"role":"assistant","content":"","tool_calls":[{"index":0,"id":"","type":"function","function":{"arguments":"\"杭州\"}"}}]}}]},"request_id":"req-2"}`,
+		`{"output":{"choices":[{"finish_reason":"tool_calls","message":{"role":"assistant","content":""}}]},"usage":{"total_tokens":30,"input_tokens":20,"output_tokens":10},"request_id":"req-2"}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i, e := range events {
+			fmt.Fprintf(w, "id:%d\nevent:result\ndata:%s\n\n", i+1, e)
+		}
+	}))
+	defer server.Close()
+
+	adapter, _ := NewQwenAdapter("test-key", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "qwen-plus",
+		Messages: []models.ChatMessage{{Role: "user", Content: "杭州天气？"}},
+		Tools: []models.Tool{{
+			Type:     "function",
+			Function: models.FunctionDefinition{Name: "get_weather"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, _ := collectStreamChunks(t, stream)
+	if len(chunks) != 4 {
+		t.Fatalf("expected 4 chunks, got %d", len(chunks))
+	}
+
+	first := chunks[1].Choices[0].Delta.ToolCalls
+	if len(first) != 1 || first[0].ID != "call_1" || first[0].Function.Name != "get_weather" {
+		t.Errorf("expected first tool call delta with id and name, got %+v", first)
+	}
+
+	var args strings.Builder
+	for _, chunk := range chunks {
+		for _, tc := range chunk.Choices[0].Delta.ToolCalls {
+			args.WriteString(tc.Function.Arguments)
+		}
+	}
+	if args.String() != `{"city":"杭州"}` {
+		t.Errorf("expected arguments to be reassembled, got %q", args.String())
+	}
+	if chunks[3].Choices[0].FinishReason != "tool_calls" {
+		t.Errorf("expected finish_reason 'tool_calls', got %q", chunks[3].Choices[0].FinishReason)
+	}
+}
+
+func TestQwenAdapter_ChatCompletionStreamReasoning(t *testing.T) {
+	events := []string{
+		`{"output":{"choices":[{"finish_reason":"null","message":{"role":"assistant","content":"","reasoning_content":"**"}}]},"request_id":"req-4"}`,
+		`{"output":{"choices":[{"finish_reason":"null","message":{"role":"assistant","content":"","reasoning_content":"**先算** 2+2"}}]},"request_id":"req-4"}`,
+		`{"output":{"choices":[{"finish_reason":"null","message":{"role":"assistant","content":"**","reasoning_content":""}}]},"request_id":"req-4"}`,
+		`{"output":{"choices":[{"finish_reason":"stop","message":{"role":"assistant","content":"**等于 4**"}}]},"usage":{"total_tokens":12,"input_tokens":5,"output_tokens":7},"request_id":"req-4"}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i, e := range events {
+			fmt.Fprintf(w, "id:%d\nevent:result\ndata:%s\n\n", i+1, e)
+		}
+	}))
+	defer server.Close()
+
+	adapter, _ := NewQwenAdapter("test-key", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:           "qwen3-plus",
+		Messages:        []models.ChatMessage{{Role: "user", Content: "2+2?"}},
+		ReasoningEffort: "low",
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, _ := collectStreamChunks(t, stream)
+	var content, reasoning string
+	for _, chunk := range chunks {
+		for _, choice := range chunk.Choices {
+			content += choice.Delta.Content
+			reasoning += choice.Delta.ReasoningContent
+		}
+	}
+	// 增量片段恰好以已输出内容开头时也不能丢失文本
+	if reasoning != "****先算** 2+2" || content != "****等于 4**" {
+		t.Errorf("unexpected stream result: reasoning=%q content=%q", reasoning, content)
+	}
+}
@@ -0,0 +1,223 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestQwenAdapter_ToolCalls(t *testing.T) {
+	var gotReq map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		fmt.Fprint(w, `{
+  "output": {
+    "choices": [{
+      "finish_reason": "tool_calls",
+      "message": {
+        "role": "assistant",
+        "content": "",
+        "tool_calls": [{"id": "call_abc", "type": "function", "function": {"name": "get_weather", "arguments": "{\"city\":\"杭州\"}"}}]
+      }
+    }]
+  },
+  "usage": {"input_tokens": 20, "output_tokens": 8, "total_tokens": 28},
+  "request_id": "req-1"
+}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewQwenAdapter("test-key", server.URL)
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "qwen-plus",
+		Messages: []models.ChatMessage{
+			{Role: "user", Content: "北京天气怎么样？"},
+			{Role: "assistant", Content: "", ToolCalls: []models.ToolCall{{
+				ID:       "call_1",
+				Type:     "function",
+				Function: models.FunctionCall{Name: "get_weather", Arguments: `{"city":"北京"}`},
+			}}},
+			{Role: "tool", ToolCallID: "call_1", Name: "get_weather", Content: "晴，25度"},
+			{Role: "user", Content: "杭州呢？"},
+		},
+		Tools: []models.Tool{{
+			Type: "function",
+			Function: models.FunctionDefinition{
+				Name:       "get_weather",
+				Parameters: map[string]interface{}{"type": "object"},
+			},
+		}},
+		ToolChoice: "auto",
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	// 请求转换
+	params := gotReq["parameters"].(map[string]interface{})
+	if params["result_format"] != "message" {
+		t.Errorf("expected result_format 'message', got %v", params["result_format"])
+	}
+	if tools, ok := params["tools"].([]interface{}); !ok || len(tools) != 1 {
+		t.Errorf("expected 1 tool in parameters, got %v", params["tools"])
+	}
+	if params["tool_choice"] != "auto" {
+		t.Errorf("expected tool_choice 'auto', got %v", params["tool_choice"])
+	}
+	messages := gotReq["input"].(map[string]interface{})["messages"].([]interface{})
+	assistant := messages[1].(map[string]interface{})
+	if calls, ok := assistant["tool_calls"].([]interface{}); !ok || len(calls) != 1 {
+		t.Errorf("expected assistant tool_calls to be forwarded, got %v", assistant)
+	}
+	tool := messages[2].(map[string]interface{})
+	if tool["role"] != "tool" || tool["tool_call_id"] != "call_1" || tool["name"] != "get_weather" {
+		t.Errorf("expected tool message to be forwarded, got %v", tool)
+	}
+
+	// 响应转换
+	choice := resp.Choices[0]
+	if choice.FinishReason != "tool_calls" {
+		t.Errorf("expected finish_reason 'tool_calls', got %q", choice.FinishReason)
+	}
+	if len(choice.Message.ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(choice.Message.ToolCalls))
+	}
+	tc := choice.Message.ToolCalls[0]
+	if tc.ID != "call_abc" || tc.Function.Name != "get_weather" || tc.Function.Arguments != `{"city":"杭州"}` {
+		t.Errorf("unexpected tool call: %+v", tc)
+	}
+	if resp.Usage.TotalTokens != 28 {
+		t.Errorf("expected total tokens 28, got %d", resp.Usage.TotalTokens)
+	}
+}
+
+func TestQwenAdapter_TextFormatWithoutTools(t *testing.T) {
+	adapter := &QwenAdapter{}
+	qwenReq, err := adapter.convertToOpenAIFormat(&models.ChatCompletionRequest{
+		Model:    "qwen-turbo",
+		Messages: []models.ChatMessage{{Role: "user", Content: "你好"}},
+	}, false)
+	if err != nil {
+		t.Fatalf("convertToOpenAIFormat() error = %v", err)
+	}
+	if _, ok := qwenReq["parameters"]; ok {
+		t.Errorf("expected no parameters without options, got %v", qwenReq["parameters"])
+	}
+}
+
+func TestQwenAdapter_ReasoningEffort(t *testing.T) {
+	adapter := &QwenAdapter{}
+	maxTokens := 512
+	qwenReq, err := adapter.convertToOpenAIFormat(&models.ChatCompletionRequest{
+		Model: "qwen3-plus",
+		Messages: []models.ChatMessage{
+			{Role: "developer", Content: "简洁回答"},
+			{Role: "user", Content: "你好"},
+		},
+		MaxCompletionTokens: &maxTokens,
+		ReasoningEffort:     "low",
+	}, true)
+	if err != nil {
+		t.Fatalf("convertToOpenAIFormat() error = %v", err)
+	}
+
+	params := qwenReq["parameters"].(map[string]interface{})
+	if params["enable_thinking"] != true || params["thinking_budget"] != 1024 || params["max_tokens"] != 512 || params["result_format"] != "message" {
+		t.Errorf("unexpected parameters: %v", params)
+	}
+	messages := qwenReq["input"].(map[string]interface{})["messages"].([]map[string]interface{})
+	if messages[0]["role"] != "system" {
+		t.Errorf("expected developer mapped to system, got %v", messages[0]["role"])
+	}
+
+	qwenReq, _ = adapter.convertToOpenAIFormat(&models.ChatCompletionRequest{
+		Model:           "qwen3-plus",
+		Messages:        []models.ChatMessage{{Role: "user", Content: "你好"}},
+		ReasoningEffort: "minimal",
+	}, true)
+	if params := qwenReq["parameters"].(map[string]interface{}); params["enable_thinking"] != false || params["result_format"] != nil {
+		t.Errorf("expected thinking disabled for minimal effort, got %v", params)
+	}
+
+	if _, err := adapter.convertToOpenAIFormat(&models.ChatCompletionRequest{
+		Model:           "qwen3-plus",
+		Messages:        []models.ChatMessage{{Role: "user", Content: "你好"}},
+		ReasoningEffort: "extreme",
+	}, true); err == nil {
+		t.Error("expected error for unsupported reasoning_effort")
+	}
+}
+
+func TestQwenAdapter_ChatCompletionDisablesThinking(t *testing.T) {
+	var gotReq map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		fmt.Fprint(w, `{
+  "output": {"text": "4", "finish_reason": "stop"},
+  "usage": {"input_tokens": 5, "output_tokens": 1, "total_tokens": 6},
+  "request_id": "req-3"
+}`)
+	}))
+	defer server.Close()
+
+	// DashScope 拒绝开启思考的非流式请求，设置 reasoning_effort 时仍应关闭思考
+	adapter, _ := NewQwenAdapter("test-key", server.URL)
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:           "qwen3-plus",
+		Messages:        []models.ChatMessage{{Role: "user", Content: "2+2?"}},
+		ReasoningEffort: "medium",
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	params := gotReq["parameters"].(map[string]interface{})
+	if params["enable_thinking"] != false || params["thinking_budget"] != nil || params["result_format"] != nil {
+		t.Errorf("expected thinking disabled for non-streaming call, got %v", params)
+	}
+	if resp.Choices[0].Message.Content != "4" {
+		t.Errorf("unexpected message: %+v", resp.Choices[0].Message)
+	}
+}
+
+func TestQwenAdapter_Multimodal(t *testing.T) {
+	var gotReq map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/aigc/multimodal-generation/generation" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&gotReq)
+		fmt.Fprint(w, `{
+  "output": {"choices": [{"finish_reason": "stop", "message": {"role": "assistant", "content": [{"text": "一只猫"}]}}]},
+  "usage": {"input_tokens": 1200, "output_tokens": 3, "total_tokens": 1203},
+  "request_id": "req-2"
+}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewQwenAdapter("test-key", server.URL)
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "qwen-vl-plus",
+		Messages: []models.ChatMessage{{Role: "user", Content: []models.ContentPart{
+			{Type: "image_url", ImageURL: &models.ImageURL{URL: "https://example.com/cat.jpg"}},
+			{Type: "text", Text: "图里是什么？"},
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	messages := gotReq["input"].(map[string]interface{})["messages"].([]interface{})
+	content := messages[0].(map[string]interface{})["content"].([]interface{})
+	if len(content) != 2 || content[0].(map[string]interface{})["image"] != "https://example.com/cat.jpg" ||
+		content[1].(map[string]interface{})["text"] != "图里是什么？" {
+		t.Errorf("unexpected multimodal content: %v", content)
+	}
+	if resp.Choices[0].Message.Content != "一只猫" {
+		t.Errorf("expected text content from parts, got %v", resp.Choices[0].Message.Content)
+	}
+}
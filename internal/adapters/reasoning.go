@@ -0,0 +1,228 @@
+package adapters
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// reasoningBudget 将 reasoning_effort 转换为思考 token 预算，供使用预算控制思考的提供商使用
+// minimal 沿用 OpenAI 的取值，对应 0，表示不开启思考；不能关闭思考的模型由调用方换成最小预算
+func reasoningBudget(effort string) (int, error) {
+	switch effort {
+	case "minimal":
+		return 0, nil
+	case "low":
+		return 1024, nil
+	case "medium":
+		return 8192, nil
+	case "high":
+		return 24576, nil
+	}
+	return 0, fmt.Errorf("unsupported reasoning_effort %q", effort)
+}
+
+// Claude 扩展思考要求的最小预算
+const claudeMinThinkingBudget = 1024
+
+// claudeThinking 返回 Claude 扩展思考配置，未开启时返回 nil
+// max_tokens 必须大于思考预算：reasoning_effort 换算的预算超出时收紧到 max_tokens-1，低于最小预算则不开启思考；
+// 显式指定的 thinking.budget_tokens 不小于 max_tokens 时返回错误
+func claudeThinking(req *models.ChatCompletionRequest) (*models.ThinkingConfig, error) {
+	maxTokens := maxOutputTokens(req)
+	if req.Thinking != nil {
+		if req.Thinking.Type != "enabled" {
+			return nil, nil
+		}
+		if maxTokens != nil && *maxTokens <= req.Thinking.BudgetTokens {
+			return nil, fmt.Errorf("max_tokens (%d) must be greater than thinking.budget_tokens (%d)", *maxTokens, req.Thinking.BudgetTokens)
+		}
+		return req.Thinking, nil
+	}
+	if req.ReasoningEffort == "" {
+		return nil, nil
+	}
+
+	budget, err := reasoningBudget(req.ReasoningEffort)
+	if err != nil {
+		return nil, err
+	}
+	if maxTokens != nil && budget >= *maxTokens {
+		budget = *maxTokens - 1
+	}
+	if budget < claudeMinThinkingBudget {
+		return nil, nil
+	}
+	return &models.ThinkingConfig{Type: "enabled", BudgetTokens: budget}, nil
+}
+
+// maxOutputTokens 返回输出 token 上限，max_completion_tokens 优先于 max_tokens
+func maxOutputTokens(req *models.ChatCompletionRequest) *int {
+	if req.MaxCompletionTokens != nil {
+		return req.MaxCompletionTokens
+	}
+	return req.MaxTokens
+}
+
+const (
+	thinkOpenTag  = "<think>"
+	thinkCloseTag = "</think>"
+)
+
+// thinkTagParser 从 DeepSeek-R1 等模型的输出中分离 <think>...</think> 思考过程
+// 只识别出现在输出开头的 <think> 标签，支持标签被拆分在多个流式片段中
+type thinkTagParser struct {
+	buf      string
+	inThink  bool
+	decided  bool // 已确定输出是否以 <think> 开头
+	trimNext bool // 去掉 </think> 之后紧跟的换行
+}
+
+// feed 输入一段文本，返回其中可以确定的思考内容和正文
+func (p *thinkTagParser) feed(text string) (reasoning, content string) {
+	p.buf += text
+	for p.buf != "" {
+		if p.inThink {
+			if i := strings.Index(p.buf, thinkCloseTag); i >= 0 {
+				reasoning += p.buf[:i]
+				p.buf = p.buf[i+len(thinkCloseTag):]
+				p.inThink = false
+				p.trimNext = true
+				continue
+			}
+			// 保留可能是半个结束标签的尾部
+			keep := partialSuffixLen(p.buf, thinkCloseTag)
+			reasoning += p.buf[:len(p.buf)-keep]
+			p.buf = p.buf[len(p.buf)-keep:]
+			return reasoning, content
+		}
+
+		if p.decided {
+			if p.trimNext {
+				p.buf = strings.TrimLeft(p.buf, "\r\n")
+				if p.buf == "" {
+					return reasoning, content
+				}
+				p.trimNext = false
+			}
+			content += p.buf
+			p.buf = ""
+			return reasoning, content
+		}
+
+		trimmed := strings.TrimLeft(p.buf, " \t\r\n")
+		if strings.HasPrefix(trimmed, thinkOpenTag) {
+			p.decided = true
+			p.inThink = true
+			p.buf = trimmed[len(thinkOpenTag):]
+			continue
+		}
+		if strings.HasPrefix(thinkOpenTag, trimmed) {
+			// 可能是尚未收全的开始标签
+			return reasoning, content
+		}
+		p.decided = true
+	}
+	return reasoning, content
+}
+
+// flush 输出缓冲中剩余的文本
+func (p *thinkTagParser) flush() (reasoning, content string) {
+	if p.inThink {
+		reasoning = p.buf
+	} else {
+		content = p.buf
+	}
+	p.buf = ""
+	return reasoning, content
+}
+
+// partialSuffixLen 返回 s 末尾与 tag 前缀重合的最大长度（小于 tag 长度）
+func partialSuffixLen(s, tag string) int {
+	for n := len(tag) - 1; n > 0; n-- {
+		if strings.HasSuffix(s, tag[:n]) {
+			return n
+		}
+	}
+	return 0
+}
+
+// splitThinkTags 分离完整回复中的 <think> 思考过程
+func splitThinkTags(text string) (reasoning, content string) {
+	var p thinkTagParser
+	reasoning, content = p.feed(text)
+	r, c := p.flush()
+	return reasoning + r, content + c
+}
+
+// splitResponseThinkTags 上游未返回 reasoning_content 时，从正文中分离 <think> 思考过程
+func splitResponseThinkTags(resp *models.ChatCompletionResponse) {
+	for i := range resp.Choices {
+		msg := &resp.Choices[i].Message
+		text, ok := msg.Content.(string)
+		if !ok || msg.ReasoningContent != "" {
+			continue
+		}
+		msg.ReasoningContent, msg.Content = splitThinkTags(text)
+	}
+}
+
+// withThinkTags 包装流式翻译函数，将各 choice 正文中的 <think> 思考过程移到 reasoning_content
+func withThinkTags(next func() ([]*models.ChatCompletionStreamResponse, error)) func() ([]*models.ChatCompletionStreamResponse, error) {
+	parsers := make(map[int]*thinkTagParser)
+	var last *models.ChatCompletionStreamResponse
+
+	return func() ([]*models.ChatCompletionStreamResponse, error) {
+		chunks, err := next()
+		for _, chunk := range chunks {
+			last = chunk
+			for i := range chunk.Choices {
+				choice := &chunk.Choices[i]
+				p, ok := parsers[choice.Index]
+				if !ok {
+					p = &thinkTagParser{}
+					parsers[choice.Index] = p
+				}
+				reasoning, content := p.feed(choice.Delta.Content)
+				// 结束块之后不应再有正文，残留的文本随结束块一起输出
+				if choice.FinishReason != "" {
+					restReasoning, restContent := p.flush()
+					reasoning += restReasoning
+					content += restContent
+				}
+				choice.Delta.ReasoningContent += reasoning
+				choice.Delta.Content = content
+			}
+		}
+
+		// 没有结束块的流在结束时输出残留的文本
+		if err == io.EOF && last != nil {
+			// 按 choice 序号依次输出，保证多个 choice 的顺序稳定
+			indexes := make([]int, 0, len(parsers))
+			for index := range parsers {
+				indexes = append(indexes, index)
+			}
+			sort.Ints(indexes)
+			for _, index := range indexes {
+				reasoning, content := parsers[index].flush()
+				if reasoning == "" && content == "" {
+					continue
+				}
+				chunks = append(chunks, &models.ChatCompletionStreamResponse{
+					ID:      last.ID,
+					Object:  "chat.completion.chunk",
+					Created: last.Created,
+					Model:   last.Model,
+					Choices: []models.ChatCompletionStreamChoice{{
+						Index: index,
+						Delta: models.ChatMessageDelta{ReasoningContent: reasoning, Content: content},
+					}},
+				})
+			}
+		}
+		return chunks, err
+	}
+}
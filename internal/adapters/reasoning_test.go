@@ -0,0 +1,241 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestSplitThinkTags(t *testing.T) {
+	tests := []struct {
+		text          string
+		wantReasoning string
+		wantContent   string
+	}{
+		{"<think>Let me think.</think>\n\nThe answer is 42.", "Let me think.", "The answer is 42."},
+		{"\n<think>\nhmm\n</think>42", "\nhmm\n", "42"},
+		{"The answer is 42.", "", "The answer is 42."},
+		{"Use <think> tags like this.", "", "Use <think> tags like this."},
+		{"<think>unfinished", "unfinished", ""},
+	}
+	for _, tt := range tests {
+		reasoning, content := splitThinkTags(tt.text)
+		if reasoning != tt.wantReasoning || content != tt.wantContent {
+			t.Errorf("splitThinkTags(%q) = %q, %q, want %q, %q", tt.text, reasoning, content, tt.wantReasoning, tt.wantContent)
+		}
+	}
+}
+
+func TestThinkTagParser_SplitAcrossChunks(t *testing.T) {
+	var p thinkTagParser
+	var reasoning, content strings.Builder
+	for _, piece := range []string{"<thi", "nk>Step 1", ". Step 2</th", "ink>", "\n\nDone", "."} {
+		r, c := p.feed(piece)
+		reasoning.WriteString(r)
+		content.WriteString(c)
+	}
+	r, c := p.flush()
+	reasoning.WriteString(r)
+	content.WriteString(c)
+
+	if reasoning.String() != "Step 1. Step 2" || content.String() != "Done." {
+		t.Errorf("got reasoning %q, content %q", reasoning.String(), content.String())
+	}
+}
+
+func TestSiliconFlowAdapter_ThinkTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(readBody(r), `"stream":true`) {
+			fmt.Fprint(w, `{"id":"1","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"<think>Compare sizes.</think>\n\n9.11 < 9.9"},"finish_reason":"stop"}]}`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, piece := range []string{"<think>", "Compare", " sizes.</", "think>\n\n9.11", " < 9.9"} {
+			fmt.Fprintf(w, "data: {\"id\":\"1\",\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", piece)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	adapter, _ := NewSiliconFlowAdapter("test-key", server.URL)
+	req := &models.ChatCompletionRequest{
+		Model:    "deepseek-ai/DeepSeek-R1-Distill-Qwen-7B",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Which is larger, 9.11 or 9.9?"}},
+	}
+
+	resp, err := adapter.ChatCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if msg := resp.Choices[0].Message; msg.ReasoningContent != "Compare sizes." || msg.Content != "9.11 < 9.9" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+
+	stream, err := adapter.ChatCompletionStream(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, _ := collectStreamChunks(t, stream)
+	var reasoning, content strings.Builder
+	for _, chunk := range chunks {
+		for _, choice := range chunk.Choices {
+			reasoning.WriteString(choice.Delta.ReasoningContent)
+			content.WriteString(choice.Delta.Content)
+		}
+	}
+	if reasoning.String() != "Compare sizes." || content.String() != "9.11 < 9.9" {
+		t.Errorf("got reasoning %q, content %q", reasoning.String(), content.String())
+	}
+}
+
+func TestWithThinkTags_FlushesBeforeFinish(t *testing.T) {
+	// 思考内容以半个结束标签结尾（如输出被截断），结束块到达时仍有残留文本
+	upstream := []*models.ChatCompletionStreamResponse{
+		{ID: "1", Choices: []models.ChatCompletionStreamChoice{{Delta: models.ChatMessageDelta{Content: "<think>step 1 </th"}}}},
+		{ID: "1", Choices: []models.ChatCompletionStreamChoice{{Delta: models.ChatMessageDelta{}, FinishReason: "stop"}}},
+	}
+	next := withThinkTags(func() ([]*models.ChatCompletionStreamResponse, error) {
+		if len(upstream) == 0 {
+			return nil, io.EOF
+		}
+		chunk := upstream[0]
+		upstream = upstream[1:]
+		return []*models.ChatCompletionStreamResponse{chunk}, nil
+	})
+
+	var reasoning string
+	finished := false
+	for {
+		chunks, err := next()
+		for _, chunk := range chunks {
+			for _, choice := range chunk.Choices {
+				if finished && (choice.Delta.Content != "" || choice.Delta.ReasoningContent != "") {
+					t.Errorf("got content after finish_reason: %+v", choice.Delta)
+				}
+				reasoning += choice.Delta.ReasoningContent
+				if choice.FinishReason != "" {
+					finished = true
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	if reasoning != "step 1 </th" {
+		t.Errorf("expected buffered text to be flushed, got %q", reasoning)
+	}
+}
+
+func TestWithThinkTags_FlushOrderAtEOF(t *testing.T) {
+	// 多个 choice 都有残留文本且没有结束块，流结束时按 choice 序号依次输出
+	choices := make([]models.ChatCompletionStreamChoice, 0, 8)
+	for i := 7; i >= 0; i-- {
+		choices = append(choices, models.ChatCompletionStreamChoice{Index: i, Delta: models.ChatMessageDelta{Content: "<think>x</th"}})
+	}
+	sent := false
+	next := withThinkTags(func() ([]*models.ChatCompletionStreamResponse, error) {
+		if sent {
+			return nil, io.EOF
+		}
+		sent = true
+		return []*models.ChatCompletionStreamResponse{{ID: "1", Choices: choices}}, nil
+	})
+
+	next()
+	chunks, err := next()
+	if err != io.EOF || len(chunks) != 8 {
+		t.Fatalf("expected 8 trailing chunks at EOF, got %d, %v", len(chunks), err)
+	}
+	for i, chunk := range chunks {
+		if chunk.Choices[0].Index != i {
+			t.Errorf("chunk %d: expected choice index %d, got %d", i, i, chunk.Choices[0].Index)
+		}
+	}
+}
+
+func TestDeepSeekAdapter_ReasoningContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body := readBody(r); strings.Contains(body, "reasoning_content") {
+			t.Errorf("reasoning_content must not be sent back to DeepSeek: %s", body)
+		}
+		fmt.Fprint(w, `{"id":"1","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","reasoning_content":"9.9 = 9.90 > 9.11","content":"9.9"},"finish_reason":"stop"}]}`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewDeepSeekAdapter("test-key", server.URL)
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "deepseek-reasoner",
+		Messages: []models.ChatMessage{
+			{Role: "user", Content: "Hi"},
+			{Role: "assistant", Content: "Hello", ReasoningContent: "Greet back."},
+			{Role: "user", Content: "Which is larger, 9.11 or 9.9?"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if msg := resp.Choices[0].Message; msg.ReasoningContent != "9.9 = 9.90 > 9.11" || msg.Content != "9.9" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+}
+
+func TestConvertToOpenAIFormatGeneric_Reasoning(t *testing.T) {
+	maxTokens, maxCompletionTokens := 100, 200
+	result := convertToOpenAIFormatGeneric(&models.ChatCompletionRequest{
+		Model: "some-model",
+		Messages: []models.ChatMessage{
+			{Role: "developer", Content: "Be brief."},
+			{Role: "assistant", Content: "Hello", ReasoningContent: "Greet back."},
+		},
+		MaxTokens:           &maxTokens,
+		MaxCompletionTokens: &maxCompletionTokens,
+		ReasoningEffort:     "low",
+	})
+
+	// reasoning_effort 只由支持的提供商转发
+	if _, ok := result["reasoning_effort"]; result["max_tokens"] != 200 || ok {
+		t.Errorf("unexpected request: %v", result)
+	}
+	messages := result["messages"].([]models.ChatMessage)
+	if messages[0].Role != "system" || messages[1].ReasoningContent != "" {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+}
+
+func TestOpenAICompatibleAdapter_ReasoningEffort(t *testing.T) {
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody = readBody(r)
+		fmt.Fprint(w, `{"id":"1","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`)
+	}))
+	defer server.Close()
+
+	req := &models.ChatCompletionRequest{
+		Model:           "some-model",
+		Messages:        []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		ReasoningEffort: "high",
+	}
+	for provider, want := range map[Provider]bool{"groq": true, "mistral": false, "deepseek": false} {
+		adapter := NewOpenAICompatibleAdapter(provider, "key", server.URL, "", "")
+		if _, err := adapter.ChatCompletion(context.Background(), req); err != nil {
+			t.Fatalf("%s: ChatCompletion() error = %v", provider, err)
+		}
+		if got := strings.Contains(gotBody, `"reasoning_effort":"high"`); got != want {
+			t.Errorf("%s: expected reasoning_effort forwarded = %v, body %s", provider, want, gotBody)
+		}
+	}
+}
+
+func readBody(r *http.Request) string {
+	body, _ := io.ReadAll(r.Body)
+	return string(body)
+}
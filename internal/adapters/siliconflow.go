@@ -70,12 +70,17 @@ func (a *SiliconFlowAdapter) ChatCompletion(ctx context.Context, req *models.Cha
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	// 部分 R1 类模型将思考过程以 <think> 标签放在正文中
+	splitResponseThinkTags(&openaiResp)
 	return &openaiResp, nil
 }
 
 func (a *SiliconFlowAdapter) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
 	sfReq := a.convertToOpenAIFormat(req)
 	sfReq["stream"] = true
+	if req.StreamOptions != nil {
+		sfReq["stream_options"] = req.StreamOptions
+	}
 
 	reqBody, err := json.Marshal(sfReq)
 	if err != nil {
@@ -100,20 +105,20 @@ func (a *SiliconFlowAdapter) ChatCompletionStream(ctx context.Context, req *mode
 		return nil, fmt.Errorf("siliconflow stream error: status %d", resp.StatusCode)
 	}
 
-	return resp.Body, nil
+	return newChunkStream(req, withThinkTags(openAICompatibleStreamNext(resp.Body)), resp.Body), nil
 }
 
 func (a *SiliconFlowAdapter) convertToOpenAIFormat(req *models.ChatCompletionRequest) map[string]interface{} {
 	messages := make([]map[string]interface{}, 0, len(req.Messages))
 	for _, msg := range req.Messages {
 		msgMap := map[string]interface{}{
-			"role": msg.Role,
+			"role": nativeRole(msg.Role),
 		}
 
 		switch v := msg.Content.(type) {
 		case string:
 			msgMap["content"] = v
-		case []interface{}:
+		case []interface{}, []models.ContentPart:
 			msgMap["content"] = v
 		default:
 			msgMap["content"] = fmt.Sprintf("%v", v)
@@ -133,8 +138,8 @@ func (a *SiliconFlowAdapter) convertToOpenAIFormat(req *models.ChatCompletionReq
 	if req.TopP != nil {
 		result["top_p"] = *req.TopP
 	}
-	if req.MaxTokens != nil {
-		result["max_tokens"] = *req.MaxTokens
+	if maxTokens := maxOutputTokens(req); maxTokens != nil {
+		result["max_tokens"] = *maxTokens
 	}
 	if req.Stop != nil && len(req.Stop) > 0 {
 		result["stop"] = req.Stop
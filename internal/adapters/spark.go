@@ -2,23 +2,51 @@ package adapters
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/gotoailab/llmhub/internal/models"
 )
 
 // SparkAdapter 讯飞星火适配器
+// 使用 WebSocket 协议，apiKey 格式为 "APPID:APIKey:APISecret"
 type SparkAdapter struct {
-	*openAICompatibleAdapter
+	appID     string
+	apiKey    string
+	apiSecret string
+	baseURL   string
+	dialer    *websocket.Dialer
 }
 
 // NewSparkAdapter 创建讯飞星火适配器
 func NewSparkAdapter(apiKey, baseURL string) (Adapter, error) {
 	if baseURL == "" {
-		baseURL = "https://spark-api.xf-yun.com/v1"
+		baseURL = "wss://spark-api.xf-yun.com"
 	}
-	adapter := NewOpenAICompatibleAdapter(Provider("spark"), apiKey, baseURL, "/chat/completions", "Bearer")
-	return &SparkAdapter{adapter}, nil
+
+	parts := strings.SplitN(apiKey, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("spark api key must be in the format \"APPID:APIKey:APISecret\"")
+	}
+
+	return &SparkAdapter{
+		appID:     parts[0],
+		apiKey:    parts[1],
+		apiSecret: parts[2],
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		dialer: &websocket.Dialer{
+			HandshakeTimeout: 30 * time.Second,
+		},
+	}, nil
 }
 
 func (a *SparkAdapter) GetProvider() Provider {
@@ -26,9 +54,284 @@ func (a *SparkAdapter) GetProvider() Provider {
 }
 
 func (a *SparkAdapter) ChatCompletion(ctx context.Context, req *models.ChatCompletionRequest) (*models.ChatCompletionResponse, error) {
-	return a.openAICompatibleAdapter.ChatCompletion(ctx, req)
+	if len(req.Tools) > 0 || len(req.Functions) > 0 {
+		return nil, fmt.Errorf("tool use not supported for provider %s", a.GetProvider())
+	}
+
+	conn, err := a.connect(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// 星火只提供流式协议，读取全部帧后组装完整响应
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	var (
+		content strings.Builder
+		sid     string
+		usage   models.Usage
+	)
+	for {
+		frame, err := readSparkFrame(conn)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+
+		sid = frame.Header.SID
+		for _, text := range frame.Payload.Choices.Text {
+			content.WriteString(text.Content)
+		}
+		if frame.Payload.Usage != nil {
+			usage = frame.Payload.Usage.Text.toUsage()
+		}
+
+		if frame.Header.Status == sparkStatusLast {
+			break
+		}
+	}
+
+	return &models.ChatCompletionResponse{
+		ID:      sid,
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   req.Model,
+		Choices: []models.ChatCompletionChoice{
+			{
+				Index: 0,
+				Message: models.ChatMessage{
+					Role:    "assistant",
+					Content: content.String(),
+				},
+				FinishReason: "stop",
+			},
+		},
+		Usage: usage,
+	}, nil
 }
 
 func (a *SparkAdapter) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
-	return a.openAICompatibleAdapter.ChatCompletionStream(ctx, req)
+	if len(req.Tools) > 0 || len(req.Functions) > 0 {
+		return nil, fmt.Errorf("tool use not supported for provider %s", a.GetProvider())
+	}
+
+	conn, err := a.connect(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSparkStream(ctx, conn, req), nil
+}
+
+// connect 建立签名的 WebSocket 连接并发送请求帧
+func (a *SparkAdapter) connect(ctx context.Context, req *models.ChatCompletionRequest) (*websocket.Conn, error) {
+	path, domain := a.mapModel(req.Model)
+
+	sparkReq, err := a.convertToSparkRequest(req, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+
+	endpoint, err := a.signedURL(a.baseURL+path, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	conn, resp, err := a.dialer.DialContext(ctx, endpoint, nil)
+	if err != nil {
+		// 握手失败时响应体中包含错误原因
+		if resp != nil {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("spark api error: status %d, body: %s", resp.StatusCode, string(body))
+		}
+		return nil, fmt.Errorf("spark api error: %w", err)
+	}
+
+	if err := conn.WriteJSON(sparkReq); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	return conn, nil
+}
+
+// signedURL 按讯飞开放平台的 HMAC-SHA256 规则生成鉴权 URL
+func (a *SparkAdapter) signedURL(rawURL string, now time.Time) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	date := now.UTC().Format(http.TimeFormat)
+	origin := fmt.Sprintf("host: %s\ndate: %s\nGET %s HTTP/1.1", u.Host, date, u.Path)
+
+	mac := hmac.New(sha256.New, []byte(a.apiSecret))
+	mac.Write([]byte(origin))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	authorization := fmt.Sprintf(`api_key="%s", algorithm="hmac-sha256", headers="host date request-line", signature="%s"`,
+		a.apiKey, signature)
+
+	query := url.Values{}
+	query.Set("authorization", base64.StdEncoding.EncodeToString([]byte(authorization)))
+	query.Set("date", date)
+	query.Set("host", u.Host)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// 星火响应帧的状态，2 表示最后一帧
+const sparkStatusLast = 2
+
+type SparkRequest struct {
+	Header    SparkRequestHeader `json:"header"`
+	Parameter SparkParameter     `json:"parameter"`
+	Payload   SparkPayload       `json:"payload"`
+}
+
+type SparkRequestHeader struct {
+	AppID string `json:"app_id"`
+	UID   string `json:"uid,omitempty"`
+}
+
+type SparkParameter struct {
+	Chat SparkChatParameter `json:"chat"`
+}
+
+type SparkChatParameter struct {
+	Domain      string   `json:"domain"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopK        *int     `json:"top_k,omitempty"`
+	MaxTokens   *int     `json:"max_tokens,omitempty"`
+}
+
+type SparkPayload struct {
+	Message SparkMessageText `json:"message"`
+}
+
+type SparkMessageText struct {
+	Text []SparkMessage `json:"text"`
+}
+
+type SparkMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+	Index   int    `json:"index,omitempty"`
+}
+
+type SparkResponse struct {
+	Header  SparkResponseHeader  `json:"header"`
+	Payload SparkResponsePayload `json:"payload"`
+}
+
+type SparkResponseHeader struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	SID     string `json:"sid"`
+	Status  int    `json:"status"`
+}
+
+type SparkResponsePayload struct {
+	Choices struct {
+		Status int            `json:"status"`
+		Seq    int            `json:"seq"`
+		Text   []SparkMessage `json:"text"`
+	} `json:"choices"`
+	Usage *struct {
+		Text SparkUsage `json:"text"`
+	} `json:"usage,omitempty"`
+}
+
+type SparkUsage struct {
+	QuestionTokens   int `json:"question_tokens"`
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+func (u SparkUsage) toUsage() models.Usage {
+	return models.Usage{
+		PromptTokens:     u.PromptTokens,
+		CompletionTokens: u.CompletionTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+}
+
+// readSparkFrame 读取一个响应帧，header.code 非 0 时返回错误
+func readSparkFrame(conn *websocket.Conn) (*SparkResponse, error) {
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		return nil, fmt.Errorf("spark api error: %w", err)
+	}
+
+	var frame SparkResponse
+	if err := json.Unmarshal(data, &frame); err != nil {
+		return nil, fmt.Errorf("failed to decode spark frame: %w", err)
+	}
+	if frame.Header.Code != 0 {
+		return nil, fmt.Errorf("spark api error: code %d, message: %s, sid: %s", frame.Header.Code, frame.Header.Message, frame.Header.SID)
+	}
+	return &frame, nil
+}
+
+func (a *SparkAdapter) convertToSparkRequest(req *models.ChatCompletionRequest, domain string) (*SparkRequest, error) {
+	// 消息内容按纯文本发送，图片会被丢弃
+	if hasContentPart(req.Messages, "image_url") {
+		return nil, fmt.Errorf("image input not supported for provider %s", a.GetProvider())
+	}
+
+	messages := make([]SparkMessage, 0, len(req.Messages))
+	for _, msg := range req.Messages {
+		role := nativeRole(msg.Role)
+		switch role {
+		case "system", "user", "assistant":
+		default:
+			role = "user"
+		}
+		messages = append(messages, SparkMessage{Role: role, Content: contentToText(msg.Content)})
+	}
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("at least one message is required")
+	}
+
+	return &SparkRequest{
+		Header: SparkRequestHeader{
+			AppID: a.appID,
+			UID:   req.User,
+		},
+		Parameter: SparkParameter{
+			Chat: SparkChatParameter{
+				Domain:      domain,
+				Temperature: req.Temperature,
+				MaxTokens:   maxOutputTokens(req),
+			},
+		},
+		Payload: SparkPayload{
+			Message: SparkMessageText{Text: messages},
+		},
+	}, nil
+}
+
+// mapModel 将模型名称映射为星火的接口路径和 domain
+func (a *SparkAdapter) mapModel(modelName string) (path, domain string) {
+	switch strings.ToLower(modelName) {
+	case "spark-lite", "lite":
+		return "/v1.1/chat", "lite"
+	case "spark-pro", "spark-v3", "generalv3":
+		return "/v3.1/chat", "generalv3"
+	case "spark-pro-128k", "pro-128k":
+		return "/chat/pro-128k", "pro-128k"
+	case "spark-max", "spark-v3.5", "generalv3.5":
+		return "/v3.5/chat", "generalv3.5"
+	case "spark-max-32k", "max-32k":
+		return "/chat/max-32k", "max-32k"
+	case "spark-v4", "spark-ultra", "4.0ultra":
+		return "/v4.0/chat", "4.0Ultra"
+	}
+	// 未知模型默认使用 Max
+	return "/v3.5/chat", "generalv3.5"
 }
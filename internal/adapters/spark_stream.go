@@ -0,0 +1,93 @@
+package adapters
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// sparkStreamTranslator 将星火 WebSocket 响应帧转换为 OpenAI chat.completion.chunk
+type sparkStreamTranslator struct {
+	conn    *websocket.Conn
+	model   string
+	created int64
+	started bool
+}
+
+// sparkConnCloser 关闭流时同时取消对 ctx 的监听
+type sparkConnCloser struct {
+	conn *websocket.Conn
+	stop func() bool
+}
+
+func (c *sparkConnCloser) Close() error {
+	c.stop()
+	return c.conn.Close()
+}
+
+func newSparkStream(ctx context.Context, conn *websocket.Conn, req *models.ChatCompletionRequest) io.ReadCloser {
+	t := &sparkStreamTranslator{
+		conn:    conn,
+		model:   req.Model,
+		created: time.Now().Unix(),
+	}
+	// ctx 取消时关闭连接，使阻塞中的读取立即返回
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	return newChunkStream(req, t.next, &sparkConnCloser{conn: conn, stop: stop})
+}
+
+func (t *sparkStreamTranslator) next() ([]*models.ChatCompletionStreamResponse, error) {
+	frame, err := readSparkFrame(t.conn)
+	if err != nil {
+		return nil, err
+	}
+
+	id := frame.Header.SID
+	chunks := make([]*models.ChatCompletionStreamResponse, 0, 2)
+	if !t.started {
+		t.started = true
+		chunks = append(chunks, t.chunk(id, models.ChatMessageDelta{Role: "assistant"}, ""))
+	}
+
+	var content string
+	for _, text := range frame.Payload.Choices.Text {
+		content += text.Content
+	}
+
+	last := frame.Header.Status == sparkStatusLast
+	finishReason := ""
+	if last {
+		finishReason = "stop"
+	}
+	if content != "" || finishReason != "" {
+		chunks = append(chunks, t.chunk(id, models.ChatMessageDelta{Content: content}, finishReason))
+	}
+
+	// 用量只在最后一帧返回
+	if frame.Payload.Usage != nil {
+		usage := frame.Payload.Usage.Text.toUsage()
+		chunks[len(chunks)-1].Usage = &usage
+	}
+
+	if last {
+		return chunks, io.EOF
+	}
+	return chunks, nil
+}
+
+func (t *sparkStreamTranslator) chunk(id string, delta models.ChatMessageDelta, finishReason string) *models.ChatCompletionStreamResponse {
+	return &models.ChatCompletionStreamResponse{
+		ID:      id,
+		Object:  "chat.completion.chunk",
+		Created: t.created,
+		Model:   t.model,
+		Choices: []models.ChatCompletionStreamChoice{{
+			Index:        0,
+			Delta:        delta,
+			FinishReason: finishReason,
+		}},
+	}
+}
@@ -0,0 +1,193 @@
+package adapters
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// newSparkTestServer 启动一个校验签名并按顺序返回响应帧的星火 WebSocket 服务
+func newSparkTestServer(t *testing.T, frames []string, gotReq *SparkRequest) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		authorization, err := base64.StdEncoding.DecodeString(q.Get("authorization"))
+		if err != nil {
+			t.Errorf("failed to decode authorization: %v", err)
+		}
+		origin := fmt.Sprintf("host: %s\ndate: %s\nGET %s HTTP/1.1", q.Get("host"), q.Get("date"), r.URL.Path)
+		mac := hmac.New(sha256.New, []byte("api-secret"))
+		mac.Write([]byte(origin))
+		wantSignature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		if !strings.Contains(string(authorization), `api_key="api-key"`) ||
+			!strings.Contains(string(authorization), fmt.Sprintf(`signature="%s"`, wantSignature)) {
+			http.Error(w, `{"message":"HMAC signature does not match"}`, http.StatusUnauthorized)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade error: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		if err := conn.ReadJSON(gotReq); err != nil {
+			t.Errorf("failed to read request frame: %v", err)
+			return
+		}
+		for _, frame := range frames {
+			conn.WriteMessage(websocket.TextMessage, []byte(frame))
+		}
+	}))
+}
+
+func sparkTestURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+var sparkTestFrames = []string{
+	`{"header":{"code":0,"message":"Success","sid":"cht-1","status":0},"payload":{"choices":{"status":0,"seq":0,"text":[{"content":"你好","role":"assistant","index":0}]}}}`,
+	`{"header":{"code":0,"message":"Success","sid":"cht-1","status":1},"payload":{"choices":{"status":1,"seq":1,"text":[{"content":"，我是星火","role":"assistant","index":0}]}}}`,
+	`{"header":{"code":0,"message":"Success","sid":"cht-1","status":2},"payload":{"choices":{"status":2,"seq":2,"text":[{"content":"。","role":"assistant","index":0}]},"usage":{"text":{"question_tokens":2,"prompt_tokens":2,"completion_tokens":6,"total_tokens":8}}}}`,
+}
+
+func TestNewSparkAdapter_InvalidKey(t *testing.T) {
+	if _, err := NewSparkAdapter("app-id:api-key", ""); err == nil {
+		t.Error("expected error for api key without api secret")
+	}
+}
+
+func TestSparkAdapter_ChatCompletion(t *testing.T) {
+	var gotReq SparkRequest
+	server := newSparkTestServer(t, sparkTestFrames, &gotReq)
+	defer server.Close()
+
+	adapter, err := NewSparkAdapter("app-id:api-key:api-secret", sparkTestURL(server))
+	if err != nil {
+		t.Fatalf("NewSparkAdapter() error = %v", err)
+	}
+
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model: "spark-v4",
+		Messages: []models.ChatMessage{
+			{Role: "system", Content: "你是一个助手"},
+			{Role: "user", Content: "你好"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	if gotReq.Header.AppID != "app-id" || gotReq.Parameter.Chat.Domain != "4.0Ultra" {
+		t.Errorf("unexpected request header/parameter: %+v", gotReq)
+	}
+	if len(gotReq.Payload.Message.Text) != 2 || gotReq.Payload.Message.Text[0].Role != "system" {
+		t.Errorf("unexpected request payload: %+v", gotReq.Payload)
+	}
+
+	if resp.ID != "cht-1" || resp.Choices[0].Message.Content != "你好，我是星火。" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if resp.Usage.PromptTokens != 2 || resp.Usage.CompletionTokens != 6 || resp.Usage.TotalTokens != 8 {
+		t.Errorf("unexpected usage: %+v", resp.Usage)
+	}
+}
+
+func TestSparkAdapter_ChatCompletionStream(t *testing.T) {
+	var gotReq SparkRequest
+	server := newSparkTestServer(t, sparkTestFrames, &gotReq)
+	defer server.Close()
+
+	adapter, _ := NewSparkAdapter("app-id:api-key:api-secret", sparkTestURL(server))
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "spark-lite",
+		Messages: []models.ChatMessage{{Role: "user", Content: "你好"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, done := collectStreamChunks(t, stream)
+	if !done {
+		t.Error("expected stream to end with data: [DONE]")
+	}
+	if len(chunks) != 4 {
+		t.Fatalf("expected 4 chunks, got %d", len(chunks))
+	}
+	if gotReq.Parameter.Chat.Domain != "lite" {
+		t.Errorf("expected domain 'lite', got %q", gotReq.Parameter.Chat.Domain)
+	}
+
+	var content strings.Builder
+	for _, chunk := range chunks {
+		content.WriteString(chunk.Choices[0].Delta.Content)
+	}
+	if content.String() != "你好，我是星火。" {
+		t.Errorf("unexpected content %q", content.String())
+	}
+	if chunks[3].Choices[0].FinishReason != "stop" {
+		t.Errorf("expected finish_reason 'stop', got %q", chunks[3].Choices[0].FinishReason)
+	}
+}
+
+func TestSparkAdapter_FrameError(t *testing.T) {
+	var gotReq SparkRequest
+	server := newSparkTestServer(t, []string{
+		`{"header":{"code":10013,"message":"input content audit failed","sid":"cht-2","status":2}}`,
+	}, &gotReq)
+	defer server.Close()
+
+	adapter, _ := NewSparkAdapter("app-id:api-key:api-secret", sparkTestURL(server))
+	_, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "spark-v3.5",
+		Messages: []models.ChatMessage{{Role: "user", Content: "test"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "10013") {
+		t.Errorf("expected spark error code in error, got %v", err)
+	}
+}
+
+func TestSparkAdapter_HandshakeError(t *testing.T) {
+	var gotReq SparkRequest
+	server := newSparkTestServer(t, nil, &gotReq)
+	defer server.Close()
+
+	adapter, _ := NewSparkAdapter("app-id:api-key:wrong-secret", sparkTestURL(server))
+	_, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "spark-v3.5",
+		Messages: []models.ChatMessage{{Role: "user", Content: "test"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Errorf("expected handshake error, got %v", err)
+	}
+}
+
+func TestSparkAdapter_RejectsTools(t *testing.T) {
+	// 不建立连接，直接返回错误
+	adapter, _ := NewSparkAdapter("app-id:api-key:api-secret", "ws://127.0.0.1:0")
+	req := &models.ChatCompletionRequest{
+		Model:    "generalv3.5",
+		Messages: []models.ChatMessage{{Role: "user", Content: "北京天气怎么样？"}},
+		Tools:    []models.Tool{{Type: "function", Function: models.FunctionDefinition{Name: "get_weather"}}},
+	}
+	if _, err := adapter.ChatCompletion(context.Background(), req); err == nil || !strings.Contains(err.Error(), "tool use not supported for provider spark") {
+		t.Errorf("expected tool use error, got %v", err)
+	}
+
+	req.Tools = nil
+	req.Functions = []models.FunctionDefinition{{Name: "get_weather"}}
+	if _, err := adapter.ChatCompletionStream(context.Background(), req); err == nil || !strings.Contains(err.Error(), "tool use not supported for provider spark") {
+		t.Errorf("expected tool use error, got %v", err)
+	}
+}
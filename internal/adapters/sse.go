@@ -0,0 +1,193 @@
+package adapters
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// SSEDone OpenAI 流式响应的结束标记
+const SSEDone = "[DONE]"
+
+// SSEEvent 一个完整的 Server-Sent Event
+type SSEEvent struct {
+	Event string
+	ID    string
+	Data  []byte
+}
+
+// SSEReader 按行解析 Server-Sent Events 流
+type SSEReader struct {
+	reader *bufio.Reader
+}
+
+// NewSSEReader 创建 SSE 读取器
+func NewSSEReader(r io.Reader) *SSEReader {
+	return &SSEReader{reader: bufio.NewReader(r)}
+}
+
+// Next 读取下一个事件，流结束时返回 io.EOF
+func (r *SSEReader) Next() (*SSEEvent, error) {
+	event := &SSEEvent{}
+	var data [][]byte
+	hasField := false
+
+	for {
+		line, err := r.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if err == io.EOF && len(line) == 0 {
+			// 流结束时分发尚未以空行结尾的事件
+			if hasField {
+				event.Data = bytes.Join(data, []byte("\n"))
+				return event, nil
+			}
+			return nil, io.EOF
+		}
+
+		line = bytes.TrimRight(line, "\r\n")
+
+		// 空行表示一个事件结束
+		if len(line) == 0 {
+			if hasField {
+				event.Data = bytes.Join(data, []byte("\n"))
+				return event, nil
+			}
+			continue
+		}
+
+		// 以冒号开头的是注释（常用于心跳）
+		if line[0] == ':' {
+			continue
+		}
+
+		field, value := line, []byte(nil)
+		if i := bytes.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], line[i+1:]
+			value = bytes.TrimPrefix(value, []byte(" "))
+		}
+
+		switch string(field) {
+		case "data":
+			data = append(data, append([]byte(nil), value...))
+			hasField = true
+		case "event":
+			event.Event = string(value)
+			hasField = true
+		case "id":
+			event.ID = string(value)
+			hasField = true
+		}
+
+		if err == io.EOF {
+			if hasField {
+				event.Data = bytes.Join(data, []byte("\n"))
+				return event, nil
+			}
+			return nil, io.EOF
+		}
+	}
+}
+
+// chunkStream 按需从上游拉取流式响应块，并以 OpenAI SSE 帧的形式输出
+// next 返回 io.EOF 表示上游正常结束，此时追加 data: [DONE]；
+// 返回其他错误时，已缓冲的数据读完后 Read 返回该错误。
+// 与 OpenAI 一致，只有请求设置了 stream_options.include_usage 时，
+// 才会在 [DONE] 之前发送一个 choices 为空、携带 usage 的响应块
+type chunkStream struct {
+	next         func() ([]*models.ChatCompletionStreamResponse, error)
+	closer       io.Closer
+	includeUsage bool
+	usage        *streamUsage
+	last         models.ChatCompletionStreamResponse
+	buf          bytes.Buffer
+	err          error
+}
+
+func newChunkStream(req *models.ChatCompletionRequest, next func() ([]*models.ChatCompletionStreamResponse, error), closer io.Closer) *chunkStream {
+	return &chunkStream{
+		next:         next,
+		closer:       closer,
+		includeUsage: req != nil && req.StreamOptions != nil && req.StreamOptions.IncludeUsage,
+		usage:        newStreamUsage(req),
+	}
+}
+
+func (s *chunkStream) Read(p []byte) (int, error) {
+	for s.buf.Len() == 0 {
+		if s.err != nil {
+			return 0, s.err
+		}
+
+		chunks, err := s.next()
+		for _, chunk := range chunks {
+			if werr := s.write(chunk); werr != nil {
+				s.err = werr
+				break
+			}
+		}
+
+		switch {
+		case s.err != nil:
+		case err == io.EOF:
+			if s.includeUsage {
+				if werr := s.writeUsage(); werr != nil {
+					s.err = werr
+					break
+				}
+			}
+			s.buf.WriteString("data: " + SSEDone + "\n\n")
+			s.err = io.EOF
+		case err != nil:
+			s.err = err
+		}
+	}
+	return s.buf.Read(p)
+}
+
+// write 记录用量后写出响应块，用量统一在流结束时单独发送
+func (s *chunkStream) write(chunk *models.ChatCompletionStreamResponse) error {
+	s.usage.observe(chunk)
+	s.last = *chunk
+
+	if len(chunk.Choices) == 0 {
+		return nil
+	}
+	out := *chunk
+	out.Usage = nil
+	return writeSSEData(&s.buf, &out)
+}
+
+func (s *chunkStream) writeUsage() error {
+	usage := s.usage.usage()
+	return writeSSEData(&s.buf, &models.ChatCompletionStreamResponse{
+		ID:                s.last.ID,
+		Object:            "chat.completion.chunk",
+		Created:           s.last.Created,
+		Model:             s.last.Model,
+		Choices:           []models.ChatCompletionStreamChoice{},
+		Usage:             &usage,
+		SystemFingerprint: s.last.SystemFingerprint,
+	})
+}
+
+func (s *chunkStream) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// writeSSEData 将 v 序列化为 JSON 并写出一个 data 帧
+func writeSSEData(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(append([]byte("data: "), data...), '\n', '\n'))
+	return err
+}
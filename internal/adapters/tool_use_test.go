@@ -15,6 +15,7 @@ func TestToolUseSupport(t *testing.T) {
 	}{
 		{"OpenAI", "openai", true},
 		{"Claude", "claude", true},
+		{"Gemini", "gemini", true},
 		{"OpenRouter", "openrouter", true},
 		{"Groq", "groq", true},
 		{"DeepSeek", "deepseek", true},
@@ -26,7 +27,7 @@ func TestToolUseSupport(t *testing.T) {
 		{"Cohere", "cohere", true},
 		{"Novita", "novita", true},
 		{"xAI", "xai", true},
-		{"Qwen", "qwen", false},
+		{"Qwen", "qwen", true},
 		{"Baichuan", "baichuan", false},
 		{"ChatGLM", "chatglm", false},
 		{"Ernie", "ernie", false},
@@ -36,7 +37,7 @@ func TestToolUseSupport(t *testing.T) {
 		{"MiniMax", "minimax", false},
 		{"Yi", "yi", false},
 		{"Doubao", "doubao", false},
-		{"Ollama", "ollama", false},
+		{"Ollama", "ollama", true},
 		{"Coze", "coze", false},
 	}
 
@@ -141,14 +142,11 @@ func TestClaudeToolSupport(t *testing.T) {
 }
 
 func TestToolUseErrorHandling(t *testing.T) {
-	// 测试 Qwen 适配器的错误处理
-	adapter := &QwenAdapter{
-		apiKey:  "test-key",
-		baseURL: "https://test.com",
-	}
+	// 测试不支持工具调用的提供商的错误处理
+	adapter := NewOpenAICompatibleAdapter("baichuan", "test-key", "https://test.com", "", "")
 
 	req := &models.ChatCompletionRequest{
-		Model: "qwen-turbo",
+		Model: "baichuan2-turbo",
 		Messages: []models.ChatMessage{
 			{Role: "user", Content: "Test"},
 		},
@@ -164,10 +162,10 @@ func TestToolUseErrorHandling(t *testing.T) {
 
 	_, err := adapter.ChatCompletion(context.Background(), req)
 	if err == nil {
-		t.Error("Expected error for tool use with Qwen, got nil")
+		t.Error("Expected error for tool use with Baichuan, got nil")
 	}
 
-	expectedMsg := "tool use not supported for Qwen models"
+	expectedMsg := "tool use not supported for provider baichuan"
 	if err.Error() != expectedMsg {
 		t.Errorf("Expected error message '%s', got '%s'", expectedMsg, err.Error())
 	}
@@ -180,4 +178,4 @@ func floatPtr(f float64) *float64 {
 
 func intPtr(i int) *int {
 	return &i
-}
\ No newline at end of file
+}
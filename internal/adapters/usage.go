@@ -0,0 +1,93 @@
+package adapters
+
+import (
+	"unicode"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// 每条消息的固定开销（角色、分隔符等），与 OpenAI 的计数方式保持一致的量级
+const messageTokenOverhead = 4
+
+// estimateTokens 粗略估算文本的 token 数
+// 中日韩字符按每字 1 个 token 计算，其余字符按每 4 个字符 1 个 token 计算
+func estimateTokens(text string) int {
+	tokens, others := 0, 0
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			tokens++
+		} else {
+			others++
+		}
+	}
+	return tokens + (others+3)/4
+}
+
+// estimatePromptTokens 粗略估算请求消息的 token 数
+func estimatePromptTokens(req *models.ChatCompletionRequest) int {
+	if req == nil {
+		return 0
+	}
+
+	tokens := 0
+	for _, msg := range req.Messages {
+		tokens += messageTokenOverhead
+		switch v := msg.Content.(type) {
+		case string:
+			tokens += estimateTokens(v)
+		case nil:
+		default:
+			// 图片等非文本内容不计入估算
+			tokens += estimateTokens(contentToText(v))
+		}
+		for _, tc := range msg.ToolCalls {
+			tokens += estimateTokens(tc.Function.Name) + estimateTokens(tc.Function.Arguments)
+		}
+	}
+	return tokens
+}
+
+// streamUsage 记录流式响应中的用量
+// 提供商返回原生用量时直接采用，否则根据请求和输出内容估算
+type streamUsage struct {
+	native     *models.Usage
+	prompt     int
+	completion []byte
+}
+
+func newStreamUsage(req *models.ChatCompletionRequest) *streamUsage {
+	return &streamUsage{prompt: estimatePromptTokens(req)}
+}
+
+// observe 记录响应块中的输出内容和原生用量
+func (u *streamUsage) observe(chunk *models.ChatCompletionStreamResponse) {
+	if chunk.Usage != nil {
+		usage := *chunk.Usage
+		u.native = &usage
+	}
+	for _, choice := range chunk.Choices {
+		u.completion = append(u.completion, choice.Delta.Content...)
+		for _, tc := range choice.Delta.ToolCalls {
+			u.completion = append(u.completion, tc.Function.Name...)
+			u.completion = append(u.completion, tc.Function.Arguments...)
+		}
+	}
+}
+
+// usage 返回最终用量
+func (u *streamUsage) usage() models.Usage {
+	if u.native != nil {
+		usage := *u.native
+		if usage.TotalTokens == 0 {
+			usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+		}
+		return usage
+	}
+
+	completion := estimateTokens(string(u.completion))
+	return models.Usage{
+		PromptTokens:     u.prompt,
+		CompletionTokens: completion,
+		TotalTokens:      u.prompt + completion,
+	}
+}
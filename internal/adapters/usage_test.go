@@ -0,0 +1,174 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"abcd", 1},
+		{"hello world", 3},
+		{"你好世界", 4},
+		{"你好 ab", 3},
+	}
+	for _, tt := range tests {
+		if got := estimateTokens(tt.text); got != tt.want {
+			t.Errorf("estimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+// newUsageTestServer 返回一个不携带用量的 OpenAI 兼容流式上游，并记录收到的请求体
+func newUsageTestServer(t *testing.T, gotBody *map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(gotBody)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"c1\",\"object\":\"chat.completion.chunk\",\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"Hello there\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"id\":\"c1\",\"object\":\"chat.completion.chunk\",\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+}
+
+func TestStreamUsage_EstimatedWhenMissing(t *testing.T) {
+	var gotBody map[string]interface{}
+	server := newUsageTestServer(t, &gotBody)
+	defer server.Close()
+
+	adapter := NewOpenAICompatibleAdapter(Provider("yi"), "test-key", server.URL, "/chat/completions", "Bearer")
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:         "m",
+		Messages:      []models.ChatMessage{{Role: "user", Content: "Say hello"}},
+		StreamOptions: &models.StreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	// 未声明支持 stream_options 的提供商不转发，用量由 chunkStream 估算
+	if _, ok := gotBody["stream_options"]; ok {
+		t.Errorf("expected stream_options to be dropped, got %v", gotBody["stream_options"])
+	}
+
+	chunks, done := collectStreamChunks(t, stream)
+	if !done || len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks and [DONE], got %d chunks (done=%v)", len(chunks), done)
+	}
+
+	usage := chunks[2]
+	if len(usage.Choices) != 0 || usage.Usage == nil || usage.ID != "c1" {
+		t.Fatalf("expected trailing usage chunk, got %+v", usage)
+	}
+	// "Say hello" 3 个 token + 4 个消息开销；"Hello there" 3 个 token
+	if usage.Usage.PromptTokens != 7 || usage.Usage.CompletionTokens != 3 || usage.Usage.TotalTokens != 10 {
+		t.Errorf("unexpected estimated usage: %+v", usage.Usage)
+	}
+}
+
+func TestStreamUsage_ForwardsStreamOptionsWhenSupported(t *testing.T) {
+	var gotBody map[string]interface{}
+	server := newUsageTestServer(t, &gotBody)
+	defer server.Close()
+
+	adapter := NewOpenAICompatibleAdapter(Provider("groq"), "test-key", server.URL, "/chat/completions", "Bearer")
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:         "m",
+		Messages:      []models.ChatMessage{{Role: "user", Content: "Say hello"}},
+		StreamOptions: &models.StreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	stream.Close()
+
+	if opts, ok := gotBody["stream_options"].(map[string]interface{}); !ok || opts["include_usage"] != true {
+		t.Errorf("expected stream_options to be forwarded, got %v", gotBody["stream_options"])
+	}
+}
+
+func TestStreamUsage_OmittedByDefault(t *testing.T) {
+	var gotBody map[string]interface{}
+	server := newUsageTestServer(t, &gotBody)
+	defer server.Close()
+
+	adapter := NewOpenAICompatibleAdapter(Provider("yi"), "test-key", server.URL, "/chat/completions", "Bearer")
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "m",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Say hello"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, _ := collectStreamChunks(t, stream)
+	for _, chunk := range chunks {
+		if chunk.Usage != nil {
+			t.Errorf("expected no usage without include_usage, got %+v", chunk.Usage)
+		}
+	}
+	if len(chunks) != 2 {
+		t.Errorf("expected 2 chunks, got %d", len(chunks))
+	}
+}
+
+func TestStreamUsage_NativeGeminiUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"candidates":[{"content":{"parts":[{"text":"Hi"}],"role":"model"},"finishReason":"STOP","index":0}],"usageMetadata":{"promptTokenCount":11,"candidatesTokenCount":2,"totalTokenCount":13}}]`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewGeminiAdapter("test-key", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:         "gemini-1.5-flash",
+		Messages:      []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		StreamOptions: &models.StreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, _ := collectStreamChunks(t, stream)
+	last := chunks[len(chunks)-1]
+	if last.Usage == nil || last.Usage.PromptTokens != 11 || last.Usage.CompletionTokens != 2 || last.Usage.TotalTokens != 13 {
+		t.Errorf("unexpected usage: %+v", last.Usage)
+	}
+}
+
+func TestStreamUsage_GeminiUsageOnlyEvent(t *testing.T) {
+	// 最后一个事件只有 usageMetadata，没有候选增量
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"candidates":[{"content":{"parts":[{"text":"Hi"}],"role":"model"},"finishReason":"STOP","index":0}]},
+{"usageMetadata":{"promptTokenCount":11,"candidatesTokenCount":2,"totalTokenCount":13}}]`)
+	}))
+	defer server.Close()
+
+	adapter, _ := NewGeminiAdapter("test-key", server.URL)
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:         "gemini-1.5-flash",
+		Messages:      []models.ChatMessage{{Role: "user", Content: "Hi"}},
+		StreamOptions: &models.StreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	chunks, _ := collectStreamChunks(t, stream)
+	last := chunks[len(chunks)-1]
+	if len(last.Choices) != 0 || last.Usage == nil || last.Usage.PromptTokens != 11 || last.Usage.CompletionTokens != 2 || last.Usage.TotalTokens != 13 {
+		t.Errorf("expected upstream usage in trailing chunk, got %+v", last)
+	}
+}
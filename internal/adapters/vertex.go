@@ -0,0 +1,218 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// vertexDefaultRegion 未配置地域时使用的地域
+const vertexDefaultRegion = "us-central1"
+
+// Vertex AI 上 Anthropic 模型要求的 anthropic_version
+const vertexAnthropicVersion = "vertex-2023-10-16"
+
+// VertexAdapter Google Vertex AI 适配器
+// apiKey 为服务账号 JSON 密钥内容或密钥文件路径，使用自签名 JWT 换取 access_token
+// Gemini 模型调用 generateContent，claude- 开头的 Anthropic 模型调用 rawPredict
+type VertexAdapter struct {
+	baseURL string
+	project string
+	region  string
+	tokens  *googleTokenSource
+	client  *http.Client
+
+	// 复用 Gemini 与 Claude 的请求和响应转换
+	gemini *GeminiAdapter
+	claude *ClaudeAdapter
+}
+
+// NewVertexAdapter 创建 Google Vertex AI 适配器（导出以供注册）
+func NewVertexAdapter(apiKey, baseURL string) (Adapter, error) {
+	return NewVertexAdapterWithOptions(apiKey, baseURL, AdapterOptions{})
+}
+
+// NewVertexAdapterWithOptions 创建指定地域的 Google Vertex AI 适配器
+// baseURL 默认为 https://{region}-aiplatform.googleapis.com/v1，项目取自服务账号的 project_id
+func NewVertexAdapterWithOptions(apiKey, baseURL string, opts AdapterOptions) (Adapter, error) {
+	tokens, err := sharedGoogleTokenSource(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	account := tokens.account
+	if account.ProjectID == "" {
+		return nil, fmt.Errorf("service account key has no project_id")
+	}
+
+	region := opts.Region
+	if region == "" {
+		region = vertexDefaultRegion
+	}
+	if baseURL == "" {
+		baseURL = "https://" + region + "-aiplatform.googleapis.com/v1"
+		if region == "global" {
+			baseURL = "https://aiplatform.googleapis.com/v1"
+		}
+	}
+
+	return &VertexAdapter{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		project: account.ProjectID,
+		region:  region,
+		tokens:  tokens,
+		client:  newStreamingHTTPClient(60 * time.Second),
+		gemini:  &GeminiAdapter{},
+		claude:  &ClaudeAdapter{},
+	}, nil
+}
+
+func (a *VertexAdapter) supportsDocuments() {}
+
+func (a *VertexAdapter) GetProvider() Provider {
+	return Provider("vertex")
+}
+
+func (a *VertexAdapter) ChatCompletion(ctx context.Context, req *models.ChatCompletionRequest) (*models.ChatCompletionResponse, error) {
+	if isVertexAnthropicModel(req.Model) {
+		claudeReq, err := a.convertToVertexClaudeRequest(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert request: %w", err)
+		}
+
+		resp, err := a.do(ctx, a.modelURL("anthropic", req.Model, "rawPredict"), claudeReq)
+		if err != nil {
+			return nil, fmt.Errorf("vertex api error: %w", err)
+		}
+		defer resp.Body.Close()
+
+		var claudeResp ClaudeResponse
+		if err := json.NewDecoder(resp.Body).Decode(&claudeResp); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return a.claude.convertFromClaudeResponse(&claudeResp, req.Model), nil
+	}
+
+	geminiReq, err := a.gemini.convertToGeminiRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+
+	resp, err := a.do(ctx, a.modelURL("google", req.Model, "generateContent"), geminiReq)
+	if err != nil {
+		return nil, fmt.Errorf("vertex api error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var geminiResp GeminiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&geminiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return a.gemini.convertFromGeminiResponse(&geminiResp, req.Model), nil
+}
+
+func (a *VertexAdapter) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
+	if isVertexAnthropicModel(req.Model) {
+		claudeReq, err := a.convertToVertexClaudeRequest(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert request: %w", err)
+		}
+		claudeReq.Stream = true
+
+		resp, err := a.do(ctx, a.modelURL("anthropic", req.Model, "streamRawPredict"), claudeReq)
+		if err != nil {
+			return nil, fmt.Errorf("vertex stream error: %w", err)
+		}
+		// 与 Anthropic API 相同的事件流
+		return newClaudeStream(resp.Body, req), nil
+	}
+
+	geminiReq, err := a.gemini.convertToGeminiRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert request: %w", err)
+	}
+
+	resp, err := a.do(ctx, a.modelURL("google", req.Model, "streamGenerateContent"), geminiReq)
+	if err != nil {
+		return nil, fmt.Errorf("vertex stream error: %w", err)
+	}
+	// 与 Gemini API 相同，返回 JSON 数组
+	return newGeminiStream(resp.Body, req), nil
+}
+
+// modelURL 拼接发布方模型的接口地址
+func (a *VertexAdapter) modelURL(publisher, model, method string) string {
+	return fmt.Sprintf("%s/projects/%s/locations/%s/publishers/%s/models/%s:%s",
+		a.baseURL, a.project, a.region, publisher, model, method)
+}
+
+// do 携带 access_token 发送请求，返回 401 时刷新令牌后重试一次
+func (a *VertexAdapter) do(ctx context.Context, endpoint string, body interface{}) (*http.Response, error) {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		token, err := a.tokens.accessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(reqBody))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := a.client.Do(httpReq)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			resp.Body.Close()
+			a.tokens.invalidateToken()
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("status %d, body: %s", resp.StatusCode, string(body))
+		}
+		return resp, nil
+	}
+}
+
+// VertexClaudeRequest rawPredict 的请求体，模型位于 URL 中，需要省略 model 字段
+type VertexClaudeRequest struct {
+	*ClaudeRequest
+	Model            string `json:"model,omitempty"` // 覆盖 ClaudeRequest.Model，保持为空
+	AnthropicVersion string `json:"anthropic_version"`
+}
+
+func (a *VertexAdapter) convertToVertexClaudeRequest(req *models.ChatCompletionRequest) (*VertexClaudeRequest, error) {
+	if (len(req.Tools) > 0 || len(req.Functions) > 0) && !a.claude.supportsTools(req.Model) {
+		return nil, fmt.Errorf("tool use not supported for model %s", req.Model)
+	}
+	claudeReq, err := a.claude.convertToClaudeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return &VertexClaudeRequest{
+		ClaudeRequest:    claudeReq,
+		AnthropicVersion: vertexAnthropicVersion,
+	}, nil
+}
+
+// isVertexAnthropicModel 判断是否为 Vertex AI 上的 Anthropic 模型，如 claude-3-5-sonnet-v2@20241022
+func isVertexAnthropicModel(model string) bool {
+	return strings.HasPrefix(model, "claude-")
+}
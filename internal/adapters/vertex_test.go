@@ -0,0 +1,285 @@
+package adapters
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// newTestServiceAccount 生成测试用的服务账号密钥
+func newTestServiceAccount(t *testing.T, tokenURI string) (string, *rsa.PublicKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	account, _ := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "my-project",
+		"private_key_id": "key-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email":   "llmhub@my-project.iam.gserviceaccount.com",
+		"token_uri":      tokenURI,
+	})
+	return string(account), &key.PublicKey
+}
+
+// verifyJWTAssertion 校验授权断言的签名和声明
+func verifyJWTAssertion(t *testing.T, assertion string, publicKey *rsa.PublicKey, audience string) {
+	t.Helper()
+	parts := strings.Split(assertion, ".")
+	if len(parts) != 3 {
+		t.Fatalf("invalid jwt %q", assertion)
+	}
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature); err != nil {
+		t.Errorf("invalid jwt signature: %v", err)
+	}
+
+	var header, claims map[string]interface{}
+	data, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	json.Unmarshal(data, &header)
+	data, _ = base64.RawURLEncoding.DecodeString(parts[1])
+	json.Unmarshal(data, &claims)
+	if header["alg"] != "RS256" || header["kid"] != "key-1" {
+		t.Errorf("unexpected jwt header: %v", header)
+	}
+	if claims["iss"] != "llmhub@my-project.iam.gserviceaccount.com" || claims["aud"] != audience ||
+		claims["scope"] != "https://www.googleapis.com/auth/cloud-platform" {
+		t.Errorf("unexpected jwt claims: %v", claims)
+	}
+	if exp, iat := claims["exp"].(float64), claims["iat"].(float64); exp-iat != 3600 {
+		t.Errorf("expected one hour lifetime, got %v", exp-iat)
+	}
+}
+
+// newVertexStub 启动同时提供令牌接口和模型接口的测试服务，handler 处理模型请求
+func newVertexStub(t *testing.T, handler http.HandlerFunc) (server *httptest.Server, adapter Adapter, tokenRequests *int32) {
+	t.Helper()
+	tokenRequests = new(int32)
+	var publicKey *rsa.PublicKey
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			n := atomic.AddInt32(tokenRequests, 1)
+			r.ParseForm()
+			if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+				t.Errorf("unexpected grant_type %q", r.Form.Get("grant_type"))
+			}
+			verifyJWTAssertion(t, r.Form.Get("assertion"), publicKey, "http://"+r.Host+"/token")
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":3599,"token_type":"Bearer"}`, n)
+			return
+		}
+		handler(w, r)
+	}))
+
+	var account string
+	account, publicKey = newTestServiceAccount(t, server.URL+"/token")
+	adapter, err := NewVertexAdapterWithOptions(account, server.URL+"/v1", AdapterOptions{Region: "europe-west4"})
+	if err != nil {
+		t.Fatalf("NewVertexAdapterWithOptions() error = %v", err)
+	}
+	return server, adapter, tokenRequests
+}
+
+func TestVertexAdapter_ChatCompletion(t *testing.T) {
+	server, adapter, tokenRequests := newVertexStub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/projects/my-project/locations/europe-west4/publishers/google/models/gemini-1.5-pro:generateContent" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer token-1" {
+			t.Errorf("expected cached token, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"candidates":[{"content":{"role":"model","parts":[{"text":"Hi there"}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":3,"candidatesTokenCount":2,"totalTokenCount":5}}`)
+	})
+	defer server.Close()
+
+	req := &models.ChatCompletionRequest{
+		Model:    "gemini-1.5-pro",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hello"}},
+	}
+	for i := 0; i < 2; i++ {
+		resp, err := adapter.ChatCompletion(context.Background(), req)
+		if err != nil {
+			t.Fatalf("ChatCompletion() error = %v", err)
+		}
+		if resp.Choices[0].Message.Content != "Hi there" || resp.Usage.TotalTokens != 5 {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+	}
+	if n := atomic.LoadInt32(tokenRequests); n != 1 {
+		t.Errorf("expected access token to be cached, got %d token requests", n)
+	}
+}
+
+func TestVertexAdapter_RefreshOnUnauthorized(t *testing.T) {
+	server, adapter, tokenRequests := newVertexStub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":{"code":401,"status":"UNAUTHENTICATED"}}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"candidates":[{"content":{"role":"model","parts":[{"text":"ok"}]},"finishReason":"STOP"}]}`)
+	})
+	defer server.Close()
+
+	_, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "gemini-1.5-pro",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hello"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+	if n := atomic.LoadInt32(tokenRequests); n != 2 {
+		t.Errorf("expected token refresh after 401, got %d token requests", n)
+	}
+}
+
+func TestVertexAdapter_AnthropicRawPredict(t *testing.T) {
+	var gotBody map[string]interface{}
+	server, adapter, _ := newVertexStub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/projects/my-project/locations/europe-west4/publishers/anthropic/models/claude-3-5-sonnet-v2@20241022:rawPredict" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"msg_1","type":"message","role":"assistant","content":[{"type":"text","text":"Hello!"}],"stop_reason":"end_turn","usage":{"input_tokens":4,"output_tokens":2}}`)
+	})
+	defer server.Close()
+
+	resp, err := adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+		Model:    "claude-3-5-sonnet-v2@20241022",
+		Messages: []models.ChatMessage{{Role: "system", Content: "Be brief."}, {Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletion() error = %v", err)
+	}
+
+	if gotBody["anthropic_version"] != "vertex-2023-10-16" || gotBody["system"] != "Be brief." {
+		t.Errorf("unexpected request body: %v", gotBody)
+	}
+	if _, ok := gotBody["model"]; ok {
+		t.Errorf("expected model to be omitted, got %v", gotBody["model"])
+	}
+	if resp.Choices[0].Message.Content != "Hello!" || resp.Choices[0].FinishReason != "stop" || resp.Usage.TotalTokens != 6 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestVertexAdapter_AnthropicStream(t *testing.T) {
+	server, adapter, _ := newVertexStub(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/publishers/anthropic/models/claude-3-5-haiku@20241022:streamRawPredict") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["stream"] != true {
+			t.Errorf("expected stream=true, got %v", body["stream"])
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_1\",\"usage\":{\"input_tokens\":4,\"output_tokens\":1}}}\n\n")
+		fmt.Fprint(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"Hello\"}}\n\n")
+		fmt.Fprint(w, "event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"end_turn\"},\"usage\":{\"output_tokens\":2}}\n\n")
+		fmt.Fprint(w, "event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
+	})
+	defer server.Close()
+
+	stream, err := adapter.ChatCompletionStream(context.Background(), &models.ChatCompletionRequest{
+		Model:    "claude-3-5-haiku@20241022",
+		Messages: []models.ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		t.Fatalf("read stream: %v", err)
+	}
+	out := string(data)
+	if !strings.Contains(out, `"content":"Hello"`) || !strings.Contains(out, `"finish_reason":"stop"`) || !strings.HasSuffix(out, "data: [DONE]\n\n") {
+		t.Errorf("unexpected stream output:\n%s", out)
+	}
+}
+
+func TestVertexAdapter_SharesTokenAcrossAdapters(t *testing.T) {
+	var tokenRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			atomic.AddInt32(&tokenRequests, 1)
+			fmt.Fprint(w, `{"access_token":"token-1","expires_in":3599,"token_type":"Bearer"}`)
+			return
+		}
+		fmt.Fprint(w, `{"candidates":[{"content":{"role":"model","parts":[{"text":"ok"}]},"finishReason":"STOP"}]}`)
+	}))
+	defer server.Close()
+
+	account, _ := newTestServiceAccount(t, server.URL+"/token")
+	keyFile := filepath.Join(t.TempDir(), "key.json")
+	if err := os.WriteFile(keyFile, []byte(account), 0o600); err != nil {
+		t.Fatalf("write key file: %v", err)
+	}
+
+	// 网关每个请求创建新的适配器，密钥文件只读取一次，access_token 只获取一次
+	for i := 0; i < 2; i++ {
+		adapter, err := NewVertexAdapter(keyFile, server.URL+"/v1")
+		if err != nil {
+			t.Fatalf("NewVertexAdapter() error = %v", err)
+		}
+		_, err = adapter.ChatCompletion(context.Background(), &models.ChatCompletionRequest{
+			Model:    "gemini-1.5-pro",
+			Messages: []models.ChatMessage{{Role: "user", Content: "Hello"}},
+		})
+		if err != nil {
+			t.Fatalf("ChatCompletion() error = %v", err)
+		}
+		os.Remove(keyFile)
+	}
+	if n := atomic.LoadInt32(&tokenRequests); n != 1 {
+		t.Errorf("expected one token fetch across adapters, got %d", n)
+	}
+}
+
+func TestNewVertexAdapter_InvalidKey(t *testing.T) {
+	if _, err := NewVertexAdapter(`{"type":"authorized_user"}`, ""); err == nil {
+		t.Error("expected error for non service account key")
+	}
+	if _, err := NewVertexAdapter("/nonexistent/key.json", ""); err == nil {
+		t.Error("expected error for missing key file")
+	}
+
+	account, _ := newTestServiceAccount(t, "")
+	adapter, err := NewVertexAdapter(account, "")
+	if err != nil {
+		t.Fatalf("NewVertexAdapter() error = %v", err)
+	}
+	vertex := adapter.(*VertexAdapter)
+	if vertex.baseURL != "https://us-central1-aiplatform.googleapis.com/v1" || vertex.tokens.account.TokenURI != googleDefaultTokenURI {
+		t.Errorf("unexpected defaults: %s, %s", vertex.baseURL, vertex.tokens.account.TokenURI)
+	}
+}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -46,7 +48,11 @@ func (h *Handler) ChatCompletions(c *gin.Context) {
 	}
 
 	// 创建适配器（将字符串转换为 adapters.Provider）
-	adapter, err := adapters.CreateAdapter(adapters.Provider(modelConfig.Provider), modelConfig.APIKey, modelConfig.BaseURL)
+	adapter, err := adapters.CreateAdapterWithOptions(adapters.Provider(modelConfig.Provider), modelConfig.APIKey, modelConfig.BaseURL, adapters.AdapterOptions{
+		Deployment: modelConfig.Deployment,
+		APIVersion: modelConfig.APIVersion,
+		Region:     modelConfig.Region,
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error: models.ErrorDetail{
@@ -101,19 +107,66 @@ func (h *Handler) handleStreamResponse(c *gin.Context, ctx context.Context, adap
 	c.Header("Connection", "keep-alive")
 	c.Header("X-Accel-Buffering", "no")
 
-	c.Stream(func(w io.Writer) bool {
-		buf := make([]byte, 4096)
-		n, err := stream.Read(buf)
-		if err != nil && err != io.EOF {
-			return false
+	c.Status(http.StatusOK)
+	h.forwardStream(c.Writer, stream)
+}
+
+// forwardStream 逐个解析上游 SSE 事件并原样转发 data 帧
+// 上游中途出错时发送 OpenAI 风格的错误事件，无论如何都以 data: [DONE] 结束
+func (h *Handler) forwardStream(w gin.ResponseWriter, stream io.Reader) {
+	reader := adapters.NewSSEReader(stream)
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			h.writeStreamError(w, err)
+			break
+		}
+
+		data := bytes.TrimSpace(event.Data)
+		if len(data) == 0 {
+			continue
 		}
-		if n > 0 {
-			// 发送 SSE 格式的数据
-			fmt.Fprintf(w, "data: %s\n\n", string(buf[:n]))
-			c.Writer.Flush()
+		if string(data) == adapters.SSEDone {
+			break
+		}
+
+		if err := writeSSEData(w, data); err != nil {
+			// 客户端已断开
+			return
 		}
-		return err == nil
+		w.Flush()
+	}
+
+	writeSSEData(w, []byte(adapters.SSEDone))
+	w.Flush()
+}
+
+// writeSSEData 写出一个 data 事件，多行数据逐行加上 data: 前缀
+func writeSSEData(w io.Writer, data []byte) error {
+	var buf bytes.Buffer
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeStreamError 以 SSE 事件的形式发送错误
+func (h *Handler) writeStreamError(w gin.ResponseWriter, err error) {
+	data, _ := json.Marshal(models.ErrorResponse{
+		Error: models.ErrorDetail{
+			Message: fmt.Sprintf("Stream error: %v", err),
+			Type:    "api_error",
+		},
 	})
+	writeSSEData(w, data)
+	w.Flush()
 }
 
 // Models 返回可用的模型列表
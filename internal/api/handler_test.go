@@ -0,0 +1,64 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func forwardForTest(upstream io.Reader) string {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+
+	NewHandler().forwardStream(c.Writer, upstream)
+	return recorder.Body.String()
+}
+
+func TestForwardStream_Reframes(t *testing.T) {
+	upstream := "event: chunk\ndata: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n" +
+		": keep-alive\n\n" +
+		"data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n" +
+		"data: [DONE]\n\n"
+
+	// 逐字节读取，模拟上游事件被任意切分
+	got := forwardForTest(iotest.OneByteReader(strings.NewReader(upstream)))
+
+	want := "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n" +
+		"data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n" +
+		"data: [DONE]\n\n"
+	if got != want {
+		t.Errorf("unexpected stream output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestForwardStream_AppendsDone(t *testing.T) {
+	got := forwardForTest(strings.NewReader("data: {\"id\":\"1\"}\n\n"))
+
+	if strings.Count(got, "data: [DONE]") != 1 || !strings.HasSuffix(got, "data: [DONE]\n\n") {
+		t.Errorf("expected a single trailing [DONE], got:\n%s", got)
+	}
+}
+
+func TestForwardStream_MidStreamError(t *testing.T) {
+	upstream := io.MultiReader(
+		strings.NewReader("data: {\"id\":\"1\"}\n\n"),
+		iotest.ErrReader(errors.New("connection reset")),
+	)
+	got := forwardForTest(upstream)
+
+	if !strings.HasPrefix(got, "data: {\"id\":\"1\"}\n\n") {
+		t.Errorf("expected first event to be forwarded, got:\n%s", got)
+	}
+	if !strings.Contains(got, `data: {"error":{"message":"Stream error: connection reset","type":"api_error"}}`) {
+		t.Errorf("expected error event, got:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "data: [DONE]\n\n") {
+		t.Errorf("expected stream to end with [DONE], got:\n%s", got)
+	}
+}
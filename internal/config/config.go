@@ -8,9 +8,10 @@ import (
 )
 
 type Config struct {
-	Server ServerConfig `yaml:"server"`
-	Models []ModelConfig `yaml:"models"`
-	Auth   AuthConfig   `yaml:"auth"`
+	Server    ServerConfig     `yaml:"server"`
+	Models    []ModelConfig    `yaml:"models"`
+	Auth      AuthConfig       `yaml:"auth"`
+	Providers []ProviderConfig `yaml:"providers"`
 }
 
 type ServerConfig struct {
@@ -23,6 +24,28 @@ type ModelConfig struct {
 	Provider string `yaml:"provider"`
 	APIKey   string `yaml:"api_key"`
 	BaseURL  string `yaml:"base_url"`
+
+	// Azure OpenAI
+	Deployment string `yaml:"deployment"`
+	APIVersion string `yaml:"api_version"`
+
+	// AWS Bedrock、Google Vertex AI
+	Region string `yaml:"region"`
+}
+
+// ProviderConfig 自定义 OpenAI 兼容提供商，模型配置中通过 provider: name 引用
+type ProviderConfig struct {
+	Name       string            `yaml:"name"`
+	BaseURL    string            `yaml:"base_url"`
+	Endpoint   string            `yaml:"endpoint"`
+	AuthHeader string            `yaml:"auth_header"`
+	AuthScheme string            `yaml:"auth_scheme"`
+	Headers    map[string]string `yaml:"headers"`
+
+	SupportsTools           bool `yaml:"supports_tools"`
+	SupportsVision          bool `yaml:"supports_vision"`
+	SupportsStreamOptions   bool `yaml:"supports_stream_options"`
+	SupportsReasoningEffort bool `yaml:"supports_reasoning_effort"`
 }
 
 type AuthConfig struct {
@@ -2,35 +2,94 @@ package models
 
 // OpenAI 兼容的请求结构
 type ChatCompletionRequest struct {
-	Model            string                 `json:"model"`
-	Messages         []ChatMessage          `json:"messages"`
-	Temperature      *float64               `json:"temperature,omitempty"`
-	TopP             *float64               `json:"top_p,omitempty"`
-	MaxTokens        *int                   `json:"max_tokens,omitempty"`
-	Stream           bool                   `json:"stream,omitempty"`
-	PresencePenalty  *float64               `json:"presence_penalty,omitempty"`
-	FrequencyPenalty *float64               `json:"frequency_penalty,omitempty"`
-	Stop             []string               `json:"stop,omitempty"`
-	User             string                 `json:"user,omitempty"`
-	Functions        []FunctionDefinition  `json:"functions,omitempty"`
-	FunctionCall     interface{}            `json:"function_call,omitempty"`
-	LogitBias       map[string]int         `json:"logit_bias,omitempty"`
-	LogProbs         bool                   `json:"logprobs,omitempty"`
-	TopLogProbs      *int                   `json:"top_logprobs,omitempty"`
-	ResponseFormat   *ResponseFormat        `json:"response_format,omitempty"`
-	Seed             *int                   `json:"seed,omitempty"`
-	Tools            []Tool                 `json:"tools,omitempty"`
-	ToolChoice       interface{}            `json:"tool_choice,omitempty"`
-	ExtraParams      map[string]interface{} `json:"-"`
+	Model       string        `json:"model"`
+	Messages    []ChatMessage `json:"messages"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	TopP        *float64      `json:"top_p,omitempty"`
+	MaxTokens   *int          `json:"max_tokens,omitempty"`
+	// 推理模型的输出上限（含思考过程）与思考强度
+	MaxCompletionTokens *int                   `json:"max_completion_tokens,omitempty"`
+	ReasoningEffort     string                 `json:"reasoning_effort,omitempty"` // "minimal"、"low"、"medium"、"high"
+	Stream              bool                   `json:"stream,omitempty"`
+	StreamOptions       *StreamOptions         `json:"stream_options,omitempty"`
+	PresencePenalty     *float64               `json:"presence_penalty,omitempty"`
+	FrequencyPenalty    *float64               `json:"frequency_penalty,omitempty"`
+	Stop                []string               `json:"stop,omitempty"`
+	User                string                 `json:"user,omitempty"`
+	Functions           []FunctionDefinition   `json:"functions,omitempty"`
+	FunctionCall        interface{}            `json:"function_call,omitempty"`
+	LogitBias           map[string]int         `json:"logit_bias,omitempty"`
+	LogProbs            bool                   `json:"logprobs,omitempty"`
+	TopLogProbs         *int                   `json:"top_logprobs,omitempty"`
+	ResponseFormat      *ResponseFormat        `json:"response_format,omitempty"`
+	Seed                *int                   `json:"seed,omitempty"`
+	Tools               []Tool                 `json:"tools,omitempty"`
+	ToolChoice          interface{}            `json:"tool_choice,omitempty"`
+	KeepAlive           string                 `json:"keep_alive,omitempty"` // Ollama 专用
+	Thinking            *ThinkingConfig        `json:"thinking,omitempty"`   // Claude 专用
+	ExtraParams         map[string]interface{} `json:"-"`
 }
 
 type ChatMessage struct {
-	Role         string          `json:"role"`
-	Content      interface{}     `json:"content"`
-	Name         string          `json:"name,omitempty"`
-	FunctionCall *FunctionCall   `json:"function_call,omitempty"`
-	ToolCalls    []ToolCall      `json:"tool_calls,omitempty"`
-	ToolCallID   string          `json:"tool_call_id,omitempty"`
+	Role         string        `json:"role"`
+	Content      interface{}   `json:"content"`
+	Name         string        `json:"name,omitempty"`
+	FunctionCall *FunctionCall `json:"function_call,omitempty"`
+	ToolCalls    []ToolCall    `json:"tool_calls,omitempty"`
+	ToolCallID   string        `json:"tool_call_id,omitempty"`
+	Citations    []Citation    `json:"citations,omitempty"`
+
+	// 推理模型的思考过程，ThinkingBlocks 保留 Claude 的签名以便多轮对话原样回传
+	ReasoningContent string          `json:"reasoning_content,omitempty"`
+	ThinkingBlocks   []ThinkingBlock `json:"thinking_blocks,omitempty"`
+}
+
+// ContentPart 多模态消息内容片段，ChatMessage.Content 可以是字符串或 []ContentPart
+type ContentPart struct {
+	Type     string    `json:"type"` // "text"、"image_url" 或 "document"
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+	Document *Document `json:"document,omitempty"`
+}
+
+type ImageURL struct {
+	URL    string `json:"url"`              // http(s) 地址或 data:image/png;base64,... 形式的 data URI
+	Detail string `json:"detail,omitempty"` // "auto"、"low"、"high"
+}
+
+// Document 文档输入，目前 Claude、Gemini、Bedrock 和 Vertex AI 支持
+type Document struct {
+	MediaType string `json:"media_type"` // "application/pdf" 或 "text/plain"
+	Data      string `json:"data"`       // PDF 为 base64 数据，text/plain 为文本本身
+	Title     string `json:"title,omitempty"`
+	Citations bool   `json:"citations,omitempty"` // Claude 专用，开启文档引用
+}
+
+type ThinkingBlock struct {
+	Type      string `json:"type"` // "thinking" 或 "redacted_thinking"
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Data      string `json:"data,omitempty"`
+}
+
+type ThinkingConfig struct {
+	Type         string `json:"type"` // "enabled" 或 "disabled"
+	BudgetTokens int    `json:"budget_tokens,omitempty"`
+}
+
+// Citation 回答中引用的来源，Start/End 为引用文本在 Content 中的字符位置
+type Citation struct {
+	Start   int              `json:"start"`
+	End     int              `json:"end"`
+	Text    string           `json:"text"`
+	Sources []CitationSource `json:"sources,omitempty"`
+}
+
+type CitationSource struct {
+	Type  string                 `json:"type"` // "document" 或 "tool"
+	ID    string                 `json:"id,omitempty"`
+	Title string                 `json:"title,omitempty"`
+	Data  map[string]interface{} `json:"data,omitempty"`
 }
 
 type FunctionDefinition struct {
@@ -45,6 +104,7 @@ type FunctionCall struct {
 }
 
 type ToolCall struct {
+	Index    *int         `json:"index,omitempty"` // 流式响应中用于合并工具调用片段
 	ID       string       `json:"id"`
 	Type     string       `json:"type"`
 	Function FunctionCall `json:"function"`
@@ -55,7 +115,19 @@ type Tool struct {
 	Function FunctionDefinition `json:"function"`
 }
 
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage,omitempty"`
+}
+
 type ResponseFormat struct {
-	Type string `json:"type"`
+	Type       string      `json:"type"`
+	JSONSchema *JSONSchema `json:"json_schema,omitempty"`
+}
+
+type JSONSchema struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Schema      interface{} `json:"schema,omitempty"`
+	Strict      bool        `json:"strict,omitempty"`
 }
 
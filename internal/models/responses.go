@@ -1,5 +1,7 @@
 package models
 
+import "encoding/json"
+
 // OpenAI 兼容的响应结构
 type ChatCompletionResponse struct {
 	ID                string                 `json:"id"`
@@ -16,6 +18,60 @@ type ChatCompletionChoice struct {
 	Message      ChatMessage `json:"message"`
 	FinishReason string      `json:"finish_reason"`
 	Delta        *ChatMessage `json:"delta,omitempty"` // 用于流式响应
+
+	ContentFilterResults map[string]ContentFilterResult `json:"content_filter_results,omitempty"` // Azure OpenAI
+}
+
+// ContentFilterResult Azure OpenAI 内容过滤结果，按类别（hate、sexual、jailbreak 等）返回
+type ContentFilterResult struct {
+	Filtered bool   `json:"filtered"`
+	Severity string `json:"severity,omitempty"` // "safe"、"low"、"medium"、"high"
+	Detected bool   `json:"detected,omitempty"` // jailbreak、profanity 等检测类过滤器使用
+}
+
+// OpenAI 兼容的流式响应块（chat.completion.chunk）
+type ChatCompletionStreamResponse struct {
+	ID                string                       `json:"id"`
+	Object            string                       `json:"object"`
+	Created           int64                        `json:"created"`
+	Model             string                       `json:"model"`
+	Choices           []ChatCompletionStreamChoice `json:"choices"`
+	Usage             *Usage                       `json:"usage,omitempty"`
+	SystemFingerprint string                       `json:"system_fingerprint,omitempty"`
+	Error             *ErrorDetail                 `json:"error,omitempty"`
+}
+
+type ChatCompletionStreamChoice struct {
+	Index        int              `json:"index"`
+	Delta        ChatMessageDelta `json:"delta"`
+	FinishReason string           `json:"finish_reason"` // 未结束时序列化为 null
+
+	ContentFilterResults map[string]ContentFilterResult `json:"content_filter_results,omitempty"`
+}
+
+// MarshalJSON 与 OpenAI 一致，每个块都携带 finish_reason，未结束时为 null
+func (c ChatCompletionStreamChoice) MarshalJSON() ([]byte, error) {
+	type choice ChatCompletionStreamChoice
+	var finishReason *string
+	if c.FinishReason != "" {
+		finishReason = &c.FinishReason
+	}
+	return json.Marshal(struct {
+		choice
+		FinishReason *string `json:"finish_reason"`
+	}{choice(c), finishReason})
+}
+
+// 流式响应中的增量消息
+type ChatMessageDelta struct {
+	Role         string        `json:"role,omitempty"`
+	Content      string        `json:"content,omitempty"`
+	FunctionCall *FunctionCall `json:"function_call,omitempty"`
+	ToolCalls    []ToolCall    `json:"tool_calls,omitempty"`
+	Citations    []Citation    `json:"citations,omitempty"`
+
+	ReasoningContent string          `json:"reasoning_content,omitempty"`
+	ThinkingBlocks   []ThinkingBlock `json:"thinking_blocks,omitempty"`
 }
 
 type Usage struct {
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestChatCompletionStreamChoice_FinishReasonNull(t *testing.T) {
+	data, err := json.Marshal(ChatCompletionStreamChoice{Delta: ChatMessageDelta{Content: "Hi"}})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"finish_reason":null`) {
+		t.Errorf("expected finish_reason null, got %s", data)
+	}
+
+	data, _ = json.Marshal(ChatCompletionStreamChoice{FinishReason: "stop"})
+	if strings.Count(string(data), "finish_reason") != 1 || !strings.Contains(string(data), `"finish_reason":"stop"`) {
+		t.Errorf("expected a single finish_reason 'stop', got %s", data)
+	}
+
+	var choice ChatCompletionStreamChoice
+	if err := json.Unmarshal([]byte(`{"index":0,"delta":{},"finish_reason":null}`), &choice); err != nil || choice.FinishReason != "" {
+		t.Errorf("expected null finish_reason to decode as empty, got %q, %v", choice.FinishReason, err)
+	}
+}
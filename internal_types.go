@@ -3,25 +3,35 @@ package llmhub
 // 内部类型定义，用于与 adapters 包交互
 
 type internalChatCompletionRequest struct {
-	Model            string
-	Messages         []internalChatMessage
-	Temperature      *float64
-	TopP             *float64
-	MaxTokens        *int
-	Stream           bool
-	PresencePenalty  *float64
-	FrequencyPenalty *float64
-	Stop             []string
-	User             string
-	Functions        []internalFunctionDefinition
-	FunctionCall     interface{}
-	LogitBias        map[string]int
-	LogProbs         bool
-	TopLogProbs      *int
-	ResponseFormat   *internalResponseFormat
-	Seed             *int
-	Tools            []internalTool
-	ToolChoice       interface{}
+	Model               string
+	Messages            []internalChatMessage
+	Temperature         *float64
+	TopP                *float64
+	MaxTokens           *int
+	MaxCompletionTokens *int
+	ReasoningEffort     string
+	Stream              bool
+	StreamOptions       *internalStreamOptions
+	PresencePenalty     *float64
+	FrequencyPenalty    *float64
+	Stop                []string
+	User                string
+	Functions           []internalFunctionDefinition
+	FunctionCall        interface{}
+	LogitBias           map[string]int
+	LogProbs            bool
+	TopLogProbs         *int
+	ResponseFormat      *internalResponseFormat
+	Seed                *int
+	Tools               []internalTool
+	ToolChoice          interface{}
+	KeepAlive           string
+	Thinking            *internalThinkingConfig
+}
+
+type internalThinkingConfig struct {
+	Type         string
+	BudgetTokens int
 }
 
 type internalChatMessage struct {
@@ -31,6 +41,50 @@ type internalChatMessage struct {
 	FunctionCall *internalFunctionCall
 	ToolCalls    []internalToolCall
 	ToolCallID   string
+	Citations    []internalCitation
+
+	ReasoningContent string
+	ThinkingBlocks   []internalThinkingBlock
+}
+
+type internalContentPart struct {
+	Type     string
+	Text     string
+	ImageURL *internalImageURL
+	Document *internalDocument
+}
+
+type internalDocument struct {
+	MediaType string
+	Data      string
+	Title     string
+	Citations bool
+}
+
+type internalImageURL struct {
+	URL    string
+	Detail string
+}
+
+type internalThinkingBlock struct {
+	Type      string
+	Thinking  string
+	Signature string
+	Data      string
+}
+
+type internalCitation struct {
+	Start   int
+	End     int
+	Text    string
+	Sources []internalCitationSource
+}
+
+type internalCitationSource struct {
+	Type  string
+	ID    string
+	Title string
+	Data  map[string]interface{}
 }
 
 type internalFunctionDefinition struct {
@@ -45,6 +99,7 @@ type internalFunctionCall struct {
 }
 
 type internalToolCall struct {
+	Index    *int
 	ID       string
 	Type     string
 	Function internalFunctionCall
@@ -55,8 +110,20 @@ type internalTool struct {
 	Function internalFunctionDefinition
 }
 
+type internalStreamOptions struct {
+	IncludeUsage bool
+}
+
 type internalResponseFormat struct {
-	Type string
+	Type       string
+	JSONSchema *internalJSONSchema
+}
+
+type internalJSONSchema struct {
+	Name        string
+	Description string
+	Schema      interface{}
+	Strict      bool
 }
 
 type internalChatCompletionResponse struct {
@@ -74,6 +141,14 @@ type internalChatCompletionChoice struct {
 	Message      internalChatMessage
 	FinishReason string
 	Delta        *internalChatMessage
+
+	ContentFilterResults map[string]internalContentFilterResult
+}
+
+type internalContentFilterResult struct {
+	Filtered bool
+	Severity string
+	Detected bool
 }
 
 type internalUsage struct {
@@ -1,5 +1,9 @@
 package llmhub
 
+import (
+	"github.com/gotoailab/llmhub/internal/adapters"
+)
+
 // Provider 支持的模型提供商枚举
 type Provider string
 
@@ -17,6 +21,11 @@ const (
 	ProviderNovita     Provider = "novita"     // novita.ai
 	ProviderOpenRouter Provider = "openrouter" // OpenRouter
 
+	// 云平台
+	ProviderAzureOpenAI Provider = "azure"   // Azure OpenAI
+	ProviderBedrock     Provider = "bedrock" // AWS Bedrock
+	ProviderVertex      Provider = "vertex"  // Google Vertex AI
+
 	// 国内模型
 	ProviderQwen        Provider = "qwen"        // 通义千问
 	ProviderSiliconFlow Provider = "siliconflow" // 硅基流动
@@ -47,7 +56,7 @@ func (p Provider) IsValid() bool {
 	switch p {
 	case ProviderOpenAI, ProviderClaude, ProviderGemini, ProviderMistral,
 		ProviderDeepSeek, ProviderGroq, ProviderCohere, ProviderXAI,
-		ProviderTogether, ProviderNovita, ProviderOpenRouter, ProviderQwen, ProviderSiliconFlow,
+		ProviderTogether, ProviderNovita, ProviderOpenRouter, ProviderAzureOpenAI, ProviderBedrock, ProviderVertex, ProviderQwen, ProviderSiliconFlow,
 		ProviderDoubao, ProviderErnie, ProviderSpark, ProviderChatGLM,
 		Provider360, ProviderHunyuan, ProviderMoonshot, ProviderBaichuan,
 		ProviderMiniMax, ProviderYi, ProviderStepFun, ProviderCoze,
@@ -72,6 +81,9 @@ func AllProviders() []Provider {
 		ProviderTogether,
 		ProviderNovita,
 		ProviderOpenRouter,
+		ProviderAzureOpenAI,
+		ProviderBedrock,
+		ProviderVertex,
 		ProviderQwen,
 		ProviderSiliconFlow,
 		ProviderDoubao,
@@ -89,3 +101,58 @@ func AllProviders() []Provider {
 		ProviderOllama,
 	}
 }
+
+// CustomProvider 通过配置声明的 OpenAI 兼容提供商，无需新增适配器代码即可接入
+// 如 Perplexity、Fireworks、Cerebras、vLLM、LM Studio
+type CustomProvider struct {
+	// Name 提供商名称，注册后可作为 ClientConfig.Provider 使用
+	Name string
+
+	// BaseURL API 基础 URL，如 https://api.perplexity.ai
+	BaseURL string
+
+	// Endpoint 接口路径（可选，默认为 /chat/completions）
+	Endpoint string
+
+	// AuthHeader 认证头名称（可选，默认为 Authorization）
+	AuthHeader string
+
+	// AuthScheme 认证方案，如 Bearer（可选，AuthHeader 为空时默认为 Bearer，否则直接发送 API Key）
+	AuthScheme string
+
+	// Headers 每个请求附带的额外静态请求头（可选）
+	Headers map[string]string
+
+	// SupportsTools 是否支持工具调用
+	SupportsTools bool
+
+	// SupportsVision 是否接受图片输入
+	SupportsVision bool
+
+	// SupportsStreamOptions 是否转发 stream_options（流式返回用量）
+	SupportsStreamOptions bool
+
+	// SupportsReasoningEffort 是否转发 reasoning_effort
+	SupportsReasoningEffort bool
+}
+
+func (p CustomProvider) toAdapterConfig() adapters.CustomProviderConfig {
+	return adapters.CustomProviderConfig{
+		Name:                    p.Name,
+		BaseURL:                 p.BaseURL,
+		Endpoint:                p.Endpoint,
+		AuthHeader:              p.AuthHeader,
+		AuthScheme:              p.AuthScheme,
+		Headers:                 p.Headers,
+		SupportsTools:           p.SupportsTools,
+		SupportsVision:          p.SupportsVision,
+		SupportsStreamOptions:   p.SupportsStreamOptions,
+		SupportsReasoningEffort: p.SupportsReasoningEffort,
+	}
+}
+
+// RegisterProvider 注册自定义提供商，之后可通过 Provider(p.Name) 创建客户端
+// 名称与内置提供商冲突时返回错误，重复注册同名自定义提供商会更新其配置
+func RegisterProvider(p CustomProvider) error {
+	return adapters.RegisterCustomProvider(p.toAdapterConfig())
+}
@@ -0,0 +1,83 @@
+package llmhub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gotoailab/llmhub/internal/adapters"
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// ChatCompletionStream 流式聊天完成的读取器
+// 无论底层是哪个提供商，Recv 返回的都是 OpenAI 风格的 chat.completion.chunk，
+// 增量内容在 Choices[i].Delta 中
+type ChatCompletionStream struct {
+	client *Client
+	body   io.ReadCloser
+	reader *adapters.SSEReader
+	err    error
+}
+
+func newChatCompletionStream(client *Client, body io.ReadCloser) *ChatCompletionStream {
+	return &ChatCompletionStream{
+		client: client,
+		body:   body,
+		reader: adapters.NewSSEReader(body),
+	}
+}
+
+// Recv 读取下一个流式响应块，流结束（收到 [DONE]）后返回 io.EOF，
+// 未收到 [DONE] 连接就已关闭时返回 io.ErrUnexpectedEOF
+func (s *ChatCompletionStream) Recv() (*ChatCompletionResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	for {
+		event, err := s.reader.Next()
+		if err == io.EOF {
+			// 上游提前断开，输出可能不完整
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			s.err = err
+			return nil, err
+		}
+
+		data := bytes.TrimSpace(event.Data)
+		if len(data) == 0 {
+			continue
+		}
+		if string(data) == adapters.SSEDone {
+			s.err = io.EOF
+			return nil, io.EOF
+		}
+
+		var chunk models.ChatCompletionStreamResponse
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			s.err = fmt.Errorf("failed to decode stream chunk: %w", err)
+			return nil, s.err
+		}
+		if chunk.Error != nil {
+			s.err = fmt.Errorf("stream error: %s", chunk.Error.Message)
+			return nil, s.err
+		}
+
+		// 跳过既没有增量也没有用量的事件（如心跳）
+		if len(chunk.Choices) == 0 && chunk.Usage == nil {
+			continue
+		}
+		if chunk.Object == "" {
+			chunk.Object = "chat.completion.chunk"
+		}
+
+		return s.client.toPublicResponse(s.client.adapter.toInternalStreamResponse(&chunk)), nil
+	}
+}
+
+// Close 关闭底层连接
+func (s *ChatCompletionStream) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,30 @@
+//go:build go1.23
+
+package llmhub
+
+import (
+	"io"
+	"iter"
+)
+
+// All 返回遍历流式响应块的迭代器，流正常结束时迭代停止，出错时产出一次错误后停止
+//
+//	for chunk, err := range stream.All() {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Print(chunk.Choices[0].Delta.Content)
+//	}
+func (s *ChatCompletionStream) All() iter.Seq2[*ChatCompletionResponse, error] {
+	return func(yield func(*ChatCompletionResponse, error) bool) {
+		for {
+			chunk, err := s.Recv()
+			if err == io.EOF {
+				return
+			}
+			if !yield(chunk, err) || err != nil {
+				return
+			}
+		}
+	}
+}
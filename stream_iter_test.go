@@ -0,0 +1,38 @@
+//go:build go1.23
+
+package llmhub
+
+import (
+	"context"
+	"testing"
+)
+
+func TestChatCompletionStream_All(t *testing.T) {
+	body := "data: {\"id\":\"c1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"a\"}}]}\n\n" +
+		"data: {\"id\":\"c1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"b\"}}]}\n\n" +
+		"data: [DONE]\n\n"
+
+	client := newFakeStreamClient(body)
+	stream, err := client.ChatCompletionsStream(context.Background(), ChatCompletionRequest{
+		Messages: []ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionsStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	var content string
+	for chunk, err := range stream.All() {
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		content += chunk.Choices[0].Delta.Content.(string)
+		if chunk.Object != "chat.completion.chunk" {
+			t.Errorf("expected object 'chat.completion.chunk', got %q", chunk.Object)
+		}
+	}
+
+	if content != "ab" {
+		t.Errorf("expected content 'ab', got %q", content)
+	}
+}
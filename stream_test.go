@@ -0,0 +1,130 @@
+package llmhub
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gotoailab/llmhub/internal/adapters"
+	"github.com/gotoailab/llmhub/internal/models"
+)
+
+// fakeStreamAdapter 返回固定 SSE 内容的测试适配器
+type fakeStreamAdapter struct {
+	body string
+}
+
+func (a *fakeStreamAdapter) ChatCompletion(ctx context.Context, req *models.ChatCompletionRequest) (*models.ChatCompletionResponse, error) {
+	return nil, io.ErrUnexpectedEOF
+}
+
+func (a *fakeStreamAdapter) ChatCompletionStream(ctx context.Context, req *models.ChatCompletionRequest) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(a.body)), nil
+}
+
+func (a *fakeStreamAdapter) GetProvider() adapters.Provider {
+	return adapters.Provider("fake")
+}
+
+func newFakeStreamClient(body string) *Client {
+	return &Client{
+		adapter: &adapterWrapper{adapter: &fakeStreamAdapter{body: body}},
+		model:   "test-model",
+	}
+}
+
+func TestChatCompletionStream_Recv(t *testing.T) {
+	body := ": keep-alive\n\n" +
+		"data: {\"id\":\"c1\",\"object\":\"chat.completion.chunk\",\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"Hel\"}}]}\n\n" +
+		"data: {\"id\":\"c1\",\"object\":\"chat.completion.chunk\",\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"lo\"}}]}\n\n" +
+		"data: {\"id\":\"c1\",\"object\":\"chat.completion.chunk\",\"model\":\"m\",\"choices\":[{\"index\":0,\"delta\":{\"tool_calls\":[{\"index\":0,\"id\":\"call_1\",\"type\":\"function\",\"function\":{\"name\":\"f\",\"arguments\":\"{}\"}}]},\"finish_reason\":\"tool_calls\"}]}\n\n" +
+		"data: [DONE]\n\n"
+
+	client := newFakeStreamClient(body)
+	stream, err := client.ChatCompletionsStream(context.Background(), ChatCompletionRequest{
+		Messages: []ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionsStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	var content strings.Builder
+	var chunks []*ChatCompletionResponse
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		chunks = append(chunks, chunk)
+		if s, ok := chunk.Choices[0].Delta.Content.(string); ok {
+			content.WriteString(s)
+		}
+	}
+
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+	if content.String() != "Hello" {
+		t.Errorf("expected content 'Hello', got %q", content.String())
+	}
+	if chunks[0].Choices[0].Delta.Role != "assistant" {
+		t.Errorf("expected role 'assistant', got %q", chunks[0].Choices[0].Delta.Role)
+	}
+
+	last := chunks[2].Choices[0]
+	if last.FinishReason != "tool_calls" {
+		t.Errorf("expected finish_reason 'tool_calls', got %q", last.FinishReason)
+	}
+	if len(last.Delta.ToolCalls) != 1 || last.Delta.ToolCalls[0].Index == nil || *last.Delta.ToolCalls[0].Index != 0 {
+		t.Errorf("unexpected tool call delta: %+v", last.Delta.ToolCalls)
+	}
+
+	// 结束后再次调用仍然返回 io.EOF
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("expected io.EOF after [DONE], got %v", err)
+	}
+}
+
+func TestChatCompletionStream_RecvError(t *testing.T) {
+	body := "data: {\"error\":{\"message\":\"upstream failed\",\"type\":\"api_error\"}}\n\n"
+
+	client := newFakeStreamClient(body)
+	stream, err := client.ChatCompletionsStream(context.Background(), ChatCompletionRequest{
+		Messages: []ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionsStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	_, err = stream.Recv()
+	if err == nil || !strings.Contains(err.Error(), "upstream failed") {
+		t.Errorf("expected upstream error, got %v", err)
+	}
+}
+
+func TestChatCompletionStream_RecvTruncated(t *testing.T) {
+	body := "data: {\"id\":\"c1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hel\"}}]}\n\n"
+
+	client := newFakeStreamClient(body)
+	stream, err := client.ChatCompletionsStream(context.Background(), ChatCompletionRequest{
+		Messages: []ChatMessage{{Role: "user", Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("ChatCompletionsStream() error = %v", err)
+	}
+	defer stream.Close()
+
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Recv() error = %v", err)
+	}
+	// 没有收到 [DONE] 就结束的流不能被当作正常结束
+	if _, err := stream.Recv(); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected io.ErrUnexpectedEOF without [DONE], got %v", err)
+	}
+}
@@ -2,12 +2,20 @@ package llmhub
 
 // ChatCompletionRequest OpenAI 兼容的请求结构
 type ChatCompletionRequest struct {
-	Model            string               `json:"model"`
-	Messages         []ChatMessage        `json:"messages"`
-	Temperature      *float64             `json:"temperature,omitempty"`
-	TopP             *float64             `json:"top_p,omitempty"`
-	MaxTokens        *int                 `json:"max_tokens,omitempty"`
+	Model       string        `json:"model"`
+	Messages    []ChatMessage `json:"messages"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	TopP        *float64      `json:"top_p,omitempty"`
+	MaxTokens   *int          `json:"max_tokens,omitempty"`
+	// MaxCompletionTokens 输出 token 上限（含推理模型的思考过程），OpenAI o 系列等推理模型需使用该字段代替 MaxTokens
+	MaxCompletionTokens *int `json:"max_completion_tokens,omitempty"`
+	// ReasoningEffort 思考强度，可选 "minimal"、"low"、"medium"、"high"
+	// "minimal" 沿用 OpenAI 的取值，可以关闭思考的模型关闭思考，否则使用最小思考预算
+	// 各适配器会转换为对应的参数：OpenAI reasoning_effort、Claude thinking.budget_tokens、
+	// Gemini thinkingConfig.thinkingBudget、Qwen enable_thinking 等
+	ReasoningEffort  string               `json:"reasoning_effort,omitempty"`
 	Stream           bool                 `json:"stream,omitempty"`
+	StreamOptions    *StreamOptions       `json:"stream_options,omitempty"`
 	PresencePenalty  *float64             `json:"presence_penalty,omitempty"`
 	FrequencyPenalty *float64             `json:"frequency_penalty,omitempty"`
 	Stop             []string             `json:"stop,omitempty"`
@@ -21,6 +29,17 @@ type ChatCompletionRequest struct {
 	Seed             *int                 `json:"seed,omitempty"`
 	Tools            []Tool               `json:"tools,omitempty"`
 	ToolChoice       interface{}          `json:"tool_choice,omitempty"`
+	// KeepAlive Ollama 专用，请求结束后模型在内存中的保留时长，如 "5m"、"0"、"-1"
+	KeepAlive string `json:"keep_alive,omitempty"`
+	// Thinking Claude 专用，开启扩展思考并设置思考的 token 预算
+	Thinking *ThinkingConfig `json:"thinking,omitempty"`
+}
+
+// ThinkingConfig 扩展思考配置
+// Type 为 "enabled" 或 "disabled"，BudgetTokens 为思考可使用的最大 token 数（Claude 要求不少于 1024）
+type ThinkingConfig struct {
+	Type         string `json:"type"`
+	BudgetTokens int    `json:"budget_tokens,omitempty"`
 }
 
 // ChatMessage 聊天消息
@@ -31,6 +50,68 @@ type ChatMessage struct {
 	FunctionCall *FunctionCall `json:"function_call,omitempty"`
 	ToolCalls    []ToolCall    `json:"tool_calls,omitempty"`
 	ToolCallID   string        `json:"tool_call_id,omitempty"`
+	// Citations 回答引用的来源，目前由 Cohere 等支持引用的提供商返回
+	Citations []Citation `json:"citations,omitempty"`
+	// ReasoningContent 推理模型的思考过程（Claude thinking、DeepSeek reasoning_content、<think> 标签等）
+	ReasoningContent string `json:"reasoning_content,omitempty"`
+	// ThinkingBlocks Claude 返回的带签名的思考块，多轮工具调用时需随 assistant 消息原样传回
+	ThinkingBlocks []ThinkingBlock `json:"thinking_blocks,omitempty"`
+}
+
+// ContentPart 多模态消息内容片段
+// ChatMessage.Content 可以是字符串，也可以是 []ContentPart，用于发送图片和文档
+// 建议使用 TextPart、ImageURLPart、ImageDataPart、PDFPart、TextDocumentPart 构造
+type ContentPart struct {
+	// Type 为 "text"、"image_url" 或 "document"
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+	Document *Document `json:"document,omitempty"`
+}
+
+// ImageURL 图片地址
+// URL 可以是 http(s) 地址，也可以是 data:image/png;base64,... 形式的 data URI
+// Detail 为 "auto"、"low" 或 "high"，目前仅 OpenAI 使用
+type ImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Document 文档输入，目前支持 Claude、Gemini、Bedrock 和 Vertex AI，其他提供商会返回错误
+type Document struct {
+	// MediaType 为 "application/pdf" 或 "text/plain"
+	MediaType string `json:"media_type"`
+	// Data PDF 为 base64 编码的文件内容，text/plain 为文本本身
+	Data  string `json:"data"`
+	Title string `json:"title,omitempty"`
+	// Citations Claude 专用，为 true 时回答会引用文档原文，引用位置通过 ChatMessage.Citations 返回
+	Citations bool `json:"citations,omitempty"`
+}
+
+// ThinkingBlock 思考块
+// Type 为 "thinking" 时 Thinking 为思考内容、Signature 为签名；为 "redacted_thinking" 时 Data 为加密内容
+type ThinkingBlock struct {
+	Type      string `json:"type"`
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Data      string `json:"data,omitempty"`
+}
+
+// Citation 回答中的引用，Start/End 为引用文本在 Content 中的字符位置
+type Citation struct {
+	Start   int              `json:"start"`
+	End     int              `json:"end"`
+	Text    string           `json:"text"`
+	Sources []CitationSource `json:"sources,omitempty"`
+}
+
+// CitationSource 引用来源
+// Type 为 "document"（请求中提供的文档）或 "tool"（工具调用结果），Data 为来源的原始内容
+type CitationSource struct {
+	Type  string                 `json:"type"`
+	ID    string                 `json:"id,omitempty"`
+	Title string                 `json:"title,omitempty"`
+	Data  map[string]interface{} `json:"data,omitempty"`
 }
 
 // FunctionDefinition 函数定义
@@ -48,6 +129,7 @@ type FunctionCall struct {
 
 // ToolCall 工具调用
 type ToolCall struct {
+	Index    *int         `json:"index,omitempty"` // 流式响应中用于合并工具调用片段
 	ID       string       `json:"id"`
 	Type     string       `json:"type"`
 	Function FunctionCall `json:"function"`
@@ -59,9 +141,25 @@ type Tool struct {
 	Function FunctionDefinition `json:"function"`
 }
 
+// StreamOptions 流式响应选项
+type StreamOptions struct {
+	// IncludeUsage 为 true 时，流结束前额外发送一个 choices 为空、携带 usage 的响应块
+	IncludeUsage bool `json:"include_usage,omitempty"`
+}
+
 // ResponseFormat 响应格式
+// Type 为 "text"、"json_object" 或 "json_schema"，为 "json_schema" 时需设置 JSONSchema
 type ResponseFormat struct {
-	Type string `json:"type"`
+	Type       string      `json:"type"`
+	JSONSchema *JSONSchema `json:"json_schema,omitempty"`
+}
+
+// JSONSchema 结构化输出的 JSON Schema 定义
+type JSONSchema struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Schema      interface{} `json:"schema,omitempty"`
+	Strict      bool        `json:"strict,omitempty"`
 }
 
 // ChatCompletionResponse OpenAI 兼容的响应结构
@@ -81,6 +179,17 @@ type ChatCompletionChoice struct {
 	Message      ChatMessage  `json:"message"`
 	FinishReason string       `json:"finish_reason"`
 	Delta        *ChatMessage `json:"delta,omitempty"` // 用于流式响应
+	// ContentFilterResults Azure OpenAI 的内容过滤结果，key 为过滤类别，如 "hate"、"sexual"、"jailbreak"
+	// 回答被过滤时 FinishReason 为 "content_filter"
+	ContentFilterResults map[string]ContentFilterResult `json:"content_filter_results,omitempty"`
+}
+
+// ContentFilterResult 单个类别的内容过滤结果
+// Severity 为 "safe"、"low"、"medium" 或 "high"；jailbreak、profanity 等检测类过滤器使用 Detected
+type ContentFilterResult struct {
+	Filtered bool   `json:"filtered"`
+	Severity string `json:"severity,omitempty"`
+	Detected bool   `json:"detected,omitempty"`
 }
 
 // Usage Token 使用情况